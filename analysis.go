@@ -0,0 +1,182 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// rebuttalMarkers are phrases that signal a turn is answering its opponent
+var rebuttalMarkers = []string{
+	"you said",
+	"you claim",
+	"you argue",
+	"you suggest",
+	"my opponent",
+	"i disagree",
+	"on the contrary",
+	"contrary to",
+	"that's not",
+	"that is not",
+	"is simply wrong",
+}
+
+// agreementMarkers are phrases that signal a turn accepts the opponent's view
+var agreementMarkers = []string{
+	"i agree",
+	"we agree",
+	"you're right",
+	"you are right",
+	"i concede",
+	"you've convinced me",
+	"i'm persuaded",
+	"i am persuaded",
+	"common ground",
+	"we have reached",
+	"we've reached",
+}
+
+// contrastWords turn an agreement into a "yes, but" rebuttal
+var contrastWords = []string{"but", "however", "although", "though", "yet", "while"}
+
+// disagreementMarkers are phrases that rule out consensus anywhere in a turn
+var disagreementMarkers = []string{
+	"i disagree",
+	"i don't agree",
+	"i do not agree",
+	"on the contrary",
+	"is simply wrong",
+}
+
+// negationWords are words that flip the meaning of a nearby key term
+var negationWords = map[string]bool{
+	"not": true, "no": true, "never": true, "wrong": true, "false": true,
+	"isn't": true, "aren't": true, "doesn't": true, "don't": true, "won't": true,
+	"can't": true, "cannot": true,
+}
+
+// clashWindow is how many words either side of a negation are searched for
+// a key term borrowed from the previous turn
+const clashWindow = 3
+
+// minKeyTermLength is the shortest word treated as a key term
+const minKeyTermLength = 5
+
+// detectClash reports whether curr directly rebuts prev. It is a lightweight
+// heuristic: a turn clashes when it uses a rebuttal phrase, quotes the previous
+// turn, or negates a key term the previous turn used.
+func detectClash(prev, curr Turn) bool {
+	if prev.ModelName == curr.ModelName {
+		return false
+	}
+	if strings.TrimSpace(prev.Content) == "" || strings.TrimSpace(curr.Content) == "" {
+		return false
+	}
+
+	prevLower := strings.ToLower(prev.Content)
+	currLower := strings.ToLower(curr.Content)
+
+	// Explicit rebuttal phrases
+	for _, marker := range rebuttalMarkers {
+		if strings.Contains(currLower, marker) {
+			return true
+		}
+	}
+
+	// Quoting the previous turn
+	for _, quote := range quotedPhrases(currLower) {
+		if len(quote) >= minKeyTermLength && strings.Contains(prevLower, quote) {
+			return true
+		}
+	}
+
+	// Negation near a key term from the previous turn
+	keyTerms := make(map[string]bool)
+	for _, word := range words(prevLower) {
+		if len(word) >= minKeyTermLength {
+			keyTerms[word] = true
+		}
+	}
+	currWords := words(currLower)
+	for i, word := range currWords {
+		if !negationWords[word] {
+			continue
+		}
+		for j := max(0, i-clashWindow); j <= min(len(currWords)-1, i+clashWindow); j++ {
+			if keyTerms[currWords[j]] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// words splits text into lowercase words, keeping apostrophes so that
+// contractions like "isn't" stay intact
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// quotedPhrases returns the text between each pair of double quotes
+func quotedPhrases(text string) []string {
+	var phrases []string
+	parts := strings.Split(text, "\"")
+	for i := 1; i < len(parts); i += 2 {
+		if i < len(parts)-1 {
+			phrases = append(phrases, strings.TrimSpace(parts[i]))
+		}
+	}
+	return phrases
+}
+
+// detectConsensus reports whether a turn expresses agreement with the opponent.
+// A turn reaches consensus when one of its sentences agrees without a
+// contrasting "but"/"however", and no part of it voices disagreement.
+func detectConsensus(turn Turn) bool {
+	lower := strings.ToLower(turn.Content)
+
+	for _, marker := range disagreementMarkers {
+		if strings.Contains(lower, marker) {
+			return false
+		}
+	}
+
+	for _, sentence := range sentences(lower) {
+		if !containsAny(sentence, agreementMarkers) {
+			continue
+		}
+
+		contrasted := false
+		for _, word := range words(sentence) {
+			for _, contrast := range contrastWords {
+				if word == contrast {
+					contrasted = true
+				}
+			}
+		}
+		if !contrasted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sentences splits text on sentence-ending punctuation
+func sentences(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == '\n'
+	})
+}
+
+// containsAny reports whether text contains any of the phrases
+func containsAny(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
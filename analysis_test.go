@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestDetectClash(t *testing.T) {
+	prev := Turn{
+		ModelName: "mistral:7b",
+		Content:   "Renewable energy is affordable and will replace fossil fuels within a decade.",
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"rebuttal phrase", "You said renewables are cheap, but storage costs tell another story.", true},
+		{"quoted term", "The claim that it will \"replace fossil fuels\" ignores grid realities.", true},
+		{"negated key term", "Solar power is not affordable for developing nations.", true},
+		{"tangent", "Let us consider the history of the steam engine and its cultural impact.", false},
+		{"shared term without negation", "Renewable energy also creates many local jobs.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			curr := Turn{ModelName: "gemma3:4b", Content: tt.content}
+			if got := detectClash(prev, curr); got != tt.want {
+				t.Errorf("detectClash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectClash_SameModel(t *testing.T) {
+	prev := Turn{ModelName: "mistral:7b", Content: "Taxes should be lower."}
+	curr := Turn{ModelName: "mistral:7b", Content: "I disagree with myself."}
+
+	if detectClash(prev, curr) {
+		t.Errorf("A turn should not clash with the same model's previous turn")
+	}
+}
+
+func TestDetectConsensus(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"agreement", "You're right, a carbon tax is the most efficient tool. I concede the point.", true},
+		{"common ground", "It seems we have reached common ground on funding schools.", true},
+		{"yes but", "I agree that costs matter, but the long-term savings are larger.", false},
+		{"disagreement", "I disagree entirely; the evidence points the other way.", false},
+		{"mixed turn", "I agree on one thing. On the contrary, your main claim fails.", false},
+		{"no agreement", "The data shows a clear decline in emissions.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectConsensus(Turn{Content: tt.content}); got != tt.want {
+				t.Errorf("detectConsensus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import "fmt"
+
+// asyncSinkBuffer is how many events an asyncSink holds before Send waits
+// for the writer to catch up
+const asyncSinkBuffer = 64
+
+// outputWarningMsg reports that the transcript could not be written. The
+// debate carries on and the warning stays in the footer.
+type outputWarningMsg struct {
+	err error
+}
+
+// asyncSink passes events to another sink from a goroutine of its own, in
+// order, so that writing the transcript never holds up the interface
+type asyncSink struct {
+	sink   OutputSink
+	events chan DebateEvent
+	done   chan struct{}
+}
+
+// newAsyncSink starts passing events to sink in the background
+func newAsyncSink(sink OutputSink) *asyncSink {
+	s := &asyncSink{
+		sink:   sink,
+		events: make(chan DebateEvent, asyncSinkBuffer),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		for event := range s.events {
+			s.sink.Send(event)
+		}
+	}()
+	return s
+}
+
+// Send queues event for the sink
+func (s *asyncSink) Send(event DebateEvent) {
+	s.events <- event
+}
+
+// Close waits for the queued events to reach the sink. Nothing may be sent
+// afterwards.
+func (s *asyncSink) Close() {
+	close(s.events)
+	<-s.done
+}
+
+// formatOutputWarning describes a failure to write the transcript for the
+// footer
+func formatOutputWarning(err error) string {
+	return fmt.Sprintf("Transcript not saved: %v", err)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForFile waits for path to contain want, failing after a second
+func waitForFile(t *testing.T, path, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %s to contain %q, got:\n%s", path, want, data)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// streamingModel returns a debate of two turns played from the test fixture,
+// streaming its turns to sink
+func streamingModel(sink OutputSink) *debateModel {
+	return &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(testFixture()),
+		maxTurns:     2,
+		initialTopic: "Is remote work better?",
+		sink:         sink,
+	}
+}
+
+// TestAsyncSink_TurnsReachFileWhileDebating verifies each turn is in the
+// transcript file as soon as it completes, while the debate goes on
+func TestAsyncSink_TurnsReachFileWhileDebating(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debate.txt")
+	transcript := &transcriptSink{path: path, live: true}
+	queue := newAsyncSink(transcript)
+	m := streamingModel(queue)
+
+	completed := 0
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		msg := cmd()
+		_, cmd = m.Update(msg)
+		if _, ok := msg.(responseCompleteMsg); ok {
+			completed++
+			if completed == 1 {
+				if !m.isGenerating || cmd == nil {
+					t.Fatal("Expected the debate to go on after the first turn")
+				}
+				waitForFile(t, path, "Remote work wins.")
+			}
+		}
+	}
+	queue.Close()
+	if err := transcript.finish(m.topic, m.exportHistory()); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+
+	waitForFile(t, path, "Offices win.")
+	if m.outputWarning != "" {
+		t.Errorf("Expected no warning, got %q", m.outputWarning)
+	}
+}
+
+// TestAsyncSink_WriteErrorWarns verifies a transcript that cannot be written
+// leaves a warning in the footer without stopping the debate
+func TestAsyncSink_WriteErrorWarns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "debate.txt")
+	transcript := &transcriptSink{path: path, live: true}
+	warnings := make(chan error, 1)
+	transcript.onError = func(err error) { warnings <- err }
+	queue := newAsyncSink(transcript)
+	m := streamingModel(queue)
+
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+	queue.Close()
+
+	select {
+	case err := <-warnings:
+		m.Update(outputWarningMsg{err: err})
+	default:
+		t.Fatal("Expected the write error to be reported")
+	}
+	if len(m.history) != 2 {
+		t.Errorf("Expected the debate to finish both turns, got %d", len(m.history))
+	}
+	m.width, m.height = 200, 40
+	if footer := m.renderFooter(); !strings.Contains(footer, "Transcript not saved") {
+		t.Errorf("Expected the footer to warn about the transcript, got:\n%s", footer)
+	}
+	if transcript.finish(m.topic, m.exportHistory()) == nil {
+		t.Error("Expected finish to still report the failure")
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autosaveTickMsg is sent when the transcript is due to be saved again
+type autosaveTickMsg struct{}
+
+// autosaveTick returns a command that triggers the next autosave, or nil
+// when autosaving is off
+func (m *debateModel) autosaveTick() tea.Cmd {
+	if m.autosaveInterval <= 0 || m.autosavePath == "" {
+		return nil
+	}
+	return tea.Tick(m.autosaveInterval, func(time.Time) tea.Msg {
+		return autosaveTickMsg{}
+	})
+}
+
+// autosave writes the turns finished so far to the autosave path. A turn
+// still streaming is left for the next save.
+func (m *debateModel) autosave() error {
+	history := m.exportHistory()
+	if m.isGenerating && m.turnStarted && len(history) > 0 {
+		history = history[:len(history)-1]
+	}
+	if len(history) == 0 {
+		return nil
+	}
+	return ExportFileAtomic(m.autosavePath, m.topic, m.exportMeta(), history, m.timestamps)
+}
+
+// ExportFileAtomic writes the debate to path like ExportFile, but through a
+// temporary file in the same directory that is renamed over path once
+// complete, so a crash mid-write never leaves a truncated transcript
+func ExportFileAtomic(path, topic string, meta *DebateMeta, history []Turn, stamps timestampFormat) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := exportFormat(path, topic, meta, history, stamps, f); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := f.Chmod(0o644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAutosave_FiresOnSchedule verifies the autosave tick arrives after the
+// interval and writes a transcript that loads back
+func TestAutosave_FiresOnSchedule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debate.jsonl")
+	m := &debateModel{
+		state:            stateDebating,
+		topic:            "Should homework be banned?",
+		autosavePath:     path,
+		autosaveInterval: 20 * time.Millisecond,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Yes.", Timestamp: time.Now()},
+			{ModelName: "gemma3:4b", Content: "No.", Timestamp: time.Now()},
+		},
+	}
+
+	cmd := m.autosaveTick()
+	if cmd == nil {
+		t.Fatal("Expected an autosave tick to be scheduled")
+	}
+	began := time.Now()
+	msg := cmd()
+	if _, ok := msg.(autosaveTickMsg); !ok {
+		t.Fatalf("Expected autosaveTickMsg, got %T", msg)
+	}
+	if elapsed := time.Since(began); elapsed < m.autosaveInterval {
+		t.Errorf("Expected the tick after %s, got it after %s", m.autosaveInterval, elapsed)
+	}
+
+	if _, next := m.Update(msg); next == nil {
+		t.Error("Expected the next autosave to be scheduled")
+	}
+	topic, history, err := LoadTranscript(path)
+	if err != nil {
+		t.Fatalf("Expected a valid transcript, got %v", err)
+	}
+	if topic != m.topic || len(history) != 2 || history[1].Content != "No." {
+		t.Errorf("Unexpected transcript %q %+v", topic, history)
+	}
+
+	// Only the transcript is left behind, with no temporary files
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the transcript in the directory, got %d entries", len(entries))
+	}
+}
+
+// TestAutosave_SkipsStreamingTurn verifies a turn still being generated is
+// left out of the save
+func TestAutosave_SkipsStreamingTurn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debate.txt")
+	m := &debateModel{
+		topic:        "Should homework be banned?",
+		autosavePath: path,
+		isGenerating: true,
+		turnStarted:  true,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Yes."},
+			{ModelName: "gemma3:4b", Content: "Half a sent"},
+		},
+	}
+	if err := m.autosave(); err != nil {
+		t.Fatalf("autosave failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the transcript to be written: %v", err)
+	}
+	if got := string(data); !strings.Contains(got, "Yes.") || strings.Contains(got, "Half a sent") {
+		t.Errorf("Expected only the finished turn, got:\n%s", got)
+	}
+}
+
+// TestAutosave_FailureWarnsWithoutHidingError verifies a failed autosave
+// is shown as a footer warning, leaving a failed turn's error in place, and
+// is cleared once a save succeeds
+func TestAutosave_FailureWarnsWithoutHidingError(t *testing.T) {
+	dir := t.TempDir()
+	m := &debateModel{
+		state:            stateError,
+		errorMsg:         "model not found",
+		topic:            "Should homework be banned?",
+		autosavePath:     filepath.Join(dir, "missing", "debate.txt"),
+		autosaveInterval: time.Minute,
+		history:          []Turn{{ModelName: "mistral:7b", Content: "Yes."}},
+	}
+
+	m.Update(autosaveTickMsg{})
+	if m.errorMsg != "model not found" {
+		t.Errorf("Expected the turn error kept, got %q", m.errorMsg)
+	}
+	if !strings.Contains(m.autosaveWarning, "Autosave failed") || !strings.Contains(m.renderFooter(), "Autosave failed") {
+		t.Errorf("Expected the failure warned of in the footer, got %q", m.autosaveWarning)
+	}
+
+	m.autosavePath = filepath.Join(dir, "debate.txt")
+	m.Update(autosaveTickMsg{})
+	if m.autosaveWarning != "" {
+		t.Errorf("Expected the warning cleared by a successful save, got %q", m.autosaveWarning)
+	}
+}
+
+func TestAutosave_DisabledWithoutInterval(t *testing.T) {
+	m := &debateModel{autosavePath: "debate.txt"}
+	if m.autosaveTick() != nil {
+		t.Error("Expected no autosave tick without an interval")
+	}
+}
+
+// TestExportFileAtomic_ReplacesFile verifies an existing file is replaced
+// whole and keeps readable permissions
+func TestExportFileAtomic_ReplacesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debate.txt")
+	if err := os.WriteFile(path, []byte("old transcript that is much longer than the new one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportFileAtomic(path, "Topic", nil, []Turn{{ModelName: "mistral:7b", Content: "New."}}, timestampFormat{}); err != nil {
+		t.Fatalf("ExportFileAtomic failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "old transcript") || !strings.Contains(string(data), "New.") {
+		t.Errorf("Expected the file replaced, got:\n%s", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o644 {
+		t.Errorf("Expected mode 0644, got %v (%v)", info.Mode().Perm(), err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultBackpressureThreshold is how long handing a chunk to the consumer
+// may block before it is reported as backpressure
+const defaultBackpressureThreshold = 100 * time.Millisecond
+
+// maxDebugLogEntries is how many backpressure entries the debug pane keeps
+const maxDebugLogEntries = 5
+
+// backpressureMsg is sent when a chunk from modelName waited blocked for the
+// view to take it
+type backpressureMsg struct {
+	modelName string
+	blocked   time.Duration
+}
+
+// SetBackpressureHandler sets a function called whenever handing a streamed
+// chunk to the consumer blocks for at least threshold, which means the
+// consumer rather than the model is holding the stream up. It may be called
+// from any goroutine. A nil handler disables the timing.
+func (c *OllamaClient) SetBackpressureHandler(threshold time.Duration, handler func(modelName string, blocked time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.backpressureThreshold = threshold
+	c.onBackpressure = handler
+}
+
+// formatBackpressure renders a backpressure entry for the debug log
+func formatBackpressure(modelName string, blocked time.Duration) string {
+	return fmt.Sprintf("backpressure: %s chunk waited %s for the consumer", modelName, blocked.Round(time.Millisecond))
+}
+
+// logBackpressure adds a backpressure entry to the debug log, keeping only
+// the most recent entries
+func (m *debateModel) logBackpressure(msg backpressureMsg) {
+	entry := fmt.Sprintf("%s %s", time.Now().Format("15:04:05.000"), formatBackpressure(msg.modelName, msg.blocked))
+	m.debugLog = append(m.debugLog, entry)
+	if len(m.debugLog) > maxDebugLogEntries {
+		m.debugLog = m.debugLog[len(m.debugLog)-maxDebugLogEntries:]
+	}
+}
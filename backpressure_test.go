@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newChunkServer streams n one-word chunks and then finishes
+func newChunkServer(t *testing.T, n int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+		for i := 0; i < n; i++ {
+			encoder.Encode(GenerateResponse{Response: fmt.Sprintf("word%d ", i)})
+		}
+		encoder.Encode(GenerateResponse{Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSetBackpressureHandler_SlowConsumer(t *testing.T) {
+	server := newChunkServer(t, 3)
+
+	var mu sync.Mutex
+	var entries []string
+	client := NewOllamaClient(server.URL)
+	client.SetBackpressureHandler(10*time.Millisecond, func(modelName string, blocked time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, formatBackpressure(modelName, blocked))
+	})
+
+	// Read each chunk well after it is ready, like a view busy rendering
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	for {
+		time.Sleep(30 * time.Millisecond)
+		if _, ok := <-responseChan; !ok {
+			break
+		}
+	}
+	if err := <-errorChan; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(entries) == 0 {
+		t.Fatal("Expected a slow consumer to produce backpressure entries")
+	}
+	if !strings.Contains(entries[0], "backpressure: mistral:7b chunk waited") {
+		t.Errorf("Unexpected entry %q", entries[0])
+	}
+}
+
+func TestSetBackpressureHandler_FastConsumer(t *testing.T) {
+	server := newChunkServer(t, 3)
+
+	calls := 0
+	client := NewOllamaClient(server.URL)
+	client.SetBackpressureHandler(time.Second, func(string, time.Duration) { calls++ })
+
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	for range responseChan {
+	}
+	<-errorChan
+
+	if calls != 0 {
+		t.Errorf("Expected no backpressure entries for a fast consumer, got %d", calls)
+	}
+}
+
+func TestBackpressureMsg_DebugLog(t *testing.T) {
+	m := &debateModel{state: stateDebating}
+	m.Update(backpressureMsg{modelName: "mistral:7b", blocked: 250 * time.Millisecond})
+	if len(m.debugLog) != 0 {
+		t.Errorf("Expected nothing logged without -debug, got %v", m.debugLog)
+	}
+
+	m.debug = true
+	for i := 0; i < maxDebugLogEntries+2; i++ {
+		m.Update(backpressureMsg{modelName: fmt.Sprintf("model%d", i), blocked: 250 * time.Millisecond})
+	}
+	if len(m.debugLog) != maxDebugLogEntries {
+		t.Fatalf("Expected the log capped at %d entries, got %d", maxDebugLogEntries, len(m.debugLog))
+	}
+	if !strings.Contains(m.debugLog[0], "model2") {
+		t.Errorf("Expected the oldest entries dropped, got %q first", m.debugLog[0])
+	}
+
+	pane := m.renderDebugPane(120)
+	if !strings.Contains(pane, "model6 chunk waited 250ms") {
+		t.Errorf("Expected the debug pane to show the latest entry, got:\n%s", pane)
+	}
+}
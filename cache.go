@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// cachingGenerator answers a prompt it has answered before with the same
+// response, and passes any other to next, remembering what it says. Entries
+// are keyed by model, prompt and options, so changing any of them misses.
+// Only responses that complete are cached. It is safe for concurrent use.
+type cachingGenerator struct {
+	next Generator
+
+	// options returns the options next sends for a model before overrides,
+	// or is nil when it sends none
+	options func(modelName string) map[string]interface{}
+
+	// dir, when set, keeps responses as files so they outlive the run
+	dir string
+
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// newCachingGenerator returns a cache in front of next, kept in dir when it
+// is not empty. dir is created if needed.
+func newCachingGenerator(next Generator, options func(modelName string) map[string]interface{}, dir string) (*cachingGenerator, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+	}
+	return &cachingGenerator{next: next, options: options, dir: dir, entries: make(map[string]string)}, nil
+}
+
+// cacheKey identifies a request by a hash of its model, prompt and options.
+// Options are encoded with sorted keys, so their order does not matter.
+func cacheKey(modelName, prompt string, options map[string]interface{}) string {
+	data, _ := json.Marshal(struct {
+		Model   string                 `json:"model"`
+		Prompt  string                 `json:"prompt"`
+		Options map[string]interface{} `json:"options,omitempty"`
+	}{modelName, prompt, options})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// key returns the cache key of a request, taking the options next would
+// send with overrides applied
+func (g *cachingGenerator) key(modelName, prompt string, overrides map[string]interface{}) string {
+	options := make(map[string]interface{})
+	if g.options != nil {
+		for key, value := range g.options(modelName) {
+			options[key] = value
+		}
+	}
+	for key, value := range overrides {
+		options[key] = value
+	}
+	return cacheKey(modelName, prompt, options)
+}
+
+// path returns the file keeping the response for key
+func (g *cachingGenerator) path(key string) string {
+	return filepath.Join(g.dir, key+".txt")
+}
+
+// lookup returns the cached response for key, from memory or else disk
+func (g *cachingGenerator) lookup(key string) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if response, ok := g.entries[key]; ok {
+		return response, true
+	}
+	if g.dir == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(g.path(key))
+	if err != nil {
+		return "", false
+	}
+	g.entries[key] = string(data)
+	return string(data), true
+}
+
+// store remembers response for key. The cache only saves work, so failing
+// to write it to disk is not an error: the response is still kept in
+// memory for the rest of the run.
+func (g *cachingGenerator) store(key, response string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.entries[key] = response
+	if g.dir == "" {
+		return
+	}
+
+	// Write to a temporary file first so a reader never sees half an entry
+	tmp, err := os.CreateTemp(g.dir, ".entry-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.WriteString(response)
+	if closeErr := tmp.Close(); writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if os.Rename(tmp.Name(), g.path(key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// GenerateResponseWithOptions streams the cached response to a request seen
+// before as a single chunk, and otherwise streams next's response while
+// caching it
+func (g *cachingGenerator) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	key := g.key(modelName, prompt, overrides)
+	responseChan := make(chan string)
+	errorChan := make(chan error, 1)
+
+	if response, ok := g.lookup(key); ok {
+		go func() {
+			defer close(responseChan)
+			defer close(errorChan)
+			select {
+			case responseChan <- response:
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+			}
+		}()
+		return responseChan, errorChan
+	}
+
+	upstream, upstreamErrs := g.next.GenerateResponseWithOptions(ctx, modelName, prompt, overrides)
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		var b strings.Builder
+		for chunk := range upstream {
+			b.WriteString(chunk)
+			select {
+			case responseChan <- chunk:
+			case <-ctx.Done():
+				// Let next wind down before reporting the cancellation
+				drainGeneration(upstream, upstreamErrs)
+				errorChan <- ctx.Err()
+				return
+			}
+		}
+		if err := <-upstreamErrs; err != nil {
+			errorChan <- err
+			return
+		}
+		g.store(key, b.String())
+	}()
+	return responseChan, errorChan
+}
+
+// OptionsFor returns the options sent with a request to modelName, before
+// any per-request overrides
+func (c *OllamaClient) OptionsFor(modelName string) map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.requestOptions(modelName)
+}
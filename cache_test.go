@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// countingGenerator answers every request with the same text, counting the
+// requests it is sent, or fails with err when set
+type countingGenerator struct {
+	mu       sync.Mutex
+	requests int
+	text     string
+	err      error
+}
+
+func (g *countingGenerator) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	g.mu.Lock()
+	g.requests++
+	g.mu.Unlock()
+
+	responseChan := make(chan string, 2)
+	errorChan := make(chan error, 1)
+	responseChan <- g.text[:len(g.text)/2]
+	responseChan <- g.text[len(g.text)/2:]
+	close(responseChan)
+	if g.err != nil {
+		errorChan <- g.err
+	}
+	close(errorChan)
+	return responseChan, errorChan
+}
+
+func generate(t *testing.T, g Generator, modelName, prompt string, overrides map[string]interface{}) string {
+	t.Helper()
+	content, err := collectResponseWithOptions(context.Background(), g, modelName, prompt, overrides, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return content
+}
+
+func TestCachingGenerator_HitAndMiss(t *testing.T) {
+	next := &countingGenerator{text: "Remote work wins."}
+	cache, err := newCachingGenerator(next, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := generate(t, cache, "phi3:mini", "Argue.", nil); got != "Remote work wins." {
+			t.Errorf("Expected the response, got %q", got)
+		}
+	}
+	if next.requests != 1 {
+		t.Errorf("Expected one request for a repeated prompt, got %d", next.requests)
+	}
+
+	generate(t, cache, "phi3:mini", "Argue again.", nil)
+	generate(t, cache, "gemma3:4b", "Argue.", nil)
+	if next.requests != 3 {
+		t.Errorf("Expected another prompt and another model to miss, got %d requests", next.requests)
+	}
+}
+
+func TestCachingGenerator_OptionsMiss(t *testing.T) {
+	next := &countingGenerator{text: "Offices win."}
+	temperature := 0.3
+	cache, err := newCachingGenerator(next, func(string) map[string]interface{} {
+		return map[string]interface{}{"temperature": temperature}
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generate(t, cache, "phi3:mini", "Argue.", nil)
+	generate(t, cache, "phi3:mini", "Argue.", map[string]interface{}{"num_predict": 60})
+	if next.requests != 2 {
+		t.Errorf("Expected differing overrides to miss, got %d requests", next.requests)
+	}
+
+	temperature = 0.9
+	generate(t, cache, "phi3:mini", "Argue.", nil)
+	if next.requests != 3 {
+		t.Errorf("Expected differing client options to miss, got %d requests", next.requests)
+	}
+
+	generate(t, cache, "phi3:mini", "Argue.", map[string]interface{}{"num_predict": 60})
+	if next.requests != 4 {
+		t.Errorf("Expected the new temperature with the overrides to miss too, got %d requests", next.requests)
+	}
+}
+
+func TestCacheKey_OptionOrder(t *testing.T) {
+	a := cacheKey("phi3:mini", "Argue.", map[string]interface{}{"temperature": 0.3, "seed": 7})
+	b := cacheKey("phi3:mini", "Argue.", map[string]interface{}{"seed": 7, "temperature": 0.3})
+	if a != b {
+		t.Error("Expected the same key whatever the order of the options")
+	}
+}
+
+func TestCachingGenerator_FailuresNotCached(t *testing.T) {
+	next := &countingGenerator{text: "Partial", err: errors.New("connection reset")}
+	cache, err := newCachingGenerator(next, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := collectResponse(context.Background(), cache, "phi3:mini", "Argue.", nil); err == nil {
+			t.Error("Expected the failure to be passed on")
+		}
+	}
+	if next.requests != 2 {
+		t.Errorf("Expected a failed response to be asked for again, got %d requests", next.requests)
+	}
+}
+
+func TestCachingGenerator_Dir(t *testing.T) {
+	dir := t.TempDir()
+	next := &countingGenerator{text: "Remote work wins."}
+	first, err := newCachingGenerator(next, nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generate(t, first, "phi3:mini", "Argue.", nil)
+
+	// A later run finds the response on disk
+	second, err := newCachingGenerator(next, nil, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := generate(t, second, "phi3:mini", "Argue.", nil); got != "Remote work wins." {
+		t.Errorf("Expected the response from disk, got %q", got)
+	}
+	if next.requests != 1 {
+		t.Errorf("Expected the second run to hit the cache on disk, got %d requests", next.requests)
+	}
+}
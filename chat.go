@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Chat message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// latestExchangeTurns is how many of the most recent turns -history-as-system
+// still sends as messages of their own
+const latestExchangeTurns = 2
+
+// ChatMessage is one message of a conversation sent to Ollama's chat API
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest represents the request body for Ollama's chat API
+type ChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []ChatMessage          `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+// GenerateChat generates a streaming reply to messages from Ollama's chat
+// API, like GenerateResponseWithOptions does for a prompt
+func (c *OllamaClient) GenerateChat(ctx context.Context, modelName string, messages []ChatMessage, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	return c.stream(ctx, "api/chat", modelName, overrides, func(options map[string]interface{}) interface{} {
+		return ChatRequest{
+			Model:    modelName,
+			Messages: messages,
+			Stream:   true,
+			Options:  options,
+		}
+	})
+}
+
+// BuildChatMessages builds the messages asking currentModel, addressed as
+// displayName, for its next turn. The instructions of the debate prompt
+// become the system message and the history follows as messages, the
+// model's own turns as the assistant's and everyone else's as the user's.
+// With historyAsSystem, all but the latest exchange is instead folded into
+// the system message as a "Previous discussion" section. A closing user
+// message asks for the turn.
+func BuildChatMessages(topic string, history []Turn, currentModel, displayName string, isFirstTurn bool, opts PromptOptions, historyAsSystem bool) []ChatMessage {
+	msgs := messagesFor(opts.Language)
+
+	var folded []Turn
+	recent := history
+	if historyAsSystem && len(history) > latestExchangeTurns {
+		folded = history[:len(history)-latestExchangeTurns]
+		recent = history[len(history)-latestExchangeTurns:]
+	}
+
+	system := buildDebatePrompt(topic, history, folded, displayName, isFirstTurn, opts, false)
+	messages := []ChatMessage{{Role: RoleSystem, Content: strings.TrimSpace(system)}}
+
+	for _, turn := range recent {
+		if turn.ModelName == currentModel && !turn.Moderator {
+			messages = append(messages, ChatMessage{Role: RoleAssistant, Content: turn.Content})
+			continue
+		}
+		messages = append(messages, ChatMessage{
+			Role:    RoleUser,
+			Content: fmt.Sprintf("[%s]: %s", turn.Speaker(), turn.Content),
+		})
+	}
+
+	// Ask for the turn
+	instruction := msgs.NextArgument
+	if !hasArgument(history) {
+		instruction = msgs.OpeningArgument
+	}
+	if opts.Rebut != "" {
+		instruction += "\n" + fmt.Sprintf(msgs.Rebut, opts.Rebut)
+	}
+	if opts.LengthLimit > 0 {
+		instruction += "\n" + fmt.Sprintf(msgs.Concise, opts.LengthLimit, opts.LengthLimit)
+	}
+	if opts.ResponsePrefix != "" {
+		instruction += "\n" + fmt.Sprintf(msgs.ResponsePrefix, opts.ResponsePrefix)
+	}
+	return append(messages, ChatMessage{Role: RoleUser, Content: instruction})
+}
+
+// formatChatMessages renders messages as text for the prompt pane and
+// echoed prompts
+func formatChatMessages(messages []ChatMessage) string {
+	parts := make([]string, len(messages))
+	for i, message := range messages {
+		parts[i] = fmt.Sprintf("[%s]\n%s", message.Role, message.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// chatGenerator sends a fixed conversation to the chat API in place of the
+// prompt it is given
+type chatGenerator struct {
+	client   *OllamaClient
+	messages []ChatMessage
+}
+
+// GenerateResponseWithOptions streams the reply to the generator's
+// messages. The prompt is ignored.
+func (g chatGenerator) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	return g.client.GenerateChat(ctx, modelName, g.messages, overrides)
+}
+
+// chatMessages builds the messages asking modelName for the turn at
+// turnIndex, with the same options as promptFor
+func (m *debateModel) chatMessages(modelName string, turnIndex int) []ChatMessage {
+	opts := m.turnPromptOptions(modelName, turnIndex)
+	return BuildChatMessages(m.topic, m.promptHistory(), modelName, m.displayName(modelName), !m.hasSpoken(modelName), opts, m.historyAsSystem)
+}
+
+// turnBackend returns what generates modelName's next argument: a chat
+// generator in chat mode, and the usual backend otherwise
+func (m *debateModel) turnBackend(modelName string) Generator {
+	if !m.chat {
+		return m.backend()
+	}
+	return chatGenerator{client: m.ollamaClient, messages: m.chatMessages(modelName, m.argumentCount())}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// chatHistory is a short debate between model-a and model-b
+func chatHistory() []Turn {
+	return []Turn{
+		{ModelName: "model-a", Content: "Opening for A"},
+		{ModelName: "model-b", Content: "Reply from B"},
+		{ModelName: "model-a", Content: "Second point from A"},
+		{ModelName: "model-b", Content: "Rebuttal from B"},
+	}
+}
+
+func roles(messages []ChatMessage) []string {
+	result := make([]string, len(messages))
+	for i, message := range messages {
+		result[i] = message.Role
+	}
+	return result
+}
+
+func TestBuildChatMessages_Default(t *testing.T) {
+	messages := BuildChatMessages("Is coffee healthy?", chatHistory(), "model-a", "model-a", false, PromptOptions{}, false)
+
+	want := []string{RoleSystem, RoleAssistant, RoleUser, RoleAssistant, RoleUser, RoleUser}
+	if got := roles(messages); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected roles %v, got %v", want, got)
+	}
+
+	system := messages[0].Content
+	if !strings.Contains(system, "Is coffee healthy?") {
+		t.Errorf("Expected the topic in the system message, got:\n%s", system)
+	}
+	if strings.Contains(system, "Previous discussion") || strings.Contains(system, "Opening for A") {
+		t.Errorf("Expected no history in the system message, got:\n%s", system)
+	}
+	if messages[1].Content != "Opening for A" {
+		t.Errorf("Expected the model's own turn verbatim, got %q", messages[1].Content)
+	}
+	if messages[2].Content != "[model-b]: Reply from B" {
+		t.Errorf("Expected the opponent's turn labelled with its speaker, got %q", messages[2].Content)
+	}
+	if last := messages[len(messages)-1].Content; !strings.Contains(last, "Provide your next argument") {
+		t.Errorf("Expected the closing message to ask for the next argument, got %q", last)
+	}
+}
+
+func TestBuildChatMessages_HistoryAsSystem(t *testing.T) {
+	messages := BuildChatMessages("Is coffee healthy?", chatHistory(), "model-a", "model-a", false, PromptOptions{}, true)
+
+	want := []string{RoleSystem, RoleAssistant, RoleUser, RoleUser}
+	if got := roles(messages); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected roles %v, got %v", want, got)
+	}
+
+	system := messages[0].Content
+	for _, folded := range []string{"Previous discussion", "[model-a]: Opening for A", "[model-b]: Reply from B"} {
+		if !strings.Contains(system, folded) {
+			t.Errorf("Expected the system message to contain %q, got:\n%s", folded, system)
+		}
+	}
+	for _, recent := range []string{"Second point from A", "Rebuttal from B"} {
+		if strings.Contains(system, recent) {
+			t.Errorf("Expected the latest exchange to stay out of the system message, found %q", recent)
+		}
+	}
+	if messages[1].Content != "Second point from A" || messages[2].Content != "[model-b]: Rebuttal from B" {
+		t.Errorf("Expected the latest exchange as messages, got %q and %q", messages[1].Content, messages[2].Content)
+	}
+}
+
+func TestBuildChatMessages_HistoryAsSystemShortHistory(t *testing.T) {
+	history := chatHistory()[:1]
+	folded := BuildChatMessages("Is coffee healthy?", history, "model-b", "model-b", true, PromptOptions{}, true)
+	plain := BuildChatMessages("Is coffee healthy?", history, "model-b", "model-b", true, PromptOptions{}, false)
+
+	if len(folded) != len(plain) {
+		t.Fatalf("Expected a history within the latest exchange to be sent unchanged, got %d and %d messages", len(folded), len(plain))
+	}
+	for i := range plain {
+		if folded[i] != plain[i] {
+			t.Errorf("Expected message %d to match, got %+v and %+v", i, folded[i], plain[i])
+		}
+	}
+}
+
+func TestBuildChatMessages_Opening(t *testing.T) {
+	messages := BuildChatMessages("Is coffee healthy?", nil, "model-a", "model-a", true, PromptOptions{ResponsePrefix: "CLAIM:"}, false)
+
+	if got := roles(messages); len(got) != 2 || got[1] != RoleUser {
+		t.Fatalf("Expected a system and a closing user message, got %v", got)
+	}
+	last := messages[1].Content
+	if !strings.Contains(last, "Provide your opening argument") || !strings.Contains(last, "CLAIM:") {
+		t.Errorf("Expected the closing message to ask for an opening starting with the prefix, got %q", last)
+	}
+}
+
+func TestGenerateChat(t *testing.T) {
+	var received ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Expected a request to /api/chat, got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		for _, chunk := range []GenerateResponse{
+			{Message: &ChatMessage{Role: RoleAssistant, Content: "Hello"}},
+			{Message: &ChatMessage{Role: RoleAssistant, Content: " there"}, Done: true},
+		} {
+			json.NewEncoder(w).Encode(chunk)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	messages := []ChatMessage{{Role: RoleSystem, Content: "Debate"}, {Role: RoleUser, Content: "Go"}}
+	content, err := collectResponse(context.Background(), chatGenerator{client: client, messages: messages}, "mistral:7b", "ignored", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if content != "Hello there" {
+		t.Errorf("Expected %q, got %q", "Hello there", content)
+	}
+	if received.Model != "mistral:7b" || !received.Stream || len(received.Messages) != 2 || received.Messages[1] != messages[1] {
+		t.Errorf("Expected the messages to be sent as a streaming chat request, got %+v", received)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compareModel shows both models answering the same prompt side by side.
+// Unlike a debate, the two generations run at the same time and neither
+// model sees the other's answer.
+type compareModel struct {
+	client *OllamaClient
+	models [2]string // Model tags, left and right
+	names  [2]string // Display names, left and right
+	topic  string
+	prompt string
+
+	// Per-column generation state
+	answers [2]string
+	done    [2]bool
+	errs    [2]error
+	streams [2]<-chan string
+	errChs  [2]<-chan error
+	cancel  context.CancelFunc
+
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// newCompareModel prepares a comparison of model1 and model2 on topic using
+// the debate's client, display names and prompt options
+func newCompareModel(m *debateModel, topic string) *compareModel {
+	return &compareModel{
+		client: m.ollamaClient,
+		models: [2]string{m.model1Name, m.model2Name},
+		names:  [2]string{m.sideName(0), m.sideName(1)},
+		topic:  topic,
+		prompt: BuildComparePrompt(topic, m.promptOptions),
+		width:  80,
+		height: 24,
+	}
+}
+
+// Init starts both generations and waits for their first chunks
+func (m *compareModel) Init() tea.Cmd {
+	m.viewport = viewport.New(m.width, viewportHeight(m.height, m.width, m.renderHeader(), m.renderFooter()))
+	return m.start()
+}
+
+// start sends the prompt to both models at once
+func (m *compareModel) start() tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	cmds := make([]tea.Cmd, len(m.models))
+	for i, model := range m.models {
+		m.streams[i], m.errChs[i] = m.client.GenerateResponse(ctx, model, m.prompt)
+		cmds[i] = waitForNextChunk(m.streams[i], m.errChs[i])
+	}
+	return tea.Batch(cmds...)
+}
+
+// side returns the column a stream belongs to, or -1 for an unknown stream
+func (m *compareModel) side(stream <-chan string) int {
+	for i, s := range m.streams {
+		if s == stream {
+			return i
+		}
+	}
+	return -1
+}
+
+// finished reports whether both generations have ended
+func (m *compareModel) finished() bool {
+	return m.done[0] && m.done[1]
+}
+
+// Update handles keys, resizes and chunks from either generation
+func (m *compareModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			m.cancel()
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = viewportHeight(msg.Height, msg.Width, m.renderHeader(), m.renderFooter())
+
+	case responseChunkMsg:
+		if i := m.side(msg.responseChan); i >= 0 {
+			m.answers[i] += msg.chunk
+			cmd = waitForNextChunk(msg.responseChan, msg.errorChan)
+		}
+
+	case responseCompleteMsg:
+		if i := m.side(msg.responseChan); i >= 0 {
+			m.done[i] = true
+		}
+
+	case responseErrorMsg:
+		if i := m.side(msg.responseChan); i >= 0 {
+			m.errs[i] = msg.err
+			m.done[i] = true
+		}
+	}
+
+	m.viewport.SetContent(m.renderColumns())
+	var scrollCmd tea.Cmd
+	m.viewport, scrollCmd = m.viewport.Update(msg)
+
+	return m, tea.Batch(cmd, scrollCmd)
+}
+
+// View renders the topic, both columns and the key help
+func (m *compareModel) View() string {
+	var b strings.Builder
+
+	// Size the viewport to what the header and footer leave, since a long
+	// topic wraps the header onto more lines
+	header, footer := m.renderHeader(), m.renderFooter()
+	m.viewport.Height = viewportHeight(m.height, m.width, header, footer)
+
+	b.WriteString(header)
+	b.WriteString("\n")
+	b.WriteString(m.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(footer)
+
+	return b.String()
+}
+
+// renderHeader renders the topic shown above both columns
+func (m *compareModel) renderHeader() string {
+	return headerStyle.Render(fmt.Sprintf("⚖️  Comparing: %s", m.topic))
+}
+
+// renderFooter renders the key help shown below both columns
+func (m *compareModel) renderFooter() string {
+	if m.finished() {
+		return subtleStyle.Render("✓ Both answers complete • ↑/↓ to scroll • 'q' to exit")
+	}
+	return subtleStyle.Render("↑/↓ to scroll • 'q' to stop")
+}
+
+// renderColumns lays out both answers next to each other
+func (m *compareModel) renderColumns() string {
+	columnWidth := m.width / 2
+	left := m.renderColumn(0, model1LabelStyle, model1Style, columnWidth)
+	right := m.renderColumn(1, model2LabelStyle, model2Style, columnWidth)
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
+
+// renderColumn renders one model's label and answer box within width
+func (m *compareModel) renderColumn(i int, labelStyle, boxStyle lipgloss.Style, width int) string {
+	var b strings.Builder
+
+	b.WriteString(labelStyle.Render(m.names[i]))
+	b.WriteString("\n")
+
+	content := m.answers[i]
+	switch {
+	case m.errs[i] != nil:
+		if content != "" {
+			content += "\n\n"
+		}
+		content += fmt.Sprintf("⚠️ %v", m.errs[i])
+	case !m.done[i] && content == "":
+		content = "💭 thinking..."
+	}
+	b.WriteString(boxStyle.Width(contentWidth(boxStyle, width, defaultMinContentWidth)).Render(content))
+
+	return lipgloss.NewStyle().Width(width).Render(b.String())
+}
+
+// shutdown cancels both generations and waits for them to finish, like
+// debateModel.shutdown
+func (m *compareModel) shutdown() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	for i := range m.streams {
+		drainGeneration(m.streams[i], m.errChs[i])
+	}
+}
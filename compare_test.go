@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestCompare_GeneratesBothAtOnce verifies both models receive the identical
+// prompt concurrently and each answer lands in its own column
+func TestCompare_GeneratesBothAtOnce(t *testing.T) {
+	var mu sync.Mutex
+	var prompts []string
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		prompts = append(prompts, req.Prompt)
+		mu.Unlock()
+
+		// Neither model answers until both requests are in flight
+		arrived.Done()
+		waited := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+		case <-time.After(2 * time.Second):
+			t.Error("Expected both generations to run at the same time")
+			return
+		}
+
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "answer from " + req.Model})
+		w.(http.Flusher).Flush()
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true})
+	}))
+	defer server.Close()
+
+	dm := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", model2Alias: "Skeptic", ollamaClient: NewOllamaClient(server.URL)}
+	m := newCompareModel(dm, "Tabs or spaces?")
+	cmd := m.Init()
+	defer m.shutdown()
+
+	// Run commands as Bubbletea would until both generations finish
+	msgs := make(chan tea.Msg, 16)
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, c := range batch {
+					run(c)
+				}
+				return
+			}
+			msgs <- msg
+		}()
+	}
+	run(cmd)
+	for !m.finished() {
+		select {
+		case msg := <-msgs:
+			_, next := m.Update(msg)
+			run(next)
+		case <-time.After(3 * time.Second):
+			t.Fatal("Comparison did not finish")
+		}
+	}
+
+	if len(prompts) != 2 || prompts[0] != prompts[1] || !strings.Contains(prompts[0], "Tabs or spaces?") {
+		t.Errorf("Expected both models to get the same prompt, got %q", prompts)
+	}
+	if m.answers[0] != "answer from mistral:7b" || m.answers[1] != "answer from gemma3:4b" {
+		t.Errorf("Expected each answer in its own column, got %q", m.answers)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	view := m.View()
+	if !strings.Contains(view, "Skeptic") || !strings.Contains(view, "Both answers complete") {
+		t.Errorf("Expected both columns and the completion note in the view")
+	}
+}
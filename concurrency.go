@@ -0,0 +1,19 @@
+package main
+
+// defaultConcurrency is how many generations may stream at once unless
+// -concurrency says otherwise. Two lets compare mode run both models side by
+// side without loading more onto the server than a debate does.
+const defaultConcurrency = 2
+
+// SetConcurrency limits how many generations stream at once. Generations
+// started beyond the limit wait for one to finish. Zero or less removes the
+// limit. Generations already started keep the limit they were started with.
+func (c *OllamaClient) SetConcurrency(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n <= 0 {
+		c.slots = nil
+		return
+	}
+	c.slots = make(chan struct{}, n)
+}
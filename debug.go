@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rawChunkMsg carries the bytes of a streamed chunk as they arrived
+type rawChunkMsg struct {
+	raw []byte
+}
+
+// SetRawChunkHandler sets a function given the bytes of each streamed chunk
+// as they arrived, before invalid UTF-8 is replaced in decoding. It may be
+// called from any goroutine. A nil handler stops the calls.
+func (c *OllamaClient) SetRawChunkHandler(handler func(modelName string, raw []byte)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRawChunk = handler
+}
+
+// formatRawChunk renders the bytes of a streamed chunk for diagnosing
+// encoding problems: the chunk as a Go string literal with everything
+// outside printable ASCII escaped, followed by a hex dump
+func formatRawChunk(chunk string) string {
+	noun := "bytes"
+	if len(chunk) == 1 {
+		noun = "byte"
+	}
+
+	return fmt.Sprintf("%d %s: %s\n%s", len(chunk), noun, strconv.QuoteToASCII(chunk),
+		strings.TrimRight(hex.Dump([]byte(chunk)), "\n"))
+}
+
+// renderDebugPane renders the raw bytes of the last chunk received, and any
+// recent backpressure entries, in a bordered pane
+func (m *debateModel) renderDebugPane(width int) string {
+	content := "🐞 Last chunk\n\n"
+	if m.lastChunk == "" {
+		content += "(none yet)"
+	} else {
+		content += formatRawChunk(m.lastChunk)
+	}
+	if len(m.debugLog) > 0 {
+		content += "\n\n" + strings.Join(m.debugLog, "\n")
+	}
+	return promptPaneStyle.Width(contentWidth(promptPaneStyle, width, m.minContentWidth)).Render(content)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFormatRawChunk(t *testing.T) {
+	tests := []struct {
+		name  string
+		chunk string
+		want  []string
+	}{
+		{
+			name:  "ascii",
+			chunk: "Hi",
+			want:  []string{`2 bytes: "Hi"`, "00000000  48 69"},
+		},
+		{
+			name:  "curly quote",
+			chunk: "it’s",
+			want:  []string{`6 bytes: "it\u2019s"`, "69 74 e2 80 99 73", "|it...s|"},
+		},
+		{
+			name:  "invalid utf-8 and control characters",
+			chunk: "a\xff\n",
+			want:  []string{`3 bytes: "a\xff\n"`, "61 ff 0a"},
+		},
+		{
+			name:  "single byte",
+			chunk: "\t",
+			want:  []string{`1 byte: "\t"`, "00000000  09"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatRawChunk(tt.chunk)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in:\n%s", want, got)
+				}
+			}
+			if strings.HasSuffix(got, "\n") {
+				t.Errorf("Expected no trailing newline, got %q", got)
+			}
+		})
+	}
+}
+
+// TestDebug_KeepsLastChunk verifies the last chunk is only kept when
+// debugging
+func TestDebug_KeepsLastChunk(t *testing.T) {
+	for _, debug := range []bool{false, true} {
+		m := &debateModel{
+			model1Name:   "phi3:mini",
+			model2Name:   "gemma3:4b",
+			state:        stateDebating,
+			isGenerating: true,
+			debug:        debug,
+		}
+		m.Update(rawChunkMsg{raw: []byte(`{"response":"café"}`)})
+
+		if want := map[bool]string{false: "", true: `{"response":"café"}`}[debug]; m.lastChunk != want {
+			t.Errorf("debug=%v: expected last chunk %q, got %q", debug, want, m.lastChunk)
+		}
+	}
+}
+
+// TestSetRawChunkHandler_KeepsInvalidUTF8 verifies the handler is given the
+// bytes the server sent, not the decoded text in which invalid UTF-8 has
+// become U+FFFD
+func TestSetRawChunkHandler_KeepsInvalidUTF8(t *testing.T) {
+	line := "{\"response\":\"caf\xc3\",\"done\":true}"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(line + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	var raws []string
+	client.SetRawChunkHandler(func(modelName string, raw []byte) {
+		raws = append(raws, string(raw))
+	})
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "phi3:mini", "test")
+	var text string
+	for chunk := range responseChan {
+		text += chunk
+	}
+	if err := <-errorChan; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if text != "caf\ufffd" {
+		t.Errorf("Expected the decoded text to replace the invalid byte, got %q", text)
+	}
+	if len(raws) != 1 || raws[0] != line {
+		t.Errorf("Expected the raw line %q, got %q", line, raws)
+	}
+}
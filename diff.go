@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Divergence marks for a pair of turns at the same position in two
+// transcripts
+const (
+	divergenceSame     = "same"
+	divergenceSimilar  = "similar"
+	divergenceDiverged = "diverged"
+	divergenceOnlyA    = "only-a"
+	divergenceOnlyB    = "only-b"
+)
+
+// Diff layouts accepted by -diff-layout
+const (
+	diffLayoutSideBySide = "side-by-side"
+	diffLayoutUnified    = "unified"
+)
+
+// similarOverlap is the share of words two turns must have in common to
+// count as making the same argument
+const similarOverlap = 0.5
+
+// defaultDiffWidth is the width of a side-by-side diff
+const defaultDiffWidth = 120
+
+// turnPair is the turn at one position in each transcript. A or B is nil
+// when that transcript ran out of turns.
+type turnPair struct {
+	Index      int
+	A, B       *Turn
+	Divergence string
+	Overlap    float64 // Share of distinct words both turns use, from 0 to 1
+}
+
+// pairTurns pairs the turns of two transcripts by position and marks how far
+// each pair diverges. Turns beyond the shorter transcript are paired with nil.
+func pairTurns(a, b []Turn) []turnPair {
+	n := max(len(a), len(b))
+	pairs := make([]turnPair, n)
+	for i := range pairs {
+		pair := turnPair{Index: i}
+		if i < len(a) {
+			pair.A = &a[i]
+		}
+		if i < len(b) {
+			pair.B = &b[i]
+		}
+
+		switch {
+		case pair.B == nil:
+			pair.Divergence = divergenceOnlyA
+		case pair.A == nil:
+			pair.Divergence = divergenceOnlyB
+		default:
+			pair.Overlap = wordOverlap(pair.A.Content, pair.B.Content)
+			switch {
+			case strings.TrimSpace(pair.A.Content) == strings.TrimSpace(pair.B.Content):
+				pair.Divergence = divergenceSame
+			case pair.Overlap >= similarOverlap:
+				pair.Divergence = divergenceSimilar
+			default:
+				pair.Divergence = divergenceDiverged
+			}
+		}
+		pairs[i] = pair
+	}
+	return pairs
+}
+
+// firstDivergence returns the index of the first pair that is not the same
+// or similar, or -1 if the transcripts never diverge
+func firstDivergence(pairs []turnPair) int {
+	for _, pair := range pairs {
+		if pair.Divergence != divergenceSame && pair.Divergence != divergenceSimilar {
+			return pair.Index
+		}
+	}
+	return -1
+}
+
+// wordOverlap returns the Jaccard similarity of the distinct words in a and
+// b: the words both use divided by the words either uses
+func wordOverlap(a, b string) float64 {
+	setA := make(map[string]bool)
+	for _, w := range words(a) {
+		setA[w] = true
+	}
+	setB := make(map[string]bool)
+	for _, w := range words(b) {
+		setB[w] = true
+	}
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for w := range setA {
+		if setB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// divergenceLabel describes a pair's divergence for the diff header
+func divergenceLabel(pair turnPair) string {
+	switch pair.Divergence {
+	case divergenceSame:
+		return "= same"
+	case divergenceSimilar:
+		return fmt.Sprintf("≈ similar (%.0f%% overlap)", pair.Overlap*100)
+	case divergenceDiverged:
+		return fmt.Sprintf("≠ diverged (%.0f%% overlap)", pair.Overlap*100)
+	case divergenceOnlyA:
+		return "+ only in A"
+	default:
+		return "+ only in B"
+	}
+}
+
+// transcriptDiff is two loaded transcripts to compare
+type transcriptDiff struct {
+	NameA, NameB   string
+	TopicA, TopicB string
+	A, B           []Turn
+}
+
+// FormatDiff renders the turn-by-turn comparison in the given layout
+func (d transcriptDiff) FormatDiff(layout string, width int) string {
+	pairs := pairTurns(d.A, d.B)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("A: %s (%d turns)\n", d.NameA, len(d.A)))
+	b.WriteString(fmt.Sprintf("B: %s (%d turns)\n", d.NameB, len(d.B)))
+	if d.TopicA == d.TopicB {
+		b.WriteString(fmt.Sprintf("Topic: %s\n", d.TopicA))
+	} else {
+		b.WriteString(fmt.Sprintf("Topic A: %s\nTopic B: %s\n", d.TopicA, d.TopicB))
+	}
+	if i := firstDivergence(pairs); i >= 0 {
+		b.WriteString(fmt.Sprintf("First divergence at turn %d\n", i+1))
+	} else {
+		b.WriteString("The transcripts do not diverge\n")
+	}
+
+	for _, pair := range pairs {
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("=", min(width, 80)))
+		b.WriteString(fmt.Sprintf("\nTurn %d: %s\n", pair.Index+1, divergenceLabel(pair)))
+		if layout == diffLayoutUnified {
+			b.WriteString(formatUnifiedPair(pair))
+		} else {
+			b.WriteString(formatSideBySidePair(pair, width))
+		}
+	}
+
+	return b.String()
+}
+
+// formatUnifiedPair renders a pair with A's turn above B's. Turns that are
+// the same are shown once.
+func formatUnifiedPair(pair turnPair) string {
+	if pair.Divergence == divergenceSame {
+		return diffTurnText(" ", pair.A)
+	}
+
+	var b strings.Builder
+	if pair.A != nil {
+		b.WriteString(diffTurnText("-", pair.A))
+	}
+	if pair.B != nil {
+		b.WriteString(diffTurnText("+", pair.B))
+	}
+	return b.String()
+}
+
+// formatSideBySidePair renders a pair as two columns, A on the left
+func formatSideBySidePair(pair turnPair, width int) string {
+	columnWidth := max((width-3)/2, defaultMinContentWidth)
+	column := lipgloss.NewStyle().Width(columnWidth)
+
+	left := column.Render(diffTurnText("", pair.A))
+	right := column.Render(diffTurnText("", pair.B))
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, " │ ", right) + "\n"
+}
+
+// diffTurnText renders a turn's speaker and content, prefixing every line
+// with marker. A missing turn renders as a placeholder.
+func diffTurnText(marker string, turn *Turn) string {
+	text := "(no turn)"
+	if turn != nil {
+		text = fmt.Sprintf("%s:\n%s", turn.Speaker(), strings.TrimSpace(turn.Content))
+		if note := turn.failureNote(); note != "" {
+			text += "\n" + note
+		}
+	}
+	if marker == "" {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = marker + " " + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPairTurns_MarksDivergence verifies turns are paired by position and
+// marked by how much of their wording they share
+func TestPairTurns_MarksDivergence(t *testing.T) {
+	a := []Turn{
+		{ModelName: "mistral:7b", Content: "Remote work saves commuting time."},
+		{ModelName: "gemma3:4b", Content: "Offices help teams collaborate in person every day."},
+		{ModelName: "mistral:7b", Content: "Video calls are cheap."},
+	}
+	b := []Turn{
+		{ModelName: "llama3:8b", Content: "Remote work saves commuting time."},
+		{ModelName: "phi3:mini", Content: "Offices help teams collaborate in person."},
+		{ModelName: "llama3:8b", Content: "Taxes should fund public transit instead."},
+	}
+
+	pairs := pairTurns(a, b)
+	want := []string{divergenceSame, divergenceSimilar, divergenceDiverged}
+	if len(pairs) != len(want) {
+		t.Fatalf("Expected %d pairs, got %d", len(want), len(pairs))
+	}
+	for i, pair := range pairs {
+		if pair.Index != i || pair.A != &a[i] || pair.B != &b[i] {
+			t.Errorf("Pair %d: expected turn %d from each transcript", i, i)
+		}
+		if pair.Divergence != want[i] {
+			t.Errorf("Pair %d: expected %s, got %s (overlap %.2f)", i, want[i], pair.Divergence, pair.Overlap)
+		}
+	}
+	if got := firstDivergence(pairs); got != 2 {
+		t.Errorf("Expected the first divergence at index 2, got %d", got)
+	}
+}
+
+// TestPairTurns_UnequalLengths verifies the extra turns of the longer
+// transcript are paired with nothing
+func TestPairTurns_UnequalLengths(t *testing.T) {
+	short := []Turn{{ModelName: "mistral:7b", Content: "Same opening."}}
+	long := []Turn{
+		{ModelName: "mistral:7b", Content: "Same opening."},
+		{ModelName: "gemma3:4b", Content: "A reply."},
+		{ModelName: "mistral:7b", Content: "Another point."},
+	}
+
+	pairs := pairTurns(long, short)
+	if len(pairs) != 3 {
+		t.Fatalf("Expected 3 pairs, got %d", len(pairs))
+	}
+	for _, pair := range pairs[1:] {
+		if pair.B != nil || pair.Divergence != divergenceOnlyA {
+			t.Errorf("Pair %d: expected a turn only in A, got %+v", pair.Index, pair)
+		}
+	}
+
+	pairs = pairTurns(short, long)
+	if pairs[2].A != nil || pairs[2].Divergence != divergenceOnlyB {
+		t.Errorf("Expected the last turn only in B, got %+v", pairs[2])
+	}
+	if got := firstDivergence(pairs); got != 1 {
+		t.Errorf("Expected the first divergence where A ends, got %d", got)
+	}
+}
+
+func TestWordOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Cats are great", "cats ARE great!", 1},
+		{"cats are great", "dogs are loyal", 0.2},
+		{"cats", "dogs", 0},
+		{"", "", 1},
+	}
+
+	for _, tt := range tests {
+		if got := wordOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("wordOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestFormatDiff_Layouts verifies both layouts show each turn with its
+// divergence mark
+func TestFormatDiff_Layouts(t *testing.T) {
+	d := transcriptDiff{
+		NameA: "a.jsonl", NameB: "b.jsonl",
+		TopicA: "Tabs or spaces?", TopicB: "Tabs or spaces?",
+		A: []Turn{{ModelName: "mistral:7b", Content: "Tabs respect preferences."}},
+		B: []Turn{
+			{ModelName: "llama3:8b", Content: "Spaces look identical everywhere."},
+			{ModelName: "phi3:mini", Content: "Editors convert them anyway."},
+		},
+	}
+
+	side := d.FormatDiff(diffLayoutSideBySide, 100)
+	for _, want := range []string{"Topic: Tabs or spaces?", "First divergence at turn 1", "Turn 1: ≠ diverged", "Turn 2: + only in B", "(no turn)", "│"} {
+		if !strings.Contains(side, want) {
+			t.Errorf("Expected side-by-side diff to contain %q, got:\n%s", want, side)
+		}
+	}
+
+	unified := d.FormatDiff(diffLayoutUnified, 100)
+	for _, want := range []string{"- mistral:7b:", "- Tabs respect preferences.", "+ llama3:8b:", "+ Editors convert them anyway."} {
+		if !strings.Contains(unified, want) {
+			t.Errorf("Expected unified diff to contain %q, got:\n%s", want, unified)
+		}
+	}
+}
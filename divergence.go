@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIdenticalOpenings is returned by headless debates run with
+// -assert-divergence when both models open with the same statement
+var ErrIdenticalOpenings = errors.New("both models produced identical opening statements")
+
+// checkDivergence compares the first arguments of model1 and model2 in
+// turns, ignoring moderator interjections, theses and failed turns, and
+// returns ErrIdenticalOpenings when they are the same after trimming
+// whitespace. A model that has not argued yet is not an error.
+func checkDivergence(turns []Turn, model1, model2 string) error {
+	openings := make(map[string]string, 2)
+	for _, turn := range turns {
+		if !turn.isArgument() || (turn.ModelName != model1 && turn.ModelName != model2) {
+			continue
+		}
+		if _, ok := openings[turn.ModelName]; !ok {
+			openings[turn.ModelName] = strings.TrimSpace(turn.Content)
+		}
+	}
+
+	first, ok1 := openings[model1]
+	second, ok2 := openings[model2]
+	if !ok1 || !ok2 || first != second {
+		return nil
+	}
+	return fmt.Errorf("%w (check the models and their seed options): %q", ErrIdenticalOpenings, truncateForError(first))
+}
+
+// truncateForError shortens text quoted in an error message
+func truncateForError(text string) string {
+	const limit = 80
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit]) + "…"
+	}
+	return text
+}
+
+// assertDiverged checks the openings among turns when -assert-divergence is
+// set, reporting a failure to the sink as coming from speaker
+func (m *debateModel) assertDiverged(turns []Turn, speaker string) error {
+	if !m.assertDivergence {
+		return nil
+	}
+	err := checkDivergence(turns, m.model1Name, m.model2Name)
+	if err != nil && m.sink != nil {
+		m.sink.Send(m.errorEvent(speaker, err))
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckDivergence(t *testing.T) {
+	tests := []struct {
+		name  string
+		turns []Turn
+		want  bool // Whether ErrIdenticalOpenings is expected
+	}{
+		{"no turns", nil, false},
+		{"one opening", []Turn{{ModelName: "a", Content: "Yes."}}, false},
+		{"different", []Turn{{ModelName: "a", Content: "Yes."}, {ModelName: "b", Content: "No."}}, false},
+		{"identical", []Turn{{ModelName: "a", Content: "Yes."}, {ModelName: "b", Content: "Yes."}}, true},
+		{"identical after trimming", []Turn{{ModelName: "a", Content: "  Yes.\n"}, {ModelName: "b", Content: "Yes."}}, true},
+		{"only later turns repeat", []Turn{{ModelName: "a", Content: "Yes."}, {ModelName: "b", Content: "No."}, {ModelName: "a", Content: "No."}}, false},
+		{"moderator skipped", []Turn{{ModelName: "a", Content: "Yes."}, {Content: "Yes.", Moderator: true}, {ModelName: "b", Content: "No."}}, false},
+		{"failed turn skipped", []Turn{{ModelName: "a", Content: "Yes."}, {ModelName: "b", Error: "timeout"}, {ModelName: "b", Content: "Yes."}}, true},
+		{"same model twice", []Turn{{ModelName: "a", Content: "Yes."}, {ModelName: "a", Content: "Yes."}, {ModelName: "b", Content: "No."}}, false},
+		{"second side speaks third", []Turn{{ModelName: "a", Content: "Yes."}, {ModelName: "a", Content: "No."}, {ModelName: "b", Content: "Yes."}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDivergence(tt.turns, "a", "b")
+			if got := errors.Is(err, ErrIdenticalOpenings); got != tt.want {
+				t.Errorf("Expected identical=%v, got error %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// TestRunHeadless_AssertDivergence verifies a headless debate stops after
+// the second turn when both openings match, and is left alone otherwise
+func TestRunHeadless_AssertDivergence(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:       "mistral:7b",
+		model2Name:       "gemma3:4b",
+		ollamaClient:     NewOllamaClient(server.URL),
+		topic:            "Should homework be banned?",
+		assertDivergence: true,
+	}
+	result := runHeadless(context.Background(), m, 4)
+	if !errors.Is(result.Err, ErrIdenticalOpenings) {
+		t.Fatalf("Expected ErrIdenticalOpenings, got %v", result.Err)
+	}
+	if len(requests) != 2 || len(result.Turns) != 2 {
+		t.Errorf("Expected the debate to stop after 2 turns, got %d requests and %d turns", len(requests), len(result.Turns))
+	}
+
+	// Openings that differ let the debate finish, even if later turns repeat
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "I am " + req.Model, Done: true})
+	}))
+	defer server.Close()
+
+	m = &debateModel{
+		model1Name:       "mistral:7b",
+		model2Name:       "gemma3:4b",
+		ollamaClient:     NewOllamaClient(server.URL),
+		topic:            "Should homework be banned?",
+		assertDivergence: true,
+	}
+	if result := runHeadless(context.Background(), m, 4); result.Err != nil || len(result.Turns) != 4 {
+		t.Errorf("Expected 4 turns without error, got %d turns and %v", len(result.Turns), result.Err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// minSharedTerms is how many of the opponent's key terms a turn must take up
+// to count as engaging with the opponent's point
+const minSharedTerms = 2
+
+// minTermLength is the shortest word, in letters, counted as a key term
+const minTermLength = 4
+
+// engagementPhrases mark a turn that addresses the opponent outright
+var engagementPhrases = []string{
+	"my opponent", "opponent's", "you said", "you say", "you claim", "you argue",
+	"you mentioned", "your point", "your argument", "your claim", "you suggest",
+}
+
+// commonWords are words long enough to be key terms but too general to show
+// that a turn takes up its opponent's point
+var commonWords = map[string]bool{
+	"about": true, "also": true, "because": true, "been": true, "being": true,
+	"between": true, "both": true, "could": true, "does": true, "each": true,
+	"even": true, "from": true, "have": true, "however": true, "into": true,
+	"just": true, "more": true, "most": true, "much": true, "must": true,
+	"only": true, "other": true, "over": true, "people": true, "point": true,
+	"really": true, "same": true, "should": true, "some": true, "such": true,
+	"than": true, "that": true, "their": true, "them": true, "then": true,
+	"there": true, "these": true, "they": true, "thing": true, "think": true,
+	"this": true, "those": true, "through": true, "very": true, "well": true,
+	"were": true, "what": true, "when": true, "where": true, "which": true,
+	"while": true, "will": true, "with": true, "without": true, "would": true,
+	"your": true, "argument": true, "debate": true, "opponent": true,
+}
+
+// stemLength is how many leading letters of a word make its key term, so
+// that different forms of a word, such as "commute" and "commuting", count
+// as the same term
+const stemLength = 5
+
+// keyTerms returns the distinctive words of text, lowercased and cut to
+// their stems
+func keyTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		if len(runes) < minTermLength || commonWords[word] {
+			continue
+		}
+		terms[string(runes[:min(len(runes), stemLength)])] = true
+	}
+	return terms
+}
+
+// engagesWith reports whether curr takes up the point made in prev: it
+// addresses the opponent outright, or reuses enough of prev's key terms. A
+// prev too short to have key terms cannot be ignored.
+func engagesWith(prev, curr Turn) bool {
+	content := strings.ToLower(curr.Content)
+	for _, phrase := range engagementPhrases {
+		if strings.Contains(content, phrase) {
+			return true
+		}
+	}
+	if speaker := strings.ToLower(prev.Speaker()); speaker != "" && strings.Contains(content, speaker) {
+		return true
+	}
+
+	prevTerms := keyTerms(prev.Content)
+	required := min(minSharedTerms, len(prevTerms))
+	shared := 0
+	for term := range keyTerms(curr.Content) {
+		if prevTerms[term] {
+			shared++
+		}
+	}
+	return shared >= required
+}
+
+// opposingTurn returns the latest argument in the history before turn made
+// by someone other than turn's speaker
+func (m *debateModel) opposingTurn(turn Turn) (Turn, bool) {
+	for i := len(m.history) - 1; i >= 0; i-- {
+		prev := m.history[i]
+		if prev.ModelName != turn.ModelName && !prev.Moderator && !prev.Thesis && prev.Error == "" && prev.Content != "" {
+			return prev, true
+		}
+	}
+	return Turn{}, false
+}
+
+// rebuttalDue reports whether turn, just generated, ignores the opponent's
+// last point and should be asked for again. Each turn is asked for again at
+// most once.
+func (m *debateModel) rebuttalDue(turn Turn) bool {
+	if !m.requireEngagement || m.rebutSpeaker != "" || turn.Moderator || turn.Thesis {
+		return false
+	}
+	prev, ok := m.opposingTurn(turn)
+	return ok && !engagesWith(prev, turn)
+}
+
+// askRebuttal reports whether turn is due a rebuttal, and if so has the
+// next prompt for its model ask for one
+func (m *debateModel) askRebuttal(turn Turn) bool {
+	if !m.rebuttalDue(turn) {
+		return false
+	}
+	prev, _ := m.opposingTurn(turn)
+	m.rebutSpeaker = prev.Speaker()
+	return true
+}
+
+// repromptRebuttal drops the last turn, which askRebuttal found ignoring the
+// opponent, and asks its model again for a direct rebuttal
+func (m *debateModel) repromptRebuttal() tea.Cmd {
+	turn := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.errorMsg = fmt.Sprintf("↩ %s did not answer %s's last point; asking for a direct rebuttal", turn.Speaker(), m.rebutSpeaker)
+	m.isGenerating = true
+	return m.generateResponse()
+}
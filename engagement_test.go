@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEngagesWith(t *testing.T) {
+	prev := Turn{ModelName: "phi3:mini", Content: "Remote work saves commuting time and lets employees focus without office interruptions."}
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"rebuts the key terms", "Commuting time is often reclaimed by longer hours, and home interruptions are no better than office ones.", true},
+		{"different word forms", "Employees commute less, yes, but focusing at home is harder for many.", true},
+		{"addresses the opponent", "My opponent overlooks how isolating it can be.", true},
+		{"names the speaker", "phi3:mini ignores the costs of isolation.", true},
+		{"tangential", "Cities should invest in public parks and cleaner air for families.", false},
+		{"one shared term", "Offices foster mentorship and a shared culture that video calls cannot match.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engagesWith(prev, Turn{ModelName: "gemma3:4b", Content: tt.content}); got != tt.want {
+				t.Errorf("engagesWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngagesWith_ShortPoint(t *testing.T) {
+	prev := Turn{ModelName: "phi3:mini", Content: "Why?"}
+	if !engagesWith(prev, Turn{ModelName: "gemma3:4b", Content: "Cities should plant more trees."}) {
+		t.Error("Expected a point without key terms to be impossible to ignore")
+	}
+}
+
+func TestKeyTerms(t *testing.T) {
+	terms := keyTerms("The taxes they're raising would hurt workers' wages.")
+	if len(terms) != 5 {
+		t.Errorf("Expected 5 key terms, got %v", terms)
+	}
+	for _, common := range []string{"the", "they", "would"} {
+		if terms[common] {
+			t.Errorf("Expected %q to be left out, got %v", common, terms)
+		}
+	}
+
+	// Forms of the same word share a term
+	forms := keyTerms("Employees commuting to work")
+	for term := range keyTerms("employee commutes") {
+		if !forms[term] {
+			t.Errorf("Expected %q among %v", term, forms)
+		}
+	}
+}
+
+// engagementFixture has gemma3:4b talk past phi3:mini's opening before
+// answering it
+func engagementFixture() *Fixture {
+	return &Fixture{Responses: []FixtureResponse{
+		{Model: "phi3:mini", Chunks: []FixtureChunk{{Text: "Remote work saves commuting time and cuts office costs."}}},
+		{Model: "gemma3:4b", Chunks: []FixtureChunk{{Text: "Parks make cities greener."}}},
+		{Model: "gemma3:4b", Chunks: []FixtureChunk{{Text: "Commuting time saved is lost to longer hours, and office costs move to homes."}}},
+	}}
+}
+
+func TestRequireEngagement_Reprompts(t *testing.T) {
+	m := &debateModel{
+		model1Name:        "phi3:mini",
+		model2Name:        "gemma3:4b",
+		ollamaClient:      NewOllamaClient("http://127.0.0.1:1"),
+		generator:         newFixtureGenerator(engagementFixture()),
+		maxTurns:          2,
+		initialTopic:      "Is remote work better?",
+		requireEngagement: true,
+	}
+	var prompts []string
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+		if len(prompts) == 0 || prompts[len(prompts)-1] != m.lastPrompt {
+			prompts = append(prompts, m.lastPrompt)
+		}
+	}
+
+	if len(m.history) != 2 || !strings.HasPrefix(m.history[1].Content, "Commuting time saved") {
+		t.Fatalf("Expected the tangential turn replaced by the rebuttal, got %+v", m.history)
+	}
+	if len(prompts) != 3 || !strings.Contains(prompts[2], "did not address phi3:mini's last point") {
+		t.Errorf("Expected a third prompt asking for a rebuttal, got %q", prompts)
+	}
+	if strings.Contains(prompts[1], "did not address") {
+		t.Errorf("Expected the first attempt to be asked normally, got:\n%s", prompts[1])
+	}
+}
+
+func TestRequireEngagement_RepromptsOnce(t *testing.T) {
+	fixture := engagementFixture()
+	fixture.Responses[2].Chunks[0].Text = "Trees are lovely."
+	m := &debateModel{
+		model1Name:        "phi3:mini",
+		model2Name:        "gemma3:4b",
+		generator:         newFixtureGenerator(fixture),
+		topic:             "Is remote work better?",
+		requireEngagement: true,
+	}
+	result := runHeadless(context.Background(), m, 2)
+
+	if result.Err != nil {
+		t.Fatalf("Unexpected error: %v", result.Err)
+	}
+	if len(result.Turns) != 2 || result.Turns[1].Content != "Trees are lovely." {
+		t.Errorf("Expected the second attempt kept even if still tangential, got %+v", result.Turns)
+	}
+	if m.rebutSpeaker != "" {
+		t.Errorf("Expected the rebuttal request cleared after the turn, got %q", m.rebutSpeaker)
+	}
+}
+
+// TestRequireEngagement_SinkSkipsDiscardedTurn verifies a headless sink is
+// only sent the chunks of the turn that is kept
+func TestRequireEngagement_SinkSkipsDiscardedTurn(t *testing.T) {
+	sink := &recordingSink{}
+	m := &debateModel{
+		model1Name:        "phi3:mini",
+		model2Name:        "gemma3:4b",
+		generator:         newFixtureGenerator(engagementFixture()),
+		topic:             "Is remote work better?",
+		requireEngagement: true,
+		sink:              sink,
+	}
+	if result := runHeadless(context.Background(), m, 2); result.Err != nil {
+		t.Fatalf("Unexpected error: %v", result.Err)
+	}
+
+	var chunks []string
+	for _, event := range sink.events {
+		if event.Type == eventChunk {
+			chunks = append(chunks, event.Content)
+		}
+	}
+	if len(chunks) != 2 || !strings.HasPrefix(chunks[1], "Commuting time saved") {
+		t.Errorf("Expected only the kept turns' chunks, got %q", chunks)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadExamples reads few-shot example turns from the file at path
+func LoadExamples(path string) ([]Turn, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open examples file: %w", err)
+	}
+	defer f.Close()
+
+	return ParseExamples(f)
+}
+
+// ParseExamples parses few-shot example turns. Turns are separated by blank
+// lines and each turn starts with "Speaker: ". Following lines in the same
+// block continue the turn's content.
+//
+//	Pro: Cities should ban cars because...
+//
+//	Con: You said cities should ban cars, but...
+func ParseExamples(r io.Reader) ([]Turn, error) {
+	var examples []Turn
+	var current *Turn
+	lineNum := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// A blank line ends the current turn
+		if line == "" {
+			current = nil
+			continue
+		}
+
+		// Continuation of the current turn
+		if current != nil {
+			current.Content += "\n" + line
+			continue
+		}
+
+		speaker, content, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(speaker) == "" {
+			return nil, fmt.Errorf("line %d: expected \"Speaker: content\"", lineNum)
+		}
+
+		examples = append(examples, Turn{
+			ModelName: strings.TrimSpace(speaker),
+			Content:   strings.TrimSpace(content),
+		})
+		current = &examples[len(examples)-1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read examples: %w", err)
+	}
+
+	return examples, nil
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ExportText writes the debate as plain text, the same format that is
+// yanked to the clipboard
+func ExportText(topic string, meta *DebateMeta, history []Turn, stamps timestampFormat, w io.Writer) error {
+	var b strings.Builder
+
+	// Add topic header
+	b.WriteString(fmt.Sprintf("Debate Topic: %s\n", topic))
+	b.WriteString(strings.Repeat("=", 80))
+	b.WriteString("\n")
+	if meta != nil {
+		meta.writeText(&b)
+	} else {
+		b.WriteString("\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	return writeTextTurns(history, stamps, w)
+}
+
+// writeTextTurns writes turns in the plain-text export format, with a blank
+// line between turns
+func writeTextTurns(history []Turn, stamps timestampFormat, w io.Writer) error {
+	var b strings.Builder
+
+	// Add all turns with model names
+	for i, turn := range history {
+		timestamp := stamps.format(turn.Timestamp)
+		if turn.Thesis {
+			b.WriteString(fmt.Sprintf("[%s] %s (thesis):\n", timestamp, turn.Speaker()))
+		} else {
+			b.WriteString(fmt.Sprintf("[%s] %s:\n", timestamp, turn.Speaker()))
+		}
+		if turn.Prompt != "" {
+			b.WriteString(quotePrompt(turn.Prompt))
+			b.WriteString("\n")
+		}
+		content := normalizeParagraphs(turn.Content)
+		if turn.OverLength {
+			content += " " + overLengthNote
+		}
+		if note := turn.failureNote(); note != "" {
+			if content != "" {
+				b.WriteString(content)
+				b.WriteString("\n")
+			}
+			b.WriteString(note)
+		} else {
+			b.WriteString(content)
+		}
+		b.WriteString("\n")
+
+		// Add spacing between turns
+		if i < len(history)-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quotePrompt formats a turn's prompt as a "> "-quoted block
+func quotePrompt(prompt string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(prompt, "\n"), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// htmlModel1 returns the tag of the model whose turns ExportHTML shows on
+// the left: model1 as recorded in meta, or else the first model to argue
+func htmlModel1(meta *DebateMeta, history []Turn) string {
+	if meta != nil && len(meta.Models) > 0 {
+		return meta.Models[0]
+	}
+	for _, turn := range history {
+		if turn.isArgument() {
+			return turn.ModelName
+		}
+	}
+	return ""
+}
+
+// ExportHTML writes the debate as a standalone HTML page. Turns are shown as
+// chat bubbles in each model's color, with model1 on the left and model2 on
+// the right. Without meta naming the models, the first model to argue is
+// taken for model1.
+func ExportHTML(topic string, meta *DebateMeta, history []Turn, stamps timestampFormat, w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString(fmt.Sprintf("<title>Debate: %s</title>\n", html.EscapeString(topic)))
+	b.WriteString("<style>\n")
+	b.WriteString("body { font-family: sans-serif; background: #1e1e1e; color: #ddd; max-width: 900px; margin: 2em auto; }\n")
+	b.WriteString(fmt.Sprintf("h1 { color: %s; }\n", headerColor))
+	b.WriteString(".turn { border: 2px solid; border-radius: 12px; padding: 0.5em 1em; margin: 1em 0; max-width: 75%; }\n")
+	b.WriteString(fmt.Sprintf(".model1 { border-color: %s; color: %s; margin-right: auto; }\n", model1Color, model1Color))
+	b.WriteString(fmt.Sprintf(".model2 { border-color: %s; color: %s; margin-left: auto; }\n", model2Color, model2Color))
+	b.WriteString(fmt.Sprintf(".moderator { border-color: %s; border-style: double; color: %s; font-style: italic; margin: 1em auto; }\n", headerColor, headerColor))
+	b.WriteString(".thesis { border-width: 4px; font-weight: bold; font-size: 1.1em; }\n")
+	b.WriteString(".speaker { font-weight: bold; }\n")
+	b.WriteString(fmt.Sprintf(".timestamp { color: %s; font-style: italic; }\n", subtleColor))
+	b.WriteString(".content { white-space: pre-wrap; margin-top: 0.5em; }\n")
+	b.WriteString(".content p { margin: 0 0 0.75em; }\n")
+	b.WriteString(".content p:last-child { margin-bottom: 0; }\n")
+	b.WriteString(".failed { border-style: dashed; }\n")
+	b.WriteString(fmt.Sprintf(".meta { color: %s; display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }\n", subtleColor))
+	b.WriteString(".meta dd { margin: 0; }\n")
+	b.WriteString(fmt.Sprintf(".prompt { color: %s; margin-top: 0.5em; }\n", subtleColor))
+	b.WriteString(".prompt pre { white-space: pre-wrap; }\n")
+	b.WriteString(fmt.Sprintf(".note { color: %s; font-style: italic; margin-top: 0.5em; }\n", subtleColor))
+	b.WriteString(fmt.Sprintf(".error { color: %s; font-weight: bold; margin-top: 0.5em; }\n", errorColor))
+	b.WriteString("</style>\n</head>\n<body>\n")
+	b.WriteString(fmt.Sprintf("<h1>Debate Topic: %s</h1>\n", html.EscapeString(topic)))
+	if meta != nil {
+		meta.writeHTML(&b)
+	}
+
+	model1 := htmlModel1(meta, history)
+	for _, turn := range history {
+		class := "model2"
+		if turn.Moderator {
+			class = "moderator"
+		} else if turn.ModelName == model1 {
+			class = "model1"
+		}
+
+		if turn.Thesis {
+			class += " thesis"
+		}
+		if turn.Error != "" {
+			class += " failed"
+		}
+
+		b.WriteString(fmt.Sprintf("<div class=\"turn %s\">\n", class))
+		b.WriteString(fmt.Sprintf("<span class=\"speaker\">%s</span> <span class=\"timestamp\">[%s]</span>\n",
+			html.EscapeString(turn.Speaker()), html.EscapeString(stamps.format(turn.Timestamp))))
+		if turn.Prompt != "" {
+			b.WriteString(fmt.Sprintf("<details class=\"prompt\"><summary>Prompt</summary><pre>%s</pre></details>\n", html.EscapeString(turn.Prompt)))
+		}
+		b.WriteString("<div class=\"content\">")
+		for _, paragraph := range splitParagraphs(turn.Content) {
+			b.WriteString(fmt.Sprintf("<p>%s</p>", html.EscapeString(paragraph)))
+		}
+		b.WriteString("</div>\n")
+		if turn.OverLength {
+			b.WriteString(fmt.Sprintf("<div class=\"note\">%s</div>\n", overLengthNote))
+		}
+		if note := turn.failureNote(); note != "" {
+			b.WriteString(fmt.Sprintf("<div class=\"error\">%s</div>\n", html.EscapeString(note)))
+		}
+		b.WriteString("</div>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ExportJSONLines writes the debate as one JSON turn event per line, the
+// same events -serve streams, led by a meta event when meta is set
+func ExportJSONLines(topic string, meta *DebateMeta, history []Turn, w io.Writer) error {
+	if meta != nil {
+		if err := writeJSONLine(DebateEvent{Type: eventMeta, Topic: topic, Meta: meta, Timestamp: meta.StartedAt}, w); err != nil {
+			return err
+		}
+	}
+	for _, turn := range history {
+		if err := writeJSONLine(newTurnEvent(topic, turn), w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadTranscript reads a transcript saved as JSON lines (see
+// ExportJSONLines) or as a JSON array of the same events
+func LoadTranscript(path string) (topic string, history []Turn, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+
+	topic, history, err = ParseTranscript(f)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return topic, history, nil
+}
+
+// ParseTranscript parses turn events into the debate topic and its turns.
+// Events other than turns, such as streamed chunks and metadata, are
+// skipped.
+func ParseTranscript(r io.Reader) (topic string, history []Turn, err error) {
+	reader := bufio.NewReader(r)
+
+	var events []DebateEvent
+	if first, err := firstNonSpace(reader); err == nil && first == '[' {
+		if err := json.NewDecoder(reader).Decode(&events); err != nil {
+			return "", nil, fmt.Errorf("failed to parse transcript: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(reader)
+		for {
+			var event DebateEvent
+			if err := decoder.Decode(&event); err == io.EOF {
+				break
+			} else if err != nil {
+				return "", nil, fmt.Errorf("failed to parse transcript: %w", err)
+			}
+			events = append(events, event)
+		}
+	}
+
+	for _, event := range events {
+		if event.Type != eventTurn && event.Type != eventPruned {
+			continue
+		}
+		if topic == "" {
+			topic = event.Topic
+		}
+		history = append(history, Turn{
+			ModelName:   event.Model,
+			DisplayName: event.Speaker,
+			Content:     event.Content,
+			Timestamp:   event.Timestamp,
+			Flagged:     event.Flagged,
+			OverLength:  event.OverLength,
+			Error:       event.Error,
+			Prompt:      event.Prompt,
+			Moderator:   event.Moderator,
+			Thesis:      event.Thesis,
+			Stance:      event.Stance,
+		})
+	}
+	if len(history) == 0 {
+		return "", nil, fmt.Errorf("transcript has no turns")
+	}
+
+	return topic, history, nil
+}
+
+// firstNonSpace returns the first byte of r that is not whitespace without
+// consuming it
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b, r.UnreadByte()
+		}
+	}
+}
+
+// writeJSONLine writes event as a single line of JSON
+func writeJSONLine(event DebateEvent, w io.Writer) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
+
+// isJSONLines reports whether path names a JSON-lines file
+func isJSONLines(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".jsonl")
+}
+
+// ExportFile writes the debate to path, choosing the format from the file
+// extension: .html and .htm produce HTML, .jsonl JSON lines, anything else
+// plain text. meta, when set, heads the export.
+func ExportFile(path, topic string, meta *DebateMeta, history []Turn, stamps timestampFormat) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer f.Close()
+
+	if err := exportFormat(path, topic, meta, history, stamps, f); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return f.Close()
+}
+
+// exportFormat writes the debate to w in the format ExportFile chooses for
+// path
+func exportFormat(path, topic string, meta *DebateMeta, history []Turn, stamps timestampFormat, w io.Writer) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		return ExportHTML(topic, meta, history, stamps, w)
+	case ".jsonl":
+		return ExportJSONLines(topic, meta, history, w)
+	default:
+		return ExportText(topic, meta, history, stamps, w)
+	}
+}
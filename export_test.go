@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportTestHistory() []Turn {
+	return []Turn{
+		{ModelName: "mistral:7b", Content: "Profits <b>matter</b> & so do people.", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{ModelName: "gemma3:4b", DisplayName: "Skeptic", Content: "\"Quotes\" and 'apostrophes'.", Timestamp: time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC)},
+	}
+}
+
+func TestExportHTML_EscapesContent(t *testing.T) {
+	var b strings.Builder
+	if err := ExportHTML("Is <script> safe?", nil, exportTestHistory(), timestampFormat{}, &b); err != nil {
+		t.Fatalf("ExportHTML failed: %v", err)
+	}
+	out := b.String()
+
+	if strings.Contains(out, "<b>matter</b>") || strings.Contains(out, "<script>") {
+		t.Errorf("Expected content and topic to be HTML-escaped")
+	}
+	if !strings.Contains(out, "&lt;b&gt;matter&lt;/b&gt; &amp; so do people.") {
+		t.Errorf("Expected escaped content in output")
+	}
+	if !strings.Contains(out, "Skeptic") {
+		t.Errorf("Expected speaker display name in output")
+	}
+}
+
+func TestExportHTML_UsesModelColors(t *testing.T) {
+	var b strings.Builder
+	if err := ExportHTML("Topic", nil, exportTestHistory(), timestampFormat{}, &b); err != nil {
+		t.Fatalf("ExportHTML failed: %v", err)
+	}
+	out := b.String()
+
+	for _, color := range []string{string(model1Color), string(model2Color)} {
+		if !strings.Contains(out, color) {
+			t.Errorf("Expected color %s in output", color)
+		}
+	}
+	if !strings.Contains(out, `class="turn model1"`) || !strings.Contains(out, `class="turn model2"`) {
+		t.Errorf("Expected turns from both models to be styled")
+	}
+}
+
+// TestExportHTML_SidesFromConfiguredModels verifies each turn is placed on
+// its model's side, whoever spoke first
+func TestExportHTML_SidesFromConfiguredModels(t *testing.T) {
+	history := []Turn{
+		{ModelName: "moderator:1b", Moderator: true, Content: "Begin."},
+		{ModelName: "gemma3:4b", Content: "Offices win."},
+		{ModelName: "mistral:7b", Content: "Remote work wins."},
+	}
+
+	for _, meta := range []*DebateMeta{testMeta(), nil} {
+		var b strings.Builder
+		if err := ExportHTML("Topic", meta, history, timestampFormat{}, &b); err != nil {
+			t.Fatalf("ExportHTML failed: %v", err)
+		}
+		out := b.String()
+
+		// Without meta, the first model to argue is taken for model1
+		want := map[string]string{"gemma3:4b": "model1", "mistral:7b": "model2"}
+		if meta != nil {
+			want = map[string]string{"gemma3:4b": "model2", "mistral:7b": "model1"}
+		}
+		for modelName, class := range want {
+			if !strings.Contains(out, fmt.Sprintf("<div class=\"turn %s\">\n<span class=\"speaker\">%s</span>", class, modelName)) {
+				t.Errorf("meta=%v: expected %s's turn styled %s, got:\n%s", meta != nil, modelName, class, out)
+			}
+		}
+	}
+}
+
+func TestExportFile_ChoosesFormatByExtension(t *testing.T) {
+	dir := t.TempDir()
+
+	htmlPath := filepath.Join(dir, "debate.html")
+	if err := ExportFile(htmlPath, "Topic", nil, exportTestHistory(), timestampFormat{}); err != nil {
+		t.Fatalf("ExportFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(htmlPath)
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") {
+		t.Errorf("Expected .html export to be an HTML page")
+	}
+
+	textPath := filepath.Join(dir, "debate.txt")
+	if err := ExportFile(textPath, "Topic", nil, exportTestHistory(), timestampFormat{}); err != nil {
+		t.Fatalf("ExportFile failed: %v", err)
+	}
+	data, _ = os.ReadFile(textPath)
+	if !strings.Contains(string(data), "[10:01:00] Skeptic:") {
+		t.Errorf("Expected plain text export, got: %s", data)
+	}
+}
+
+func TestExport_FailedTurns(t *testing.T) {
+	history := append(exportTestHistory(), Turn{ModelName: "mistral:7b", Error: "connection reset", Timestamp: time.Date(2024, 1, 1, 10, 2, 0, 0, time.UTC)})
+
+	var text strings.Builder
+	if err := ExportText("Topic", nil, history, timestampFormat{}, &text); err != nil {
+		t.Fatalf("ExportText failed: %v", err)
+	}
+	if !strings.HasSuffix(text.String(), "mistral:7b:\n⚠️ Turn failed: connection reset\n") {
+		t.Errorf("Expected the failure in the text export, got %q", text.String())
+	}
+
+	var page strings.Builder
+	if err := ExportHTML("Topic", nil, history, timestampFormat{}, &page); err != nil {
+		t.Fatalf("ExportHTML failed: %v", err)
+	}
+	if !strings.Contains(page.String(), "turn model1 failed") || !strings.Contains(page.String(), "<div class=\"error\">⚠️ Turn failed: connection reset</div>") {
+		t.Errorf("Expected the failed turn to be marked in the HTML export")
+	}
+}
+
+func TestExport_EchoesPrompts(t *testing.T) {
+	history := exportTestHistory()
+	history[0].Prompt = "You are participating in a debate.\n\nProvide your <opening> argument."
+
+	var text strings.Builder
+	if err := ExportText("Topic", nil, history, timestampFormat{}, &text); err != nil {
+		t.Fatalf("ExportText failed: %v", err)
+	}
+	want := "mistral:7b:\n> You are participating in a debate.\n>\n> Provide your <opening> argument.\n\nProfits"
+	if !strings.Contains(text.String(), want) {
+		t.Errorf("Expected the quoted prompt before the response, got %q", text.String())
+	}
+	if strings.Count(text.String(), "> You are participating") != 1 {
+		t.Errorf("Expected only turns with a stored prompt to show one")
+	}
+
+	var page strings.Builder
+	if err := ExportHTML("Topic", nil, history, timestampFormat{}, &page); err != nil {
+		t.Fatalf("ExportHTML failed: %v", err)
+	}
+	if !strings.Contains(page.String(), "<details class=\"prompt\"><summary>Prompt</summary><pre>You are participating in a debate.\n\nProvide your &lt;opening&gt; argument.</pre></details>") {
+		t.Errorf("Expected a collapsible, escaped prompt in the HTML export")
+	}
+}
+
+// TestParseTranscript_RoundTrips verifies that a JSON lines export, or the
+// same events as a JSON array, loads back into the original turns
+func TestParseTranscript_RoundTrips(t *testing.T) {
+	var lines strings.Builder
+	if err := ExportJSONLines("Topic", nil, exportTestHistory(), &lines); err != nil {
+		t.Fatalf("ExportJSONLines failed: %v", err)
+	}
+	array := "[" + strings.Join(strings.Split(strings.TrimSpace(lines.String()), "\n"), ",") + "]"
+
+	for name, data := range map[string]string{"lines": lines.String(), "array": "\n " + array} {
+		topic, history, err := ParseTranscript(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: ParseTranscript failed: %v", name, err)
+		}
+		if topic != "Topic" || len(history) != 2 {
+			t.Fatalf("%s: Expected 2 turns on Topic, got %d on %q", name, len(history), topic)
+		}
+		if history[1].Speaker() != "Skeptic" || history[1].Content != exportTestHistory()[1].Content {
+			t.Errorf("%s: Expected the second turn to round-trip, got %+v", name, history[1])
+		}
+		if !history[0].Timestamp.Equal(exportTestHistory()[0].Timestamp) {
+			t.Errorf("%s: Expected timestamps to round-trip", name)
+		}
+	}
+
+	if _, _, err := ParseTranscript(strings.NewReader(`{"type":"done"}`)); err == nil {
+		t.Errorf("Expected an error for a transcript without turns")
+	}
+}
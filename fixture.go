@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Generator streams a model's response to a prompt. Chunks arrive on the
+// first channel, which is closed when the response is complete; a failure
+// is sent on the second. OllamaClient is the real implementation, and
+// fixtureGenerator replays a recording.
+type Generator interface {
+	GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error)
+}
+
+// backend returns what generates the debate's responses: the generator
+// when one is set, and the Ollama client otherwise
+func (m *debateModel) backend() Generator {
+	if m.generator != nil {
+		return m.generator
+	}
+	return m.ollamaClient
+}
+
+// ErrFixtureExhausted is returned when a fixture has no recorded response
+// left for a model
+var ErrFixtureExhausted = errors.New("fixture has no more recorded responses")
+
+// Fixture is a recording of model responses played back by -fixture
+type Fixture struct {
+	Responses []FixtureResponse `json:"responses"`
+}
+
+// FixtureResponse is one recorded response, streamed as its chunks
+type FixtureResponse struct {
+	Model  string         `json:"model"` // Model the response is for, or empty for whichever asks next
+	Chunks []FixtureChunk `json:"chunks"`
+}
+
+// FixtureChunk is a recorded chunk and how long after the previous one it
+// arrives
+type FixtureChunk struct {
+	Text    string `json:"text"`
+	DelayMS int    `json:"delay_ms"`
+}
+
+// LoadFixture reads a fixture file, rejecting unknown fields like
+// LoadScenario
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var fixture Fixture
+	if err := decoder.Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(fixture.Responses) == 0 {
+		return nil, fmt.Errorf("%s: fixture has no responses", path)
+	}
+	for i, response := range fixture.Responses {
+		for _, chunk := range response.Chunks {
+			if chunk.DelayMS < 0 {
+				return nil, fmt.Errorf("%s: responses[%d] has a negative delay_ms", path, i)
+			}
+		}
+	}
+	return &fixture, nil
+}
+
+// fixtureGenerator plays back a fixture's responses in order, each to the
+// first request from its model. It is safe for concurrent use.
+type fixtureGenerator struct {
+	mu        sync.Mutex
+	responses []FixtureResponse
+	used      []bool
+}
+
+// newFixtureGenerator returns a generator replaying fixture from the start
+func newFixtureGenerator(fixture *Fixture) *fixtureGenerator {
+	return &fixtureGenerator{
+		responses: fixture.Responses,
+		used:      make([]bool, len(fixture.Responses)),
+	}
+}
+
+// next takes the first unplayed response recorded for modelName, or for
+// any model
+func (g *fixtureGenerator) next(modelName string) (FixtureResponse, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, response := range g.responses {
+		if !g.used[i] && (response.Model == "" || response.Model == modelName) {
+			g.used[i] = true
+			return response, true
+		}
+	}
+	return FixtureResponse{}, false
+}
+
+// ValidateModel checks a response is left for modelName to play back, so
+// a model swapped in while a fixture plays is checked against the fixture
+// rather than a server
+func (g *fixtureGenerator) ValidateModel(modelName string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, response := range g.responses {
+		if !g.used[i] && (response.Model == "" || response.Model == modelName) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w for %s", ErrFixtureExhausted, modelName)
+}
+
+// GenerateResponseWithOptions streams the next recorded response for
+// modelName, waiting each chunk's delay before sending it. The prompt and
+// options are ignored.
+func (g *fixtureGenerator) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	responseChan := make(chan string)
+	errorChan := make(chan error, 1)
+	response, ok := g.next(modelName)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		if !ok {
+			errorChan <- fmt.Errorf("%w for %s", ErrFixtureExhausted, modelName)
+			return
+		}
+
+		for _, chunk := range response.Chunks {
+			timer := time.NewTimer(time.Duration(chunk.DelayMS) * time.Millisecond)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				errorChan <- ctx.Err()
+				return
+			}
+
+			select {
+			case responseChan <- chunk.Text:
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return responseChan, errorChan
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFixture() *Fixture {
+	return &Fixture{Responses: []FixtureResponse{
+		{Model: "phi3:mini", Chunks: []FixtureChunk{{Text: "Remote ", DelayMS: 20}, {Text: "work ", DelayMS: 20}, {Text: "wins."}}},
+		{Model: "gemma3:4b", Chunks: []FixtureChunk{{Text: "Offices ", DelayMS: 10}, {Text: "win."}}},
+		{Chunks: []FixtureChunk{{Text: "Anyone's turn."}}},
+	}}
+}
+
+func TestFixtureGenerator_EmitsChunksInOrder(t *testing.T) {
+	g := newFixtureGenerator(testFixture())
+
+	began := time.Now()
+	responseChan, errorChan := g.GenerateResponseWithOptions(context.Background(), "phi3:mini", "ignored", nil)
+	var chunks []string
+	for chunk := range responseChan {
+		chunks = append(chunks, chunk)
+	}
+	if err := <-errorChan; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := strings.Join(chunks, "|"); got != "Remote |work |wins." {
+		t.Errorf("Expected the recorded chunks in order, got %q", got)
+	}
+	if elapsed := time.Since(began); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected the recorded delays to be waited, took %s", elapsed)
+	}
+}
+
+func TestFixtureGenerator_MatchesModels(t *testing.T) {
+	g := newFixtureGenerator(testFixture())
+	collect := func(modelName string) (string, error) {
+		return collectResponse(context.Background(), g, modelName, "ignored", nil)
+	}
+
+	// gemma3:4b skips phi3:mini's recording, which stays for phi3:mini
+	if got, _ := collect("gemma3:4b"); got != "Offices win." {
+		t.Errorf("Expected gemma3:4b's response, got %q", got)
+	}
+	if got, _ := collect("gemma3:4b"); got != "Anyone's turn." {
+		t.Errorf("Expected the response for any model, got %q", got)
+	}
+	if _, err := collect("gemma3:4b"); !errors.Is(err, ErrFixtureExhausted) {
+		t.Errorf("Expected ErrFixtureExhausted, got %v", err)
+	}
+	if got, _ := collect("phi3:mini"); got != "Remote work wins." {
+		t.Errorf("Expected phi3:mini's response, got %q", got)
+	}
+}
+
+func TestFixtureGenerator_Cancel(t *testing.T) {
+	g := newFixtureGenerator(&Fixture{Responses: []FixtureResponse{
+		{Chunks: []FixtureChunk{{Text: "slow", DelayMS: 60000}}},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	responseChan, errorChan := g.GenerateResponseWithOptions(ctx, "phi3:mini", "ignored", nil)
+	cancel()
+	for range responseChan {
+	}
+	if err := <-errorChan; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	fixture, err := LoadFixture(write("ok.json", `{"responses": [{"model": "phi3:mini", "chunks": [{"text": "Hi", "delay_ms": 5}]}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fixture.Responses) != 1 || fixture.Responses[0].Chunks[0].DelayMS != 5 {
+		t.Errorf("Unexpected fixture %+v", fixture)
+	}
+
+	for name, content := range map[string]string{
+		"unknown.json":  `{"responses": [{"chunks": [{"txt": "Hi"}]}]}`,
+		"empty.json":    `{"responses": []}`,
+		"negative.json": `{"responses": [{"chunks": [{"text": "Hi", "delay_ms": -1}]}]}`,
+	} {
+		if _, err := LoadFixture(write(name, content)); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
+
+// TestFixture_DrivesDebate verifies a debate runs from a fixture alone,
+// without a server
+func TestFixture_DrivesDebate(t *testing.T) {
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(testFixture()),
+		maxTurns:     2,
+		initialTopic: "Is remote work better?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	if len(m.history) != 2 || m.history[0].Content != "Remote work wins." || m.history[1].Content != "Offices win." {
+		t.Errorf("Expected the recorded turns, got %+v", m.history)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// defaultHeadlessTurns is how many turns a headless debate runs by default
+const defaultHeadlessTurns = 4
+
+// collectResponse generates a full response from a model, gathering all of
+// its streamed chunks. onChunk, if set, is called with each chunk as it
+// arrives.
+func collectResponse(ctx context.Context, client Generator, modelName, prompt string, onChunk func(string)) (string, error) {
+	return collectResponseWithOptions(ctx, client, modelName, prompt, nil, onChunk)
+}
+
+// collectResponseWithOptions generates a full response like collectResponse,
+// with overrides taking precedence over the client's options
+func collectResponseWithOptions(ctx context.Context, client Generator, modelName, prompt string, overrides map[string]interface{}, onChunk func(string)) (string, error) {
+	responseChan, errorChan := client.GenerateResponseWithOptions(ctx, modelName, prompt, overrides)
+
+	var b strings.Builder
+	for chunk := range responseChan {
+		b.WriteString(chunk)
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	}
+	if err := <-errorChan; err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// runHeadless runs a debate of the given number of turns without the TUI,
+// appending each completed turn to m.history. It uses the same prompts and
+// post-processing as the interactive debate. Progress is reported to
+// m.sink when one is set. The result holds every turn made, even when the
+// debate fails part way. Simultaneous rounds make two turns each, so an odd
+// number of turns is rounded up to a whole round.
+func runHeadless(ctx context.Context, m *debateModel, turns int) (result DebateResult) {
+	result.Topic = m.topic
+	m.openDebate()
+	m.startMeta()
+	result.Meta = m.meta
+	began := time.Now()
+	defer func() {
+		result.Stats = newDebateStats(result.Turns, time.Since(began))
+	}()
+
+	// A seeded opening counts as the first turn
+	start := 0
+	if m.seedOpening() {
+		start = 1
+		result.Turns = append(result.Turns, m.history[0])
+	}
+
+	for i := start; i < turns; i++ {
+		if m.moderationDue() {
+			if turn, ok := m.interject(ctx); ok {
+				result.Turns = append(result.Turns, turn)
+			}
+		}
+
+		// Both models answer a simultaneous round at once, making two turns
+		if m.simultaneous {
+			round, err := m.headlessRound(ctx)
+			for _, turn := range round {
+				m.history = append(m.history, turn)
+				result.Turns = append(result.Turns, turn)
+				if m.sink != nil {
+					m.sink.Send(m.turnEvent(turn))
+				}
+			}
+			if err != nil {
+				result.Err = err
+				return result
+			}
+			if err := m.assertDiverged(result.Turns, round[1].ModelName); err != nil {
+				result.Err = err
+				return result
+			}
+			i++
+
+			if m.endOnConsensus && (detectConsensus(round[0]) || detectConsensus(round[1])) {
+				m.endReason = "🤝 Consensus reached"
+				return result
+			}
+			m.pruneHistory()
+			continue
+		}
+
+		// A model states its thesis before its first argument
+		if next := m.getNextModel(); m.thesisDue(next) {
+			if turn, ok := m.stateThesis(ctx, next); ok {
+				result.Turns = append(result.Turns, turn)
+			}
+		}
+
+		// A turn that talks past its opponent is asked for once more. Its
+		// chunks are held back until it is kept, so the sink never sees
+		// a discarded turn.
+		turn, chunks, err := m.headlessTurn(ctx, m.requireEngagement)
+		if err == nil && m.askRebuttal(turn) {
+			turn, chunks, err = m.headlessTurn(ctx, false)
+		}
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		modelName := turn.ModelName
+		m.history = append(m.history, turn)
+		result.Turns = append(result.Turns, turn)
+		if m.sink != nil {
+			for _, chunk := range chunks {
+				m.sink.Send(chunk)
+			}
+			m.sink.Send(m.turnEvent(turn))
+		}
+
+		// Abort when both models opened with the same statement
+		if err := m.assertDiverged(result.Turns, modelName); err != nil {
+			result.Err = err
+			return result
+		}
+
+		// Stop early if the models have come to agree
+		if m.endOnConsensus && len(m.history) > 1 && detectConsensus(m.history[len(m.history)-1]) {
+			m.endReason = "🤝 Consensus reached"
+			return result
+		}
+
+		m.pruneHistory()
+		m.switchTurn()
+	}
+
+	return result
+}
+
+// headlessTurn generates the current model's next turn of a headless
+// debate, reporting chunks and any failure to the sink. With hold, the
+// chunks are returned for the caller to send instead.
+func (m *debateModel) headlessTurn(ctx context.Context, hold bool) (Turn, []DebateEvent, error) {
+	modelName, prompt := m.nextPrompt()
+
+	// Chunks are not streamed when filtering, since a disallowed word
+	// can be split across them
+	var held []DebateEvent
+	var onChunk func(string)
+	if m.sink != nil && m.contentFilter == nil {
+		onChunk = func(chunk string) {
+			if hold {
+				held = append(held, m.chunkEvent(modelName, chunk))
+				return
+			}
+			m.sink.Send(m.chunkEvent(modelName, chunk))
+		}
+	}
+
+	content, err := collectResponse(ctx, m.turnBackend(modelName), modelName, prompt, onChunk)
+	if err != nil {
+		if m.sink != nil {
+			m.sink.Send(m.errorEvent(modelName, err))
+		}
+		return Turn{}, nil, err
+	}
+	content = m.finishContent(content)
+	var flagged bool
+	if m.contentFilter != nil {
+		content, flagged = m.contentFilter(content)
+	}
+
+	return Turn{
+		ModelName:   modelName,
+		DisplayName: m.displayName(modelName),
+		Content:     content,
+		Timestamp:   time.Now(),
+		Flagged:     flagged,
+		OverLength:  overLength(content, m.softLength),
+		Prompt:      m.storedPrompt(prompt),
+		Stance:      m.stance(modelName),
+	}, held, nil
+}
+
+// interject adds a moderator interjection to the history of a headless
+// debate and returns it. A failed interjection is reported and skipped.
+func (m *debateModel) interject(ctx context.Context) (Turn, bool) {
+	prompt := BuildModeratorPrompt(m.topic, m.promptHistory())
+	m.lastPrompt = prompt
+	content, err := collectResponse(ctx, m.backend(), m.moderator, prompt, nil)
+	if err != nil {
+		if m.sink != nil {
+			event := m.errorEvent(m.moderator, err)
+			event.Speaker = moderatorName
+			m.sink.Send(event)
+		}
+		return Turn{}, false
+	}
+
+	turn := m.moderatorTurn(content)
+	if m.contentFilter != nil {
+		turn.Content, turn.Flagged = m.contentFilter(content)
+	}
+	m.history = append(m.history, turn)
+	if m.sink != nil {
+		m.sink.Send(m.turnEvent(turn))
+	}
+	m.pruneHistory()
+	return turn, true
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// pruneHistory drops the oldest completed turns once the history holds more
+// than historyCap, so long sessions do not grow without bound. Each dropped
+// turn is sent to m.sink first so it can still be streamed or written out.
+// A historyCap of zero or less keeps every turn.
+func (m *debateModel) pruneHistory() {
+	if m.historyCap <= 0 || len(m.history) <= m.historyCap {
+		return
+	}
+
+	n := len(m.history) - m.historyCap
+	dropped := m.history[:n]
+	if m.sink != nil {
+		for _, turn := range dropped {
+			m.sink.Send(m.prunedEvent(turn))
+		}
+	}
+
+	// Keep the typewriter and render cache aligned with the remaining turns
+	m.typewriter.revealed -= transcriptLength(dropped)
+	if m.typewriter.revealed < 0 {
+		m.typewriter.revealed = 0
+	}
+	if len(m.turnCache) > n {
+		m.turnCache = append([]renderedTurn(nil), m.turnCache[n:]...)
+	} else {
+		m.turnCache = nil
+	}
+
+	// Copy the kept turns so the dropped ones can be garbage collected
+	m.history = append([]Turn(nil), m.history[n:]...)
+	m.prunedTurns += n
+	for _, turn := range dropped {
+		switch {
+		case turn.Moderator:
+			m.prunedUnmod = 0
+		case turn.isArgument():
+			m.prunedArgs++
+			m.prunedUnmod++
+		}
+	}
+}
+
+// transcriptSink writes turns to the -output file as the debate runs, so
+// nothing is lost to a capped history or a crash. In live mode it appends
+// every completed turn; otherwise it only writes turns dropped by
+// pruneHistory and finish adds the rest. Each write leaves a valid plain-text
+// or, for .jsonl paths, JSON-lines file.
+type transcriptSink struct {
+	path   string
+	stamps timestampFormat
+	live   bool        // Write every completed turn, not only pruned ones
+	meta   *DebateMeta // Metadata of the debate, once it has started
+	file   *os.File
+	err    error // First write error, reported by finish
+
+	// onError, if set, is told of the first write error as it happens
+	onError func(error)
+}
+
+// fail records err as the reason the transcript is incomplete, stopping
+// further writes
+func (s *transcriptSink) fail(err error) {
+	s.err = err
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+// Send appends a turn to the transcript, creating the file and writing its
+// header on the first one
+func (s *transcriptSink) Send(event DebateEvent) {
+	if event.Type == eventMeta {
+		s.meta = event.Meta
+		return
+	}
+
+	want := eventPruned
+	if s.live {
+		want = eventTurn
+	}
+	if event.Type != want || s.err != nil {
+		return
+	}
+
+	if s.file == nil {
+		f, err := os.Create(s.path)
+		if err != nil {
+			s.fail(fmt.Errorf("failed to create export file: %w", err))
+			return
+		}
+		s.file = f
+		if err := s.writeHeader(event.Topic); err != nil {
+			s.fail(fmt.Errorf("failed to write export: %w", err))
+			return
+		}
+	}
+
+	if err := s.writeTurn(event); err != nil {
+		s.fail(fmt.Errorf("failed to write export: %w", err))
+	}
+}
+
+// writeHeader starts the transcript with the topic and metadata of the
+// debate, or for JSON lines only the metadata
+func (s *transcriptSink) writeHeader(topic string) error {
+	if isJSONLines(s.path) {
+		return ExportJSONLines(topic, s.meta, nil, s.file)
+	}
+	return ExportText(topic, s.meta, nil, s.stamps, s.file)
+}
+
+// writeTurn appends one turn in the transcript's format
+func (s *transcriptSink) writeTurn(event DebateEvent) error {
+	if isJSONLines(s.path) {
+		event.Type = eventTurn
+		return writeJSONLine(event, s.file)
+	}
+
+	turn := Turn{DisplayName: event.Speaker, Content: event.Content, Timestamp: event.Timestamp, Error: event.Error, Prompt: event.Prompt}
+	if err := writeTextTurns([]Turn{turn}, s.stamps, s.file); err != nil {
+		return err
+	}
+	_, err := s.file.WriteString("\n")
+	return err
+}
+
+// finish completes and closes the transcript. Outside live mode it writes
+// the turns still in memory after the pruned ones, or exports history as
+// usual when nothing was pruned.
+func (s *transcriptSink) finish(topic string, history []Turn) error {
+	if s.file == nil {
+		if s.err != nil || s.live {
+			return s.err
+		}
+		return ExportFile(s.path, topic, s.meta, history, s.stamps)
+	}
+	defer s.file.Close()
+
+	if s.err != nil {
+		return s.err
+	}
+	if !s.live {
+		var err error
+		if isJSONLines(s.path) {
+			err = ExportJSONLines(topic, nil, history, s.file)
+		} else {
+			err = writeTextTurns(history, s.stamps, s.file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+	return s.file.Close()
+}
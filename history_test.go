@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingSink collects the events it is sent
+type recordingSink struct {
+	events []DebateEvent
+}
+
+func (s *recordingSink) Send(event DebateEvent) {
+	s.events = append(s.events, event)
+}
+
+func historyTestTurns(n int) []Turn {
+	turns := make([]Turn, n)
+	for i := range turns {
+		model := "mistral:7b"
+		if i%2 == 1 {
+			model = "gemma3:4b"
+		}
+		turns[i] = Turn{ModelName: model, Content: strings.Repeat("x", i+1), Timestamp: time.Date(2024, 1, 1, 10, i, 0, 0, time.UTC)}
+	}
+	return turns
+}
+
+func TestPruneHistory_EmitsDroppedTurnsAndBoundsHistory(t *testing.T) {
+	sink := &recordingSink{}
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", topic: "Tabs or spaces?", historyCap: 3, sink: sink}
+	m.history = historyTestTurns(5)
+
+	m.pruneHistory()
+
+	if len(m.history) != 3 {
+		t.Fatalf("Expected 3 turns kept, got %d", len(m.history))
+	}
+	if m.history[0].Content != "xxx" {
+		t.Errorf("Expected the newest turns to be kept, first is %q", m.history[0].Content)
+	}
+	if m.prunedTurns != 2 {
+		t.Errorf("Expected 2 pruned turns, got %d", m.prunedTurns)
+	}
+
+	if len(sink.events) != 2 {
+		t.Fatalf("Expected 2 pruned events, got %d", len(sink.events))
+	}
+	for i, event := range sink.events {
+		if event.Type != eventPruned || event.Content != strings.Repeat("x", i+1) || event.Topic != "Tabs or spaces?" {
+			t.Errorf("Unexpected event %d: %+v", i, event)
+		}
+	}
+
+	// Under the cap nothing more is dropped
+	m.pruneHistory()
+	if len(m.history) != 3 || len(sink.events) != 2 {
+		t.Errorf("Expected no further pruning, have %d turns and %d events", len(m.history), len(sink.events))
+	}
+}
+
+func TestPruneHistory_UncappedKeepsAll(t *testing.T) {
+	m := &debateModel{history: historyTestTurns(5)}
+	m.pruneHistory()
+	if len(m.history) != 5 || m.prunedTurns != 0 {
+		t.Errorf("Expected all turns kept without a cap, have %d", len(m.history))
+	}
+}
+
+func TestPruneHistory_KeepsTurnIndex(t *testing.T) {
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", historyCap: 1}
+	m.promptOptions.Phases, _ = ParsePhaseSchedule("opening,rebuttal,closing")
+	m.history = historyTestTurns(2)
+
+	m.pruneHistory()
+
+	// The phase follows the full debate length, not just the kept turns
+	if !strings.Contains(m.currentPrompt(), "This is the rebuttal") {
+		t.Errorf("Expected the third turn to be in the rebuttal phase after pruning")
+	}
+}
+
+func TestTranscriptSink_WritesPrunedTurnsThenRest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debate.txt")
+	sink := &transcriptSink{path: path}
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", topic: "Tabs or spaces?", historyCap: 1, sink: sink}
+	m.history = historyTestTurns(3)
+
+	m.pruneHistory()
+	if err := sink.finish(m.topic, m.history); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read transcript: %v", err)
+	}
+
+	var want strings.Builder
+	ExportText("Tabs or spaces?", nil, historyTestTurns(3), timestampFormat{}, &want)
+	if string(data) != want.String() {
+		t.Errorf("Expected the full transcript\n%s\ngot\n%s", want.String(), data)
+	}
+}
+
+func TestTranscriptSink_LiveWritesTurnsProgressively(t *testing.T) {
+	for _, name := range []string{"debate.txt", "debate.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			var requests []GenerateRequest
+			server := newTestServer(t, &requests)
+
+			path := filepath.Join(t.TempDir(), name)
+			sink := &transcriptSink{path: path, live: true}
+			m := &debateModel{
+				model1Name:   "mistral:7b",
+				model2Name:   "gemma3:4b",
+				ollamaClient: NewOllamaClient(server.URL),
+				topic:        "Tabs or spaces?",
+				state:        stateDebating,
+				isGenerating: true,
+				sink:         sink,
+			}
+
+			// Each finished turn is in the file before the next one starts
+			cmd := m.generateResponse()
+			for turn := 1; turn <= 2; turn++ {
+				_, cmd = m.Update(cmd())
+				_, cmd = m.Update(cmd())
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Failed to read transcript after turn %d: %v", turn, err)
+				}
+				if got := strings.Count(string(data), "ok"); got != turn {
+					t.Errorf("Expected %d turns in the file, found %d:\n%s", turn, got, data)
+				}
+			}
+			m.shutdown()
+
+			if err := sink.finish(m.topic, m.history); err != nil {
+				t.Fatalf("finish failed: %v", err)
+			}
+			data, _ := os.ReadFile(path)
+			if strings.HasSuffix(name, ".jsonl") {
+				if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], `{"type":"turn","topic":"Tabs or spaces?","model":"gemma3:4b"`) {
+					t.Errorf("Expected one JSON turn per line, got:\n%s", data)
+				}
+			} else if !strings.HasPrefix(string(data), "Debate Topic: Tabs or spaces?\n") {
+				t.Errorf("Expected the text header, got:\n%s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BuildJudgePrompt asks a judge model to pick the winner of a finished debate
+func BuildJudgePrompt(topic string, history []Turn, speakers []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("You are judging a debate on the topic: \"%s\"\n\n", topic))
+	prompt.WriteString(fmt.Sprintf("The debaters were %s.\n\n", strings.Join(speakers, " and ")))
+	prompt.WriteString("Transcript:\n")
+	prompt.WriteString(FormatHistory(history))
+	prompt.WriteString("\n\n")
+	prompt.WriteString("Decide who argued more convincingly. Briefly explain your reasoning, then end with a final line in exactly this form:\n")
+	prompt.WriteString("WINNER: <debater name>\n")
+
+	return prompt.String()
+}
+
+// parseVerdict extracts the winner from a judge's response. speakers maps
+// each debater's display name to its model tag. It returns the winning model
+// tag, or an empty string when the verdict names no known debater.
+func parseVerdict(response string, speakers map[string]string) string {
+	lines := strings.Split(response, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.Trim(lines[i], " *_")
+		if len(line) < len("WINNER:") || !strings.EqualFold(line[:len("WINNER:")], "WINNER:") {
+			continue
+		}
+
+		name := strings.Trim(strings.TrimSpace(line[len("WINNER:"):]), "*.\"'")
+		for speaker, tag := range speakers {
+			if strings.EqualFold(name, speaker) {
+				return tag
+			}
+		}
+		return ""
+	}
+
+	return ""
+}
+
+// JudgeDebate asks judgeModel which debater won and returns the winning
+// model tag, or an empty string if the judge's verdict is unclear
+func JudgeDebate(ctx context.Context, client *OllamaClient, judgeModel string, m *debateModel) (string, error) {
+	speakers := map[string]string{
+		m.sideName(0): m.model1Name,
+		m.sideName(1): m.model2Name,
+	}
+	prompt := BuildJudgePrompt(m.topic, m.history, []string{m.sideName(0), m.sideName(1)})
+
+	response, err := collectResponse(ctx, client, judgeModel, prompt, nil)
+	if err != nil {
+		return "", fmt.Errorf("judge failed: %w", err)
+	}
+
+	return parseVerdict(response, speakers), nil
+}
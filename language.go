@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// defaultLanguage is the language of prompts when -language is not given
+const defaultLanguage = "en"
+
+// promptMessages is the instruction text of every prompt in one language.
+// Formats take a single %s argument. The topic and the models' own words are
+// never translated.
+type promptMessages struct {
+	DateFormat string // Go time layout for the injected date
+	Date       string // Date statement, given the formatted date
+
+	Topic string // Debate topic statement, given the topic
+	Role  string // Role statement, given the model's name
+
+	AssignedPro      string
+	AssignedCon      string
+	AssignedOpening  string
+	AssignedResponse string
+	DefaultOpening   string
+	DefaultResponse  string
+
+	PreviousDiscussion string
+	ExamplesHeader     string
+	ExamplesFooter     string
+
+	NextArgument    string
+	OpeningArgument string
+	ResponsePrefix  string // Instruction to start with the response prefix, given the prefix
+	Rebut           string // Instruction to answer the opponent's last point directly, given the opponent's name
+	Concise         string // Reminder to stay within the word limit after running over it, given the limit
+
+	CompareTopic       string // Compare mode topic statement, given the topic
+	CompareInstruction string
+
+	ContinueRole string // Role statement for continuations, given the model's name
+	Continue     string
+
+	// Phases maps phase names to their instructions. Phases without an
+	// entry use their built-in English instruction.
+	Phases map[string]string
+
+	// Styles maps debate style names to their instructions. Styles without
+	// an entry use their built-in English instructions.
+	Styles map[string]string
+}
+
+// promptCatalog holds the prompt text for each language, keyed by ISO 639-1
+// code
+var promptCatalog = map[string]promptMessages{
+	"en": {
+		DateFormat: "January 2, 2006",
+		Date:       "Today's date is %s.",
+
+		Topic: "You are participating in a debate on the topic: \"%s\"",
+		Role:  "You are %s. Your role is to present arguments and respond to your opponent's points.",
+
+		AssignedPro:      "You are arguing in favor of the topic (the affirmative side). Take a clear position supporting it.",
+		AssignedCon:      "You are arguing against the topic (the opposing side). Take a clear position challenging it.",
+		AssignedOpening:  "You will be presenting the opening argument.",
+		AssignedResponse: "You will be responding to the opening argument with your counterarguments.",
+		DefaultOpening:   "You will be presenting the opening argument. Take a clear position on this topic and present your initial arguments.",
+		DefaultResponse:  "You will be responding to the opening argument. Take an opposing or alternative perspective and present your counterarguments.",
+
+		PreviousDiscussion: "Previous discussion:",
+		ExamplesHeader:     "Here's an example of good debate style:",
+		ExamplesFooter:     "(End of example. The real debate follows.)",
+
+		NextArgument:    "Provide your next argument or response. Be thoughtful, specific, and engage directly with the previous points made.",
+		OpeningArgument: "Provide your opening argument. Be thoughtful, specific, and clearly state your position.",
+		ResponsePrefix:  "Begin your response with \"%s\".",
+		Rebut:           "Your previous answer did not address %s's last point. Rebut it directly: name the claim you are answering and explain why it is wrong.",
+		Concise:         "Your last turn ran over the %d-word limit. Keep this one to %d words or fewer.",
+
+		CompareTopic:       "Give your answer on the topic: \"%s\"",
+		CompareInstruction: "Be thoughtful, specific, and clearly state your position.",
+
+		ContinueRole: "You are %s.",
+		Continue:     "Your last response was brief. Continue and elaborate on your most recent argument with further reasoning, evidence, or examples. Do not repeat what you already said.",
+	},
+	"pl": {
+		DateFormat: "2.01.2006",
+		Date:       "Dzisiejsza data to %s.",
+
+		Topic: "Bierzesz udział w debacie na temat: \"%s\"",
+		Role:  "Jesteś %s. Twoją rolą jest przedstawianie argumentów i odpowiadanie na argumenty przeciwnika.",
+
+		AssignedPro:      "Argumentujesz za tematem (strona popierająca). Zajmij jasne stanowisko, które go wspiera.",
+		AssignedCon:      "Argumentujesz przeciwko tematowi (strona przeciwna). Zajmij jasne stanowisko, które go podważa.",
+		AssignedOpening:  "Przedstawisz argument otwierający.",
+		AssignedResponse: "Odpowiesz na argument otwierający swoimi kontrargumentami.",
+		DefaultOpening:   "Przedstawisz argument otwierający. Zajmij jasne stanowisko w tej sprawie i przedstaw swoje pierwsze argumenty.",
+		DefaultResponse:  "Odpowiesz na argument otwierający. Przyjmij przeciwną lub alternatywną perspektywę i przedstaw swoje kontrargumenty.",
+
+		PreviousDiscussion: "Dotychczasowa dyskusja:",
+		ExamplesHeader:     "Oto przykład dobrego stylu debaty:",
+		ExamplesFooter:     "(Koniec przykładu. Poniżej właściwa debata.)",
+
+		NextArgument:    "Przedstaw swój kolejny argument lub odpowiedź. Bądź rzeczowy, konkretny i odnieś się bezpośrednio do wcześniejszych punktów.",
+		OpeningArgument: "Przedstaw swój argument otwierający. Bądź rzeczowy, konkretny i jasno określ swoje stanowisko.",
+		ResponsePrefix:  "Zacznij swoją odpowiedź od \"%s\".",
+		Rebut:           "Twoja poprzednia odpowiedź nie odniosła się do ostatniego argumentu %s. Odpowiedz na niego bezpośrednio: wskaż tezę, na którą odpowiadasz, i wyjaśnij, dlaczego jest błędna.",
+		Concise:         "Twoja poprzednia wypowiedź przekroczyła limit %d słów. Tym razem zmieść się w %d słowach.",
+
+		CompareTopic:       "Przedstaw swoją odpowiedź na temat: \"%s\"",
+		CompareInstruction: "Bądź rzeczowy, konkretny i jasno określ swoje stanowisko.",
+
+		ContinueRole: "Jesteś %s.",
+		Continue:     "Twoja ostatnia odpowiedź była krótka. Kontynuuj i rozwiń swój ostatni argument, podając dalsze uzasadnienie, dowody lub przykłady. Nie powtarzaj tego, co już zostało powiedziane.",
+
+		Phases: map[string]string{
+			"opening":           "To jest wystąpienie otwierające; przedstaw swoje stanowisko i główne argumenty.",
+			"rebuttal":          "To jest replika; bezpośrednio podważ najsłabsze punkty przedstawione przez przeciwnika.",
+			"cross-examination": "To jest przesłuchanie krzyżowe; zadawaj przeciwnikowi celne pytania i odpowiadaj na te, które zadał tobie.",
+			"closing":           "To jest wystąpienie końcowe; podsumuj swoje najmocniejsze argumenty i wyjaśnij, dlaczego twoje stanowisko przeważa.",
+		},
+		Styles: map[string]string{
+			"formal":   "Debatuj formalnie: uporządkuj swój argument w jasne punkty, poprzyj każdy z nich dowodami lub rozumowaniem i zachowaj wyważony, profesjonalny ton.",
+			"casual":   "Debatuj swobodnie: mów luźnym, rozmownym tonem, jak w rozmowie z przyjacielem, krótko i prostym językiem.",
+			"socratic": "Debatuj metodą sokratejską: broń swojego stanowiska głównie zadając przeciwnikowi dociekliwe pytania, które obnażają założenia i sprzeczności w jego poglądach.",
+		},
+	},
+	"de": {
+		DateFormat: "2.1.2006",
+		Date:       "Heute ist der %s.",
+
+		Topic: "Du nimmst an einer Debatte zum Thema \"%s\" teil.",
+		Role:  "Du bist %s. Deine Aufgabe ist es, Argumente vorzubringen und auf die Punkte deines Gegners einzugehen.",
+
+		AssignedPro:      "Du argumentierst für das Thema (die befürwortende Seite). Nimm eine klare Position ein, die es unterstützt.",
+		AssignedCon:      "Du argumentierst gegen das Thema (die ablehnende Seite). Nimm eine klare Position ein, die es infrage stellt.",
+		AssignedOpening:  "Du trägst das Eröffnungsargument vor.",
+		AssignedResponse: "Du antwortest auf das Eröffnungsargument mit deinen Gegenargumenten.",
+		DefaultOpening:   "Du trägst das Eröffnungsargument vor. Nimm eine klare Position zu diesem Thema ein und lege deine ersten Argumente dar.",
+		DefaultResponse:  "Du antwortest auf das Eröffnungsargument. Nimm eine gegensätzliche oder alternative Perspektive ein und lege deine Gegenargumente dar.",
+
+		PreviousDiscussion: "Bisherige Diskussion:",
+		ExamplesHeader:     "Hier ist ein Beispiel für einen guten Debattenstil:",
+		ExamplesFooter:     "(Ende des Beispiels. Es folgt die eigentliche Debatte.)",
+
+		NextArgument:    "Bringe dein nächstes Argument oder deine Antwort vor. Sei durchdacht und konkret und geh direkt auf die bisherigen Punkte ein.",
+		OpeningArgument: "Trage dein Eröffnungsargument vor. Sei durchdacht und konkret und lege deine Position klar dar.",
+		ResponsePrefix:  "Beginne deine Antwort mit \"%s\".",
+		Rebut:           "Deine vorige Antwort ist nicht auf das letzte Argument von %s eingegangen. Widerlege es direkt: Nenne die Behauptung, auf die du antwortest, und erkläre, warum sie falsch ist.",
+		Concise:         "Dein letzter Beitrag hat das Limit von %d Wörtern überschritten. Bleib diesmal bei höchstens %d Wörtern.",
+
+		CompareTopic:       "Gib deine Antwort zum Thema \"%s\".",
+		CompareInstruction: "Sei durchdacht und konkret und lege deine Position klar dar.",
+
+		ContinueRole: "Du bist %s.",
+		Continue:     "Deine letzte Antwort war kurz. Setze dein jüngstes Argument fort und vertiefe es mit weiteren Begründungen, Belegen oder Beispielen. Wiederhole nicht, was du bereits gesagt hast.",
+
+		Phases: map[string]string{
+			"opening":           "Dies ist das Eröffnungsstatement; stelle deine Position und deine wichtigsten Argumente vor.",
+			"rebuttal":          "Dies ist die Erwiderung; greife direkt die schwächsten Punkte deines Gegners an.",
+			"cross-examination": "Dies ist das Kreuzverhör; stelle deinem Gegner gezielte Fragen und beantworte die, die er dir gestellt hat.",
+			"closing":           "Dies ist das Schlussstatement; fasse deine stärksten Punkte zusammen und erkläre, warum deine Position überzeugt.",
+		},
+		Styles: map[string]string{
+			"formal":   "Debattiere förmlich: gliedere dein Argument in klare Punkte, stütze jeden mit Belegen oder Begründungen und bleibe in einem sachlichen, professionellen Ton.",
+			"casual":   "Debattiere locker: sprich entspannt und im Plauderton, wie mit einem Freund, und halte dich kurz und einfach.",
+			"socratic": "Debattiere nach sokratischer Art: vertritt deine Position vor allem, indem du deinem Gegner bohrende Fragen stellst, die die Annahmen und Widersprüche seiner Sicht offenlegen.",
+		},
+	},
+	"es": {
+		DateFormat: "02/01/2006",
+		Date:       "Hoy es %s.",
+
+		Topic: "Estás participando en un debate sobre el tema: \"%s\"",
+		Role:  "Eres %s. Tu papel es presentar argumentos y responder a los puntos de tu oponente.",
+
+		AssignedPro:      "Argumentas a favor del tema (la postura afirmativa). Adopta una posición clara que lo respalde.",
+		AssignedCon:      "Argumentas en contra del tema (la postura opositora). Adopta una posición clara que lo cuestione.",
+		AssignedOpening:  "Presentarás el argumento de apertura.",
+		AssignedResponse: "Responderás al argumento de apertura con tus contraargumentos.",
+		DefaultOpening:   "Presentarás el argumento de apertura. Adopta una posición clara sobre este tema y presenta tus argumentos iniciales.",
+		DefaultResponse:  "Responderás al argumento de apertura. Adopta una perspectiva opuesta o alternativa y presenta tus contraargumentos.",
+
+		PreviousDiscussion: "Discusión previa:",
+		ExamplesHeader:     "Este es un ejemplo de buen estilo de debate:",
+		ExamplesFooter:     "(Fin del ejemplo. A continuación, el debate real.)",
+
+		NextArgument:    "Presenta tu siguiente argumento o respuesta. Sé reflexivo y concreto, y responde directamente a los puntos planteados anteriormente.",
+		OpeningArgument: "Presenta tu argumento de apertura. Sé reflexivo y concreto, y expón claramente tu posición.",
+		ResponsePrefix:  "Comienza tu respuesta con \"%s\".",
+		Rebut:           "Tu respuesta anterior no abordó el último punto de %s. Refútalo directamente: indica la afirmación a la que respondes y explica por qué es errónea.",
+		Concise:         "Tu último turno superó el límite de %d palabras. Esta vez no pases de %d palabras.",
+
+		CompareTopic:       "Da tu respuesta sobre el tema: \"%s\"",
+		CompareInstruction: "Sé reflexivo y concreto, y expón claramente tu posición.",
+
+		ContinueRole: "Eres %s.",
+		Continue:     "Tu última respuesta fue breve. Continúa y desarrolla tu argumento más reciente con más razonamientos, pruebas o ejemplos. No repitas lo que ya has dicho.",
+
+		Phases: map[string]string{
+			"opening":           "Esta es la declaración de apertura; presenta tu posición y tus argumentos principales.",
+			"rebuttal":          "Esta es la réplica; cuestiona directamente los puntos más débiles de tu oponente.",
+			"cross-examination": "Este es el contrainterrogatorio; haz preguntas incisivas a tu oponente y responde a las que te haya hecho.",
+			"closing":           "Esta es la declaración final; resume tus puntos más sólidos y explica por qué prevalece tu posición.",
+		},
+		Styles: map[string]string{
+			"formal":   "Debate de manera formal: estructura tu argumento en puntos claros, respalda cada uno con pruebas o razonamientos y mantén un tono mesurado y profesional.",
+			"casual":   "Debate de manera informal: habla con un tono relajado y conversacional, como si hablaras con un amigo, y sé breve y sencillo.",
+			"socratic": "Debate al estilo socrático: defiende tu posición sobre todo haciendo a tu oponente preguntas incisivas que expongan los supuestos y contradicciones de su postura.",
+		},
+	},
+	"fr": {
+		DateFormat: "02/01/2006",
+		Date:       "Nous sommes le %s.",
+
+		Topic: "Vous participez à un débat sur le sujet : « %s »",
+		Role:  "Vous êtes %s. Votre rôle est de présenter des arguments et de répondre aux points de votre adversaire.",
+
+		AssignedPro:      "Vous défendez le sujet (le camp de l'affirmative). Adoptez une position claire en sa faveur.",
+		AssignedCon:      "Vous vous opposez au sujet (le camp de l'opposition). Adoptez une position claire qui le remet en question.",
+		AssignedOpening:  "Vous présenterez l'argument d'ouverture.",
+		AssignedResponse: "Vous répondrez à l'argument d'ouverture avec vos contre-arguments.",
+		DefaultOpening:   "Vous présenterez l'argument d'ouverture. Adoptez une position claire sur ce sujet et présentez vos premiers arguments.",
+		DefaultResponse:  "Vous répondrez à l'argument d'ouverture. Adoptez une perspective opposée ou différente et présentez vos contre-arguments.",
+
+		PreviousDiscussion: "Discussion précédente :",
+		ExamplesHeader:     "Voici un exemple de bon style de débat :",
+		ExamplesFooter:     "(Fin de l'exemple. Le vrai débat suit.)",
+
+		NextArgument:    "Présentez votre prochain argument ou votre réponse. Soyez réfléchi, précis, et répondez directement aux points soulevés précédemment.",
+		OpeningArgument: "Présentez votre argument d'ouverture. Soyez réfléchi, précis, et énoncez clairement votre position.",
+		ResponsePrefix:  "Commencez votre réponse par « %s ».",
+		Rebut:           "Votre réponse précédente n'a pas abordé le dernier argument de %s. Réfutez-le directement : nommez l'affirmation à laquelle vous répondez et expliquez pourquoi elle est fausse.",
+		Concise:         "Votre dernière intervention a dépassé la limite de %d mots. Cette fois, ne dépassez pas %d mots.",
+
+		CompareTopic:       "Donnez votre réponse sur le sujet : « %s »",
+		CompareInstruction: "Soyez réfléchi, précis, et énoncez clairement votre position.",
+
+		ContinueRole: "Vous êtes %s.",
+		Continue:     "Votre dernière réponse était brève. Poursuivez et développez votre argument le plus récent avec davantage de raisonnement, de preuves ou d'exemples. Ne répétez pas ce que vous avez déjà dit.",
+
+		Phases: map[string]string{
+			"opening":           "Ceci est la déclaration d'ouverture ; présentez votre position et vos principaux arguments.",
+			"rebuttal":          "Ceci est la réfutation ; contestez directement les points les plus faibles de votre adversaire.",
+			"cross-examination": "Ceci est le contre-interrogatoire ; posez des questions ciblées à votre adversaire et répondez à celles qu'il vous a posées.",
+			"closing":           "Ceci est la déclaration de clôture ; résumez vos points les plus forts et expliquez pourquoi votre position l'emporte.",
+		},
+		Styles: map[string]string{
+			"formal":   "Débattez de manière formelle : structurez votre argument en points clairs, étayez chacun par des preuves ou un raisonnement et gardez un ton mesuré et professionnel.",
+			"casual":   "Débattez de manière détendue : parlez sur un ton léger et conversationnel, comme avec un ami, en restant bref et simple.",
+			"socratic": "Débattez à la manière socratique : défendez votre position surtout en posant à votre adversaire des questions pénétrantes qui révèlent les présupposés et les contradictions de son point de vue.",
+		},
+	},
+}
+
+// ParseLanguage validates a -language code, returning it in the form used as
+// a catalog key
+func ParseLanguage(code string) (string, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return defaultLanguage, nil
+	}
+	if _, ok := promptCatalog[code]; !ok {
+		return "", fmt.Errorf("unsupported language '%s' (use %s)", code, strings.Join(supportedLanguages(), ", "))
+	}
+	return code, nil
+}
+
+// supportedLanguages returns the catalog's language codes in sorted order
+func supportedLanguages() []string {
+	codes := make([]string, 0, len(promptCatalog))
+	for code := range promptCatalog {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// messagesFor returns the prompt text for language, falling back to English
+// for an empty or unknown code
+func messagesFor(language string) promptMessages {
+	if msgs, ok := promptCatalog[language]; ok {
+		return msgs
+	}
+	return promptCatalog[defaultLanguage]
+}
+
+// phaseInstruction returns the instruction for phase in this language
+func (msgs promptMessages) phaseInstruction(phase DebatePhase) string {
+	if instruction, ok := msgs.Phases[phase.Name]; ok {
+		return instruction
+	}
+	return phase.Instruction
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -12,44 +18,904 @@ func main() {
 	// Parse command-line flags
 	model1 := flag.String("model1", "phi3:mini", "First AI model for the debate")
 	model2 := flag.String("model2", "gemma3:4b", "Second AI model for the debate")
+	alias1 := flag.String("alias1", "", "Display name for the first model (defaults to the model tag)")
+	alias2 := flag.String("alias2", "", "Display name for the second model (defaults to the model tag)")
+	pro := flag.String("pro", "", "Model (tag or alias) that argues in favor of the topic; the other argues against")
+	minWidth := flag.Int("min-width", defaultMinContentWidth, "Minimum width a turn box may wrap to")
+	noColor := flag.Bool("no-color", false, "Draw the view without colors and with ASCII borders; chosen automatically when the terminal has no color support")
+	themeName := flag.String("theme", themes[0].Name, "Color theme: default, light, or high-contrast; 't' cycles themes during a debate")
+	maxWidth := flag.Int("max-width", 0, "Maximum width of a turn box, centered on wider terminals (0 uses the full width)")
+	examplesFile := flag.String("examples-file", "", "File of example turns to show models as good debate style")
+	trimBoilerplate := flag.Bool("trim-boilerplate", false, "Trim whitespace and boilerplate openers from finished turns")
+	maxSentences := flag.Int("max-sentences", 0, "Cut each finished turn after this many sentences, marking the cut (0 has no limit)")
+	softLength := flag.Int("soft-length", 0, "Note each turn longer than this many words as [over length], keeping it whole (0 has no limit)")
+	softLengthReminder := flag.Bool("soft-length-reminder", false, "Remind a model whose last turn ran over -soft-length to be concise in its next one")
+	boilerplate := flag.String("boilerplate-prefixes", strings.Join(defaultBoilerplatePrefixes, "|"), "'|'-separated openers removed by -trim-boilerplate")
+	endOnConsensus := flag.Bool("end-on-consensus", false, "End the debate when a model agrees with its opponent")
+	typewriterOn := flag.Bool("typewriter", false, "Reveal streamed text at a steady pace instead of as it arrives")
+	typewriterCPS := flag.Int("typewriter-cps", defaultTypewriterCPS, "Characters per second revealed by -typewriter")
+	output := flag.String("output", "", "Write the transcript to this file (.html for a styled page, .jsonl for JSON lines) or directory when the debate ends")
+	thinkingFrames := flag.String("thinking-frames", defaultThinkingFrames, "Thinking animation: thought, spinner, dots, moon, or comma-separated frames")
+	cache := flag.Bool("cache", false, "Reuse the response to a prompt sent before to the same model with the same options instead of generating it again, for development and repeatable demos")
+	cacheDir := flag.String("cache-dir", "", "Directory to keep -cache responses in so they last across runs; without it the cache lasts one run")
+	tournament := flag.Int("tournament", 0, "Run N headless debates on -topic and tally the judge's verdicts")
+	exitOnVerdict := flag.Bool("exit-on-verdict", false, "Exit a -tournament with a code naming the winner: 0 if model1 won more debates, 1 if model2 did, 2 for a tie, 3 if the tournament failed")
+	topic := flag.String("topic", "", "Debate topic; starts the debate without asking, and is required by headless runs such as -tournament")
+	topicLimit := flag.Int("topic-limit", defaultTopicLimit, "Longest topic accepted, in characters; longer topics given with -topic are rejected and typing stops at the limit. 0 means no limit")
+	concurrency := flag.Int("concurrency", defaultConcurrency, "Most generations streaming at once, and debates run at once by -tournament (0 has no limit)")
+	simultaneous := flag.Bool("simultaneous", false, "Have both models answer each round at once and reveal their turns together")
+	turns := flag.Int("turns", defaultHeadlessTurns, "Number of turns in each headless debate")
+	maxTurns := flag.Int("max-turns", 0, "End the interactive debate after this many arguments, not counting interjections or theses (0 has no limit)")
+	judge := flag.String("judge", "", "Model that judges headless debates and scores -score-meter (defaults to -model1)")
+	scoreMeter := flag.Bool("score-meter", false, "Have the -judge model score who is ahead after each turn, shown as a meter in the footer")
+	options := optionFlags{}
+	flag.Var(options, "option", "Model option as key=value sent with every request (repeatable)")
+	temperatureList := flag.String("temperatures", "", "Comma-separated sampling temperatures, one per model in order, e.g. 0.3,0.9")
+	var stop stopFlags
+	flag.Var(&stop, "stop", "Sequence that ends a model's turn, e.g. '\\n\\n[' (repeatable)")
+	onError := flag.String("on-error", errorPolicyPause, "What to do when a turn fails: pause, continue, stop, or retry")
+	maxIdleChunks := flag.Int("max-idle-chunks", defaultMaxIdleChunks, "Fail a turn after this many consecutive empty chunks (0 disables)")
+	strictStream := flag.Bool("strict-stream", false, "Fail a turn whose stream ends without Ollama's final \"done\" chunk instead of taking it as complete")
+	proxyURL := flag.String("proxy", "", "Proxy to reach Ollama through, e.g. http://proxy.corp:3128 (default: HTTP_PROXY/HTTPS_PROXY)")
+	caFile := flag.String("ca-file", "", "PEM file of extra CA certificates to trust for an HTTPS -ollama-url")
+	userAgent := flag.String("user-agent", defaultUserAgent, "User-Agent header sent to Ollama")
+	style := flag.String("style", styleNeutral, "Debate style: neutral, formal, casual, or socratic")
+	phases := flag.String("phases", "", "Structured phases: standard, or a comma-separated list of opening, rebuttal, cross-examination, closing")
+	ratio := flag.String("turn-ratio", "1:1", "Consecutive turns per round for model1:model2, e.g. 2:1")
+	timeFormat := flag.String("time-format", defaultTimeFormat, "Go time layout for turn timestamps")
+	timezone := flag.String("timezone", "", "IANA timezone for turn timestamps, e.g. UTC or Europe/Warsaw (default local)")
+	serve := flag.String("serve", "", "Run a headless debate on -topic and stream it as JSON over a websocket at this address, e.g. :8080")
+	injectDate := flag.Bool("inject-date", false, "Tell models today's date at the start of every prompt")
+	historyCap := flag.Int("history-cap", 0, "Keep only the last N turns in memory, writing older ones to -output as they are dropped (0 keeps all)")
+	safetyWordlist := flag.String("safety-wordlist", "", "File of words to redact from turns, one per line; turns containing them are flagged")
+	firstMessage := flag.String("first-message", "", "Opening statement used as model1's first turn instead of generating one")
+	recordErrors := flag.Bool("record-errors", false, "Keep a marked placeholder turn in the transcript for each failed generation")
+	pageTurns := flag.Int("page-turns", 0, "Show the debate a page of N turns at a time, moving between pages with PgUp/PgDn (0 shows all)")
+	viewTurns := flag.Int("view-turns", 0, "Show only the last N turns in the view; prompts and exports still use every turn (0 shows all)")
+	debug := flag.Bool("debug", false, "Show the raw bytes of the last streamed chunk, to diagnose encoding issues")
+	verbose := flag.Bool("verbose", false, "Log each finished generation's model, duration, token count and done reason to stderr, one logfmt line each; held until exit when stderr is the TUI's terminal")
+	echoPrompt := flag.Bool("echo-prompt", false, "Include the prompt sent for each turn in exports")
+	streamOutput := flag.Bool("stream-output", false, "Append each turn to -output as soon as it finishes instead of writing at the end")
+	compare := flag.Bool("compare", false, "Show both models' answers to -topic side by side instead of debating")
+	language := flag.String("language", defaultLanguage, "Language of prompt instructions: "+strings.Join(supportedLanguages(), ", "))
+	scenarioFile := flag.String("scenario", "", "JSON file describing a complete debate: models, stances, temperatures, topic, turns, theme, and Ollama URL")
+	ollamaURL := flag.String("ollama-url", defaultOllamaURL, "Base URL of the Ollama server, including any path prefix it is mounted under")
+	diff := flag.String("diff", "", "Compare this saved .jsonl transcript with the one given as an argument: -diff A.jsonl B.jsonl")
+	fixtureFile := flag.String("fixture", "", "JSON file of recorded responses to play back instead of contacting Ollama, for offline demos")
+	record := flag.String("record", "", "Record every response, chunk by chunk with its timing, to this file as a fixture -fixture can play back")
+	theses := flag.Bool("theses", false, "Have each model state a one-sentence thesis before its first argument")
+	reinforceStance := flag.Bool("reinforce-stance", false, "Remind each model of the side it argues on every turn, not just its first, so it does not drift in long debates")
+	requireEngagement := flag.Bool("require-engagement", false, "Ask a model once more, for a direct rebuttal, when its turn does not take up the opponent's last point")
+	shuffleRounds := flag.Bool("shuffle-rounds", false, "Draw at random which model opens each round instead of model1 always opening")
+	shuffleSeed := flag.Int64("shuffle-seed", 0, "Seed for -shuffle-rounds, to reproduce a speaking order (0 picks one and prints it)")
+	colorBy := flag.String("color-by", colorByModel, "Color turns by model, or by stance so the pro side always has one color and the con side the other")
+	chat := flag.Bool("chat", false, "Send arguments to Ollama's chat API as system, user and assistant messages instead of one prompt")
+	compactHistory := flag.Bool("compact-history", false, "Send models the previous discussion one turn per line with whitespace collapsed, to save tokens; the view and exports are unchanged")
+	historyAsSystem := flag.Bool("history-as-system", false, "With -chat, fold all but the latest exchange into the system message instead of sending each turn as a message")
+	assertDivergence := flag.Bool("assert-divergence", false, "Fail a headless debate if both models' opening statements are identical")
+	autosaveInterval := flag.Duration("autosave-interval", 0, "Rewrite -output with the transcript this often, e.g. 30s (0 saves only at the end)")
+	replay := flag.String("replay", "", "Play back this saved .jsonl transcript one turn at a time instead of debating")
+	replayDelay := flag.Duration("replay-delay", defaultReplayDelay, "Pause between turns when replaying with -replay; +/- change it during playback")
+	diffLayout := flag.String("diff-layout", diffLayoutSideBySide, "Layout for -diff: side-by-side or unified")
+	responsePrefix := flag.String("response-prefix", "", "Text every turn must start with, such as 'CLAIM:'; models are primed with it")
+	moderator := flag.String("moderator", "", "Model that moderates, interjecting every -moderate-every turns to steer the debate")
+	moderateEvery := flag.Int("moderate-every", defaultModerateEvery, "Debate turns between moderator interjections")
+	timeline := flag.Bool("timeline", false, "Show a color-coded bar of turn lengths when the debate ends")
+	clashes := flag.Bool("clashes", false, "Highlight turns that directly rebut the previous one")
 	flag.Parse()
 
-	// Create Ollama client
-	client := NewOllamaClient("")
+	// Keep output plain when it is piped into another process
+	isTerminal := stdoutIsTerminal()
+	configureOutput(isTerminal)
 
-	// Validate both models are available
-	fmt.Printf("Validating models...\n")
-	if err := client.ValidateModel(*model1); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Model '%s' is not available.\n", *model1)
-		fmt.Fprintf(os.Stderr, "Please ensure Ollama is running and the model is installed.\n")
-		fmt.Fprintf(os.Stderr, "You can install it with: ollama pull %s\n", *model1)
+	// Fall back to plain rendering where colors are unsupported or unwanted
+	configureRendering(*noColor)
+
+	// Progress notes go to stderr when stdout carries a piped debate
+	status := io.Writer(os.Stdout)
+	if !isTerminal {
+		status = os.Stderr
+	}
+
+	// Fill in every setting not given on the command line from the scenario
+	var scenario *Scenario
+	if *scenarioFile != "" {
+		var err error
+		if scenario, err = LoadScenario(*scenarioFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := scenario.apply(flag.CommandLine); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	themeIndex, err := ParseTheme(*themeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	applyTheme(themes[themeIndex])
 
-	if err := client.ValidateModel(*model2); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Model '%s' is not available.\n", *model2)
-		fmt.Fprintf(os.Stderr, "Please ensure Ollama is running and the model is installed.\n")
-		fmt.Fprintf(os.Stderr, "You can install it with: ollama pull %s\n", *model2)
+	if *diff != "" {
+		runDiffMode(*diff, flag.Args(), *diffLayout)
+		return
+	}
+
+	switch *onError {
+	case errorPolicyPause, errorPolicyContinue, errorPolicyStop, errorPolicyRetry:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: -on-error must be pause, continue, stop, or retry\n")
+		os.Exit(1)
+	}
+
+	// Sides, stances and colors are all told apart by model tag
+	if *model1 == *model2 {
+		fmt.Fprintf(os.Stderr, "Error: -model1 and -model2 must be different models\n")
+		os.Exit(1)
+	}
+
+	// Resolve the designated pro model to its tag
+	proModel, err := resolveParticipant(*pro, *model1, *model2, *alias1, *alias2)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -pro: %v\n", err)
+		os.Exit(1)
+	}
+
+	turnRatio, err := parseTurnRatio(*ratio)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -turn-ratio: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Assign each listed temperature to the model in the same position
+	var temperatures map[string]float64
+	if *temperatureList != "" {
+		temperatures, err = ParseTemperatures(*temperatureList, []string{*model1, *model2})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -temperatures: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	timestamps, err := parseTimestampFormat(*timeFormat, *timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *maxIdleChunks < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -max-idle-chunks must not be negative\n")
+		os.Exit(1)
+	}
+	if *moderator != "" && *moderateEvery <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -moderate-every must be positive\n")
+		os.Exit(1)
+	}
+	if *record != "" && (*fixtureFile != "" || *chat || *tournament > 0 || *serve != "" || *compare) {
+		fmt.Fprintf(os.Stderr, "Error: -record cannot be combined with -fixture, -chat, -tournament, -serve or -compare\n")
+		os.Exit(1)
+	}
+	if *cacheDir != "" && !*cache {
+		fmt.Fprintf(os.Stderr, "Error: -cache-dir requires -cache\n")
+		os.Exit(1)
+	}
+	if *cache && (*chat || *fixtureFile != "") {
+		fmt.Fprintf(os.Stderr, "Error: -cache cannot be combined with -chat or -fixture\n")
+		os.Exit(1)
+	}
+	if *exitOnVerdict && *tournament == 0 {
+		fmt.Fprintf(os.Stderr, "Error: -exit-on-verdict requires -tournament\n")
+		os.Exit(1)
+	}
+	if *fixtureFile != "" && (*tournament > 0 || *compare) {
+		fmt.Fprintf(os.Stderr, "Error: -fixture cannot be combined with -tournament or -compare\n")
+		os.Exit(1)
+	}
+	if *theses && *simultaneous {
+		fmt.Fprintf(os.Stderr, "Error: -theses cannot be combined with -simultaneous\n")
+		os.Exit(1)
+	}
+	if *requireEngagement && *simultaneous {
+		fmt.Fprintf(os.Stderr, "Error: -require-engagement cannot be combined with -simultaneous\n")
+		os.Exit(1)
+	}
+	if *shuffleRounds && *simultaneous {
+		fmt.Fprintf(os.Stderr, "Error: -shuffle-rounds cannot be combined with -simultaneous\n")
+		os.Exit(1)
+	}
+	if *colorBy != colorByModel && *colorBy != colorByStance {
+		fmt.Fprintf(os.Stderr, "Error: -color-by must be %s or %s\n", colorByModel, colorByStance)
+		os.Exit(1)
+	}
+	if *historyAsSystem && !*chat {
+		fmt.Fprintf(os.Stderr, "Error: -history-as-system requires -chat\n")
+		os.Exit(1)
+	}
+	if *chat && (*simultaneous || *fixtureFile != "") {
+		fmt.Fprintf(os.Stderr, "Error: -chat cannot be combined with -simultaneous or -fixture\n")
+		os.Exit(1)
+	}
+	if *concurrency < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -concurrency must not be negative\n")
+		os.Exit(1)
+	}
+	if *maxSentences < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -max-sentences must not be negative\n")
+		os.Exit(1)
+	}
+	if *softLength < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -soft-length must not be negative\n")
+		os.Exit(1)
+	}
+	if *softLengthReminder && *softLength == 0 {
+		fmt.Fprintf(os.Stderr, "Error: -soft-length-reminder requires -soft-length\n")
 		os.Exit(1)
 	}
+	if *maxTurns < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -max-turns must not be negative\n")
+		os.Exit(1)
+	}
+	if *topicLimit < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -topic-limit must not be negative\n")
+		os.Exit(1)
+	}
+	if err := checkTopicLength(*topic, *topicLimit); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *maxWidth < 0 || (*maxWidth > 0 && *maxWidth < *minWidth) {
+		fmt.Fprintf(os.Stderr, "Error: -max-width must be 0 or at least -min-width (%d)\n", *minWidth)
+		os.Exit(1)
+	}
+	if *pageTurns < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -page-turns must not be negative\n")
+		os.Exit(1)
+	}
+	if *pageTurns > 0 && *viewTurns > 0 {
+		fmt.Fprintf(os.Stderr, "Error: -page-turns cannot be combined with -view-turns\n")
+		os.Exit(1)
+	}
+	if *historyCap < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -history-cap must not be negative\n")
+		os.Exit(1)
+	}
+	if *autosaveInterval < 0 {
+		fmt.Fprintf(os.Stderr, "Error: -autosave-interval must not be negative\n")
+		os.Exit(1)
+	}
+	if *autosaveInterval > 0 && *output == "" {
+		fmt.Fprintf(os.Stderr, "Error: -autosave-interval requires -output\n")
+		os.Exit(1)
+	}
+	if *autosaveInterval > 0 && (*historyCap > 0 || *streamOutput) {
+		fmt.Fprintf(os.Stderr, "Error: -autosave-interval cannot be combined with -history-cap or -stream-output, which already write -output as the debate runs\n")
+		os.Exit(1)
+	}
+
+	// Turns dropped by the history cap or streamed by -stream-output are
+	// written out as they go, which HTML pages do not support
+	outputPath := *output
+	incremental := (*historyCap > 0 || *streamOutput) && outputPath != ""
+	if incremental {
+		if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+			outputPath = filepath.Join(outputPath, debateFileName(*model1, *model2, time.Now(), ".txt"))
+		}
+		switch strings.ToLower(filepath.Ext(outputPath)) {
+		case ".html", ".htm":
+			fmt.Fprintf(os.Stderr, "Error: -history-cap and -stream-output can only write text or .jsonl -output files\n")
+			os.Exit(1)
+		}
+	}
+
+	// Play back a saved debate instead of running one if requested
+	if *replay != "" {
+		runReplayMode(*replay, *replayDelay, &debateModel{
+			timestamps:      timestamps,
+			minContentWidth: *minWidth,
+			maxContentWidth: *maxWidth,
+		})
+		return
+	}
+
+	// Build prompt options, loading few-shot examples if requested
+	var promptOptions PromptOptions
+	promptOptions.Style, err = ParseStyle(*style)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -style: %v\n", err)
+		os.Exit(1)
+	}
+	promptOptions.Phases, err = ParsePhaseSchedule(*phases)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -phases: %v\n", err)
+		os.Exit(1)
+	}
+	if *examplesFile != "" {
+		examples, err := LoadExamples(*examplesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		promptOptions.Examples = examples
+	}
+	promptOptions.Language, err = ParseLanguage(*language)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -language: %v\n", err)
+		os.Exit(1)
+	}
+	promptOptions.ResponsePrefix = strings.TrimSpace(*responsePrefix)
+	promptOptions.CompactHistory = *compactHistory
+	if *injectDate {
+		promptOptions.Date = time.Now()
+		if timestamps.location != nil {
+			promptOptions.Date = promptOptions.Date.In(timestamps.location)
+		}
+	}
+
+	// Load the safety filter if requested
+	var filter contentFilter
+	if *safetyWordlist != "" {
+		wordlist, err := LoadWordlist(*safetyWordlist)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		filter = wordlist.filterContent
+	}
+
+	// Create Ollama client
+	client := NewOllamaClient(*ollamaURL)
+	if *proxyURL != "" || *caFile != "" {
+		transport, err := NewTransport(*proxyURL, *caFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		client.SetTransport(transport)
+	}
+	if len(options) > 0 {
+		client.SetOptions(options)
+	}
+	client.SetStop(stop)
+	client.SetUserAgent(*userAgent)
+	client.SetMaxIdleChunks(*maxIdleChunks)
+	client.SetStrictStream(*strictStream)
+	client.SetConcurrency(*concurrency)
+	client.SetRateLimitHandler(func(wait time.Duration) {
+		fmt.Fprintf(status, "⚠ Rate limited, retrying in %s\n", formatRetryDelay(wait))
+	})
+	if *debug {
+		client.SetBackpressureHandler(defaultBackpressureThreshold, func(modelName string, blocked time.Duration) {
+			fmt.Fprintf(os.Stderr, "🐞 %s\n", formatBackpressure(modelName, blocked))
+		})
+	}
+	var vlog *verboseLog
+	if *verbose {
+		vlog = newVerboseLog(os.Stderr)
+		client.SetDoneHandler(func(modelName string, stats GenerationStats) {
+			vlog.log(formatVerboseLine(time.Now(), modelName, stats))
+		})
+	}
+	if scenario != nil {
+		for modelName, modelOptions := range scenario.modelOptions(*model1, *model2) {
+			client.SetModelOptions(modelName, modelOptions)
+		}
+	}
+	for modelName, temperature := range temperatures {
+		client.SetModelOptions(modelName, map[string]interface{}{"temperature": temperature})
+	}
+
+	// Check the server has the models, unless a fixture stands in for it
+	var generator Generator
+	if *fixtureFile != "" {
+		fixture, err := LoadFixture(*fixtureFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		generator = newFixtureGenerator(fixture)
+		fmt.Fprintf(status, "✓ Playing back %d recorded responses from %s\n\n", len(fixture.Responses), *fixtureFile)
+	} else {
+		validateServer(client, status, *model1, *model2, *moderator)
+		if *scoreMeter && *judge != "" {
+			if err := client.ValidateModel(*judge); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Judge model '%s' is not available.\n", *judge)
+				os.Exit(1)
+			}
+		}
+	}
+	if *cache {
+		if generator, err = newCachingGenerator(client, client.OptionsFor, *cacheDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Record what the models say, behind any cache
+	var recorder *recordingGenerator
+	if *record != "" {
+		var next Generator = client
+		if generator != nil {
+			next = generator
+		}
+		recorder = newRecordingGenerator(next)
+		generator = recorder
+	}
+
+	// Score the debate after each turn with the judge
+	scoreModel := ""
+	if *scoreMeter {
+		scoreModel = *judge
+		if scoreModel == "" {
+			scoreModel = *model1
+		}
+	}
 
-	fmt.Printf("✓ Models validated: %s and %s\n\n", *model1, *model2)
+	// Pace the reveal of streamed text if requested
+	var tw typewriter
+	if *typewriterOn {
+		tw.cps = *typewriterCPS
+	}
+
+	frames, err := parseThinkingFrames(*thinkingFrames)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -thinking-frames: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Draw each round's opener from a seed that can be given again
+	var shuffle roundShuffle
+	if *shuffleRounds {
+		seed := *shuffleSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		shuffle = newRoundShuffle(seed)
+		fmt.Fprintf(status, "✓ Shuffling round openers with -shuffle-seed %d\n", seed)
+	}
 
 	// Create initial model with validated models
 	initialModel := debateModel{
-		model1Name:   *model1,
-		model2Name:   *model2,
-		ollamaClient: client,
-		currentTurn:  0,
-		history:      []Turn{},
-		state:        stateInput,
+		model1Name:    *model1,
+		model2Name:    *model2,
+		model1Alias:   *alias1,
+		model2Alias:   *alias2,
+		ollamaClient:  client,
+		generator:     generator,
+		promptOptions: promptOptions,
+		proModel:      proModel,
+		currentTurn:   0,
+		turnRatio:     turnRatio,
+		timestamps:    timestamps,
+		history:       []Turn{},
+		historyCap:    *historyCap,
+		viewTurns:     *viewTurns,
+		pageTurns:     *pageTurns,
+		state:         stateInput,
+
+		trimBoilerplate:     *trimBoilerplate,
+		maxSentences:        *maxSentences,
+		softLength:          *softLength,
+		lengthReminder:      *softLengthReminder,
+		boilerplatePrefixes: parsePrefixList(*boilerplate),
+		firstMessage:        strings.TrimSpace(*firstMessage),
+		contentFilter:       filter,
+		endOnConsensus:      *endOnConsensus,
+		maxTurns:            *maxTurns,
+		initialTopic:        *topic,
+		topicLimit:          *topicLimit,
+		onError:             *onError,
+		recordErrors:        *recordErrors,
+		echoPrompt:          *echoPrompt,
+		debug:               *debug,
+		simultaneous:        *simultaneous,
+		requireEngagement:   *requireEngagement,
+		reinforceStance:     *reinforceStance,
+		assertDivergence:    *assertDivergence,
+		theses:              *theses,
+		chat:                *chat,
+		shuffle:             shuffle,
+		scoreModel:          scoreModel,
+		colorBy:             *colorBy,
+		historyAsSystem:     *historyAsSystem,
+
+		typewriter: tw,
+		thinking:   thinkingIndicator{frames: frames},
+
+		minContentWidth:  *minWidth,
+		maxContentWidth:  *maxWidth,
+		themeIndex:       themeIndex,
+		highlightClashes: *clashes,
+		showTimeline:     *timeline,
+		moderator:        *moderator,
+		moderateEvery:    *moderateEvery,
+	}
+
+	// Run a headless tournament instead of the TUI if requested
+	if *tournament > 0 {
+		runTournamentMode(client, &initialModel, *topic, *judge, *tournament, *turns, *concurrency, *exitOnVerdict)
+		return
+	}
+
+	// Stream a headless debate to websocket clients if requested
+	if *serve != "" {
+		runServeMode(&initialModel, *serve, *topic, *turns)
+		return
+	}
+
+	// Compare both models' answers instead of debating if requested
+	if *compare {
+		runCompareMode(&initialModel, *topic)
+		return
+	}
+
+	// Print the debate as plain text when stdout is not a terminal
+	if !isTerminal {
+		runPipeMode(client, &initialModel, *topic, *turns, *output, *judge, status)
+		saveRecording(recorder, *record, status)
+		return
+	}
+
+	// Write turns to the transcript as they finish or are dropped, in the
+	// background so the interface never waits on the disk
+	var transcript *transcriptSink
+	var transcriptQueue *asyncSink
+	if incremental {
+		transcript = &transcriptSink{path: outputPath, stamps: timestamps, live: *streamOutput}
+		transcriptQueue = newAsyncSink(transcript)
+		initialModel.sink = transcriptQueue
+	}
+
+	// Save the transcript periodically as the debate runs, to the same file
+	// the final export replaces
+	if *autosaveInterval > 0 {
+		initialModel.autosavePath = outputPath
+		if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+			initialModel.autosavePath = filepath.Join(outputPath, debateFileName(*model1, *model2, time.Now(), ".txt"))
+		}
+		initialModel.autosaveInterval = *autosaveInterval
 	}
 
 	// Configure and run Bubbletea program
-	p := tea.NewProgram(&initialModel, tea.WithAltScreen())
+	// Panics are recovered here rather than by Bubbletea so we can restore
+	// the terminal ourselves and exit non-zero
+	p := tea.NewProgram(&initialModel, tea.WithAltScreen(), tea.WithoutCatchPanics())
+	client.SetRateLimitHandler(func(wait time.Duration) { p.Send(rateLimitedMsg{wait: wait}) })
+	if transcript != nil {
+		transcript.onError = func(err error) { p.Send(outputWarningMsg{err: err}) }
+	}
+	if *debug {
+		client.SetBackpressureHandler(defaultBackpressureThreshold, func(modelName string, blocked time.Duration) {
+			p.Send(backpressureMsg{modelName: modelName, blocked: blocked})
+		})
+		client.SetRawChunkHandler(func(_ string, raw []byte) {
+			p.Send(rawChunkMsg{raw: raw})
+		})
+	}
+
+	// Keep -verbose lines off the screen the TUI is drawing on
+	if vlog != nil && stderrIsTerminal() {
+		vlog.hold()
+	}
 
 	// Run program and handle exit
-	if _, err := p.Run(); err != nil {
+	var finalModel tea.Model
+	err = runWithRecovery(os.Stdout, func() { _ = p.ReleaseTerminal() }, func() error {
+		var runErr error
+		finalModel, runErr = p.Run()
+		return runErr
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Let any generation still in flight wind down before exiting
+	if m, ok := finalModel.(*debateModel); ok {
+		m.shutdown()
+	}
+	saveRecording(recorder, *record, status)
+	if transcriptQueue != nil {
+		transcriptQueue.Close()
+	}
+	if vlog != nil {
+		vlog.release()
+	}
+
+	// Finish a transcript that was written as the debate ran
+	if m, ok := finalModel.(*debateModel); ok && transcript != nil && (m.prunedTurns > 0 || len(m.history) > 0) {
+		if err := transcript.finish(m.topic, m.exportHistory()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Debate written to %s\n", outputPath)
+		return
+	}
+
+	// Export the transcript if requested
+	if m, ok := finalModel.(*debateModel); ok && *output != "" && len(m.history) > 0 {
+		path := *output
+		export := ExportFile
+
+		// Name the file after the models when given a directory
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, debateFileName(m.model1Name, m.model2Name, time.Now(), ".txt"))
+		}
+
+		// Replace the last autosave without risking it
+		if m.autosavePath != "" {
+			path, export = m.autosavePath, ExportFileAtomic
+		}
+
+		if err := export(path, m.topic, m.exportMeta(), m.exportHistory(), m.timestamps); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Debate written to %s\n", path)
+	}
+}
+
+// validateServer checks that Ollama is reachable and has the debate's
+// models, reporting its version, and exits when a model is missing
+func validateServer(client *OllamaClient, status io.Writer, model1, model2, moderator string) {
+	// Validate both models are available
+	fmt.Fprintf(status, "Validating models...\n")
+	if err := client.EnsureModelsInstalled(); err != nil {
+		if errors.Is(err, ErrNoModelsInstalled) {
+			fmt.Fprintf(os.Stderr, "Error: No models installed; run 'ollama pull <model>'.\n")
+			fmt.Fprintf(os.Stderr, "For this debate you can run: ollama pull %s && ollama pull %s\n", model1, model2)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Please ensure Ollama is running.\n")
+		}
+		os.Exit(1)
+	}
+
+	if err := client.ValidateModel(model1); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Model '%s' is not available.\n", model1)
+		fmt.Fprintf(os.Stderr, "Please ensure Ollama is running and the model is installed.\n")
+		fmt.Fprintf(os.Stderr, "You can install it with: ollama pull %s\n", model1)
+		os.Exit(1)
+	}
+
+	if err := client.ValidateModel(model2); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Model '%s' is not available.\n", model2)
+		fmt.Fprintf(os.Stderr, "Please ensure Ollama is running and the model is installed.\n")
+		fmt.Fprintf(os.Stderr, "You can install it with: ollama pull %s\n", model2)
+		os.Exit(1)
+	}
+
+	if moderator != "" {
+		if err := client.ValidateModel(moderator); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Moderator model '%s' is not available.\n", moderator)
+			fmt.Fprintf(os.Stderr, "You can install it with: ollama pull %s\n", moderator)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Fprintf(status, "✓ Models validated: %s and %s\n", model1, model2)
+
+	// Report the server version to help diagnose API differences
+	if version, err := client.Version(); err == nil {
+		fmt.Fprintf(status, "✓ Ollama version %s\n\n", version)
+	} else {
+		fmt.Fprintf(status, "⚠ Could not determine the Ollama version: %v\n\n", err)
+	}
+}
+
+// resolveParticipant maps a model tag or alias to the tag of one of the two
+// debating models. An empty name resolves to an empty tag.
+func resolveParticipant(name, model1, model2, alias1, alias2 string) (string, error) {
+	switch {
+	case name == "":
+		return "", nil
+	case name == model1 || (alias1 != "" && name == alias1):
+		return model1, nil
+	case name == model2 || (alias2 != "" && name == alias2):
+		return model2, nil
+	}
+	return "", fmt.Errorf("'%s' is not one of the debating models (%s, %s)", name, model1, model2)
+}
+
+// runTournamentMode runs a headless tournament, prints the tally, and exits
+// on error. With exitOnVerdict, the exit code reports the outcome.
+func runTournamentMode(client *OllamaClient, template *debateModel, topic, judge string, n, turns, workers int, exitOnVerdict bool) {
+	if strings.TrimSpace(topic) == "" {
+		fmt.Fprintf(os.Stderr, "Error: -tournament requires -topic\n")
+		os.Exit(1)
+	}
+	if judge == "" {
+		judge = template.model1Name
+	} else if err := client.ValidateModel(judge); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Judge model '%s' is not available.\n", judge)
+		os.Exit(1)
+	}
+
+	template.topic = topic
+	fmt.Printf("Running %d debates on \"%s\" judged by %s...\n", n, topic, judge)
+
+	winners, err := runTournament(context.Background(), *template, judge, n, turns, workers, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if exitOnVerdict {
+			os.Exit(exitNoVerdict)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n%s", formatTournamentSummary(template, winners))
+	if exitOnVerdict {
+		os.Exit(tournamentOutcome(winners, template.model1Name, template.model2Name).exitCode())
+	}
+}
+
+// runServeMode streams a headless debate to websocket clients and exits on
+// error
+func runServeMode(m *debateModel, addr, topic string, turns int) {
+	if strings.TrimSpace(topic) == "" {
+		fmt.Fprintf(os.Stderr, "Error: -serve requires -topic\n")
+		os.Exit(1)
+	}
+
+	m.topic = topic
+	if err := serveDebate(context.Background(), m, addr, turns, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Debate finished after %d turns\n", len(m.history))
+}
+
+// runPipeMode writes a headless debate to stdout as plain text, a whole
+// turn at a time, and exits on error. The debate is also saved to output
+// and given a verdict by judge, when set.
+func runPipeMode(client *OllamaClient, m *debateModel, topic string, turns int, output, judge string, status io.Writer) {
+	if strings.TrimSpace(topic) == "" {
+		fmt.Fprintf(os.Stderr, "Error: -topic is required when output is not a terminal\n")
+		os.Exit(1)
+	}
+	if judge != "" {
+		if err := client.ValidateModel(judge); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Judge model '%s' is not available.\n", judge)
+			os.Exit(1)
+		}
+	}
+
+	m.topic = topic
+	m.sink = &textSink{w: os.Stdout, stamps: m.timestamps}
+	m.ollamaClient.SetRateLimitHandler(func(wait time.Duration) {
+		fmt.Fprintf(os.Stderr, "⚠ Rate limited, retrying in %s\n", formatRetryDelay(wait))
+	})
+	path, err := pipeDebate(context.Background(), m, turns, os.Stdout, output, judge)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if path != "" {
+		fmt.Fprintf(status, "✓ Debate written to %s\n", path)
+	}
+}
+
+// pipeDebate runs a headless debate through m's sink, then saves it to
+// output, a file or directory, and writes judge's verdict on it to w, when
+// set. It returns the path the debate was saved to.
+func pipeDebate(ctx context.Context, m *debateModel, turns int, w io.Writer, output, judge string) (string, error) {
+	result := runHeadless(ctx, m, turns)
+	if result.Err != nil {
+		return "", result.Err
+	}
+
+	path := ""
+	if output != "" && len(result.Turns) > 0 {
+		path = output
+		// Name the file after the models when given a directory
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, debateFileName(m.model1Name, m.model2Name, time.Now(), ".txt"))
+		}
+		if err := ExportFile(path, m.topic, m.exportMeta(), result.Turns, m.timestamps); err != nil {
+			return "", err
+		}
+	}
+
+	if judge != "" {
+		verdict, err := JudgeDebate(ctx, m.ollamaClient, judge, m)
+		if err != nil {
+			return path, err
+		}
+		winner := "undecided"
+		if verdict != "" {
+			winner = m.displayName(verdict)
+		}
+		fmt.Fprintf(w, "Verdict (%s): %s\n", judge, winner)
+	}
+	return path, nil
+}
+
+// saveRecording writes the responses recorded by -record to path, if
+// recording, and exits on error
+func saveRecording(recorder *recordingGenerator, path string, status io.Writer) {
+	if recorder == nil {
+		return
+	}
+	n, err := recorder.WriteFixture(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(status, "✓ Recorded %d responses to %s\n", n, path)
+}
+
+// runDiffMode prints a turn-by-turn comparison of two saved transcripts and
+// exits on error
+func runDiffMode(pathA string, args []string, layout string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: -diff takes two transcripts: -diff A.jsonl B.jsonl\n")
+		os.Exit(1)
+	}
+	if layout != diffLayoutSideBySide && layout != diffLayoutUnified {
+		fmt.Fprintf(os.Stderr, "Error: -diff-layout must be side-by-side or unified\n")
+		os.Exit(1)
+	}
+
+	d := transcriptDiff{NameA: pathA, NameB: args[0]}
+	var err error
+	if d.TopicA, d.A, err = LoadTranscript(d.NameA); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if d.TopicB, d.B, err = LoadTranscript(d.NameB); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(d.FormatDiff(layout, defaultDiffWidth))
+}
+
+// runReplayMode plays back a saved transcript, rendered with m's width and
+// timestamp settings, and exits on error
+func runReplayMode(path string, delay time.Duration, m *debateModel) {
+	if delay <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: -replay-delay must be positive\n")
+		os.Exit(1)
+	}
+	topic, turns, err := LoadTranscript(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(turns) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: %s has no turns to replay\n", path)
+		os.Exit(1)
+	}
+
+	p := tea.NewProgram(newReplayModel(m, topic, turns, delay), tea.WithAltScreen(), tea.WithoutCatchPanics())
+	err = runWithRecovery(os.Stdout, func() { _ = p.ReleaseTerminal() }, func() error {
+		_, runErr := p.Run()
+		return runErr
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runCompareMode shows both models answering the topic side by side and
+// exits on error
+func runCompareMode(m *debateModel, topic string) {
+	if strings.TrimSpace(topic) == "" {
+		fmt.Fprintf(os.Stderr, "Error: -compare requires -topic\n")
+		os.Exit(1)
+	}
+
+	cm := newCompareModel(m, topic)
+	p := tea.NewProgram(cm, tea.WithAltScreen(), tea.WithoutCatchPanics())
+	m.ollamaClient.SetRateLimitHandler(func(wait time.Duration) { p.Send(rateLimitedMsg{wait: wait}) })
+	err := runWithRecovery(os.Stdout, func() { _ = p.ReleaseTerminal() }, func() error {
+		_, runErr := p.Run()
+		return runErr
+	})
+	cm.shutdown()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveParticipant(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"phi3:mini", "phi3:mini", false},
+		{"Skeptic", "gemma3:4b", false},
+		{"llama2:13b", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveParticipant(tt.name, "phi3:mini", "gemma3:4b", "Optimist", "Skeptic")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveParticipant(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("resolveParticipant(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunWithRecovery_Panic(t *testing.T) {
+	var out bytes.Buffer
+	restored := false
+
+	err := runWithRecovery(&out, func() { restored = true }, func() error {
+		panic("renderer exploded")
+	})
+	if err == nil || !strings.Contains(err.Error(), "renderer exploded") {
+		t.Fatalf("Expected the panic to be returned as an error, got %v", err)
+	}
+	if !restored {
+		t.Errorf("Expected the terminal to be released")
+	}
+	if out.String() != restoreTerminalSequence {
+		t.Errorf("Expected alt screen exit and cursor show sequences, got %q", out.String())
+	}
+}
+
+func TestRunWithRecovery_NoPanic(t *testing.T) {
+	var out bytes.Buffer
+	wantErr := errors.New("program failed")
+
+	err := runWithRecovery(&out, nil, func() error { return wantErr })
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Expected the run error to pass through, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no terminal output without a panic, got %q", out.String())
+	}
+}
@@ -1,5 +1,7 @@
 package main
 
+import "time"
+
 // topicSubmittedMsg is sent when the user submits a topic
 type topicSubmittedMsg struct {
 	topic string
@@ -12,14 +14,23 @@ type responseChunkMsg struct {
 	errorChan    <-chan error
 }
 
-// responseCompleteMsg is sent when a response is complete
+// responseCompleteMsg is sent when a response is complete. It carries no
+// text: every chunk, including any text on Ollama's final done chunk, has
+// already arrived as a responseChunkMsg and been added to the turn.
 type responseCompleteMsg struct {
-	fullResponse string
+	responseChan <-chan string // Stream that completed
 }
 
 // responseErrorMsg is sent when an error occurs during generation
 type responseErrorMsg struct {
-	err error
+	err          error
+	responseChan <-chan string // Stream that failed
+}
+
+// rateLimitedMsg is sent when a request was rate limited and will be
+// retried after wait
+type rateLimitedMsg struct {
+	wait time.Duration
 }
 
 // nextTurnMsg is sent to trigger the next turn
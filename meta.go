@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DebateMeta describes a debate so that an archived transcript identifies
+// itself: which debate it is, when it began, who took part and how it was
+// set up
+type DebateMeta struct {
+	ID        string            `json:"id"`
+	StartedAt time.Time         `json:"started_at"`
+	Models    []string          `json:"models"`
+	Settings  map[string]string `json:"settings,omitempty"` // Settings that differ from the defaults, by flag name
+}
+
+// newDebateID returns a random identifier for a debate, 16 hex digits long.
+// Should the system's randomness fail, the current time stands in.
+func newDebateID() string {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// newMeta describes the debate m is about to run
+func (m *debateModel) newMeta() DebateMeta {
+	meta := DebateMeta{
+		ID:        newDebateID(),
+		StartedAt: time.Now(),
+		Models:    []string{m.model1Name, m.model2Name},
+		Settings:  make(map[string]string),
+	}
+	set := func(name, value string) {
+		if value != "" {
+			meta.Settings[name] = value
+		}
+	}
+
+	set("pro", m.proModel)
+	set("language", m.promptOptions.Language)
+	set("style", m.promptOptions.Style)
+	set("response-prefix", m.promptOptions.ResponsePrefix)
+	set("moderator", m.moderator)
+	if m.moderator != "" {
+		set("moderate-every", strconv.Itoa(m.moderateEvery))
+	}
+	if m.maxTurns > 0 {
+		set("max-turns", strconv.Itoa(m.maxTurns))
+	}
+	if m.maxSentences > 0 {
+		set("max-sentences", strconv.Itoa(m.maxSentences))
+	}
+	if m.softLength > 0 {
+		set("soft-length", strconv.Itoa(m.softLength))
+	}
+	if m.shuffle.enabled() {
+		set("shuffle-seed", strconv.FormatInt(m.shuffle.seed, 10))
+	}
+	if m.turnRatio.model1 > 1 || m.turnRatio.model2 > 1 {
+		set("turn-ratio", fmt.Sprintf("%d:%d", m.turnRatio.model1, m.turnRatio.model2))
+	}
+	for name, on := range map[string]bool{
+		"theses":               m.theses,
+		"simultaneous":         m.simultaneous,
+		"chat":                 m.chat,
+		"history-as-system":    m.historyAsSystem,
+		"require-engagement":   m.requireEngagement,
+		"reinforce-stance":     m.reinforceStance,
+		"compact-history":      m.promptOptions.CompactHistory,
+		"soft-length-reminder": m.lengthReminder,
+	} {
+		if on {
+			set(name, "true")
+		}
+	}
+	if len(meta.Settings) == 0 {
+		meta.Settings = nil
+	}
+	return meta
+}
+
+// startMeta gives the debate starting now its metadata and reports it to
+// the sink
+func (m *debateModel) startMeta() {
+	m.meta = m.newMeta()
+	if m.sink != nil {
+		m.sink.Send(DebateEvent{Type: eventMeta, Topic: m.topic, Meta: &m.meta, Timestamp: m.meta.StartedAt})
+	}
+}
+
+// exportMeta returns the metadata to head exports with, or nil before a
+// debate has started
+func (m *debateModel) exportMeta() *DebateMeta {
+	if m.meta.ID == "" {
+		return nil
+	}
+	return &m.meta
+}
+
+// settingsList returns the settings as "name=value" pairs sorted by name
+func (meta *DebateMeta) settingsList() []string {
+	list := make([]string, 0, len(meta.Settings))
+	for name, value := range meta.Settings {
+		list = append(list, name+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// writeText writes the metadata header of a plain-text export
+func (meta *DebateMeta) writeText(b *strings.Builder) {
+	b.WriteString(fmt.Sprintf("Debate ID: %s\n", meta.ID))
+	b.WriteString(fmt.Sprintf("Started: %s\n", meta.StartedAt.Format(time.RFC3339)))
+	b.WriteString(fmt.Sprintf("Models: %s\n", strings.Join(meta.Models, " vs ")))
+	if settings := meta.settingsList(); len(settings) > 0 {
+		b.WriteString(fmt.Sprintf("Settings: %s\n", strings.Join(settings, ", ")))
+	}
+	b.WriteString("\n")
+}
+
+// writeHTML writes the metadata header of an HTML export
+func (meta *DebateMeta) writeHTML(b *strings.Builder) {
+	b.WriteString("<dl class=\"meta\">\n")
+	rows := [][2]string{
+		{"Debate ID", meta.ID},
+		{"Started", meta.StartedAt.Format(time.RFC3339)},
+		{"Models", strings.Join(meta.Models, " vs ")},
+	}
+	if settings := meta.settingsList(); len(settings) > 0 {
+		rows = append(rows, [2]string{"Settings", strings.Join(settings, ", ")})
+	}
+	for _, row := range rows {
+		b.WriteString(fmt.Sprintf("<dt>%s</dt><dd>%s</dd>\n", row[0], html.EscapeString(row[1])))
+	}
+	b.WriteString("</dl>\n")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testMeta() *DebateMeta {
+	return &DebateMeta{
+		ID:        "0123456789abcdef",
+		StartedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Models:    []string{"mistral:7b", "gemma3:4b"},
+		Settings:  map[string]string{"theses": "true", "pro": "gemma3:4b"},
+	}
+}
+
+func TestExport_IncludesMeta(t *testing.T) {
+	var text strings.Builder
+	if err := ExportText("Topic", testMeta(), exportTestHistory(), timestampFormat{}, &text); err != nil {
+		t.Fatalf("ExportText failed: %v", err)
+	}
+	for _, want := range []string{"Debate ID: 0123456789abcdef", "Started: 2024-01-01T10:00:00Z", "Models: mistral:7b vs gemma3:4b", "Settings: pro=gemma3:4b, theses=true"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected the text export to contain %q, got:\n%s", want, text.String())
+		}
+	}
+
+	var page strings.Builder
+	if err := ExportHTML("Topic", testMeta(), exportTestHistory(), timestampFormat{}, &page); err != nil {
+		t.Fatalf("ExportHTML failed: %v", err)
+	}
+	if !strings.Contains(page.String(), "<dt>Debate ID</dt><dd>0123456789abcdef</dd>") {
+		t.Errorf("Expected the HTML export to list the debate ID, got:\n%s", page.String())
+	}
+
+	var lines strings.Builder
+	if err := ExportJSONLines("Topic", testMeta(), exportTestHistory(), &lines); err != nil {
+		t.Fatalf("ExportJSONLines failed: %v", err)
+	}
+	var first DebateEvent
+	if err := json.Unmarshal([]byte(strings.SplitN(lines.String(), "\n", 2)[0]), &first); err != nil {
+		t.Fatalf("Failed to parse the first line: %v", err)
+	}
+	if first.Type != eventMeta || first.Meta == nil || first.Meta.ID != "0123456789abcdef" || first.Meta.Settings["pro"] != "gemma3:4b" {
+		t.Errorf("Expected a leading meta event, got %+v", first)
+	}
+
+	// The metadata does not get in the way of loading the transcript
+	topic, history, err := ParseTranscript(strings.NewReader(lines.String()))
+	if err != nil || topic != "Topic" || len(history) != 2 {
+		t.Errorf("Expected the transcript to load with its 2 turns, got %q, %d turns, %v", topic, len(history), err)
+	}
+}
+
+func TestExport_WithoutMeta(t *testing.T) {
+	var text strings.Builder
+	ExportText("Topic", nil, exportTestHistory(), timestampFormat{}, &text)
+	if strings.Contains(text.String(), "Debate ID") {
+		t.Errorf("Expected no metadata header without meta, got:\n%s", text.String())
+	}
+}
+
+func TestNewDebateID_Unique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := newDebateID()
+		if len(id) != 16 {
+			t.Fatalf("Expected a 16 digit ID, got %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("Expected unique IDs, got %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+// TestMeta_HeadlessRuns verifies each run gets its own metadata, which
+// reaches the result and the transcript written as the debate runs
+func TestMeta_HeadlessRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debate.jsonl")
+	var ids []string
+	for i := 0; i < 2; i++ {
+		m := &debateModel{
+			model1Name:   "phi3:mini",
+			model2Name:   "gemma3:4b",
+			topic:        "Is remote work better?",
+			maxSentences: 2,
+			generator:    newFixtureGenerator(testFixture()),
+		}
+		sink := &transcriptSink{path: path, live: true}
+		m.sink = sink
+		result := runHeadless(context.Background(), m, 2)
+		if result.Err != nil {
+			t.Fatalf("Unexpected error: %v", result.Err)
+		}
+		if err := sink.finish(m.topic, m.exportHistory()); err != nil {
+			t.Fatalf("finish failed: %v", err)
+		}
+
+		meta := result.Meta
+		if meta.ID == "" || meta.StartedAt.IsZero() || strings.Join(meta.Models, ",") != "phi3:mini,gemma3:4b" || meta.Settings["max-sentences"] != "2" {
+			t.Errorf("Expected the result to describe the debate, got %+v", meta)
+		}
+		ids = append(ids, meta.ID)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read transcript: %v", err)
+		}
+		if !strings.HasPrefix(string(data), `{"type":"meta"`) || !strings.Contains(string(data), meta.ID) {
+			t.Errorf("Expected the transcript to start with the metadata, got:\n%s", data)
+		}
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("Expected each run to get its own ID, got %q twice", ids[0])
+	}
+}
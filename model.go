@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,11 +23,49 @@ const (
 	stateError
 )
 
+// Policies for handling a failed turn
+const (
+	errorPolicyPause    = "pause"    // Pause and let the user retry with 'r'
+	errorPolicyContinue = "continue" // Move on to the opponent's turn
+	errorPolicyStop     = "stop"     // End the debate
+	errorPolicyRetry    = "retry"    // Retry the same turn automatically
+)
+
+// maxAutoRetries is how many times the retry policy re-runs a failing turn
+// before pausing
+const maxAutoRetries = 3
+
 // Turn represents a single contribution to the debate from one model
 type Turn struct {
-	ModelName string
-	Content   string
-	Timestamp time.Time
+	ModelName   string // Model tag used for API calls
+	DisplayName string // Optional friendly name shown instead of the tag
+	Content     string
+	Timestamp   time.Time
+	Flagged     bool   // Whether the safety filter redacted part of the content
+	OverLength  bool   // Whether the turn ran past the -soft-length word limit
+	Error       string // Why the generation failed, for turns recorded by -record-errors
+	Prompt      string // Prompt the turn was generated from, kept for exports by -echo-prompt
+	Moderator   bool   // Whether the turn is a moderator interjection rather than an argument
+	Thesis      bool   // Whether the turn is the one-sentence thesis stated before a model's first argument
+	Stance      string // Side the speaker defends, PositionPro or PositionCon; empty for moderator turns
+}
+
+// Speaker returns the name the turn is attributed to in views and exports,
+// made safe for the "[name]:" attribution format
+func (t Turn) Speaker() string {
+	if t.DisplayName != "" {
+		return safeName(t.DisplayName)
+	}
+	return safeName(t.ModelName)
+}
+
+// failureNote describes why a recorded error turn failed, or is empty for
+// turns that completed
+func (t Turn) failureNote() string {
+	if t.Error == "" {
+		return ""
+	}
+	return "⚠️ Turn failed: " + t.Error
 }
 
 // DebateContext represents the complete conversation context passed to models
@@ -38,20 +79,209 @@ type debateModel struct {
 	// Configuration
 	model1Name   string
 	model2Name   string
+	model1Alias  string // Optional display name for model1
+	model2Alias  string // Optional display name for model2
 	ollamaClient *OllamaClient
+	generator    Generator // Replaces ollamaClient for generating responses when set, as by -fixture
+
+	// promptOptions holds optional additions to every debate prompt
+	promptOptions PromptOptions
+
+	// proModel is the model tag arguing in favor of the topic, if designated
+	proModel string
+
+	// Response post-processing
+	trimBoilerplate     bool     // Clean completed turns before they are kept
+	boilerplatePrefixes []string // Openers stripped from completed turns
+	maxSentences        int      // Sentences kept of each completed turn (0 keeps all)
+
+	// contentFilter redacts disallowed content from completed turns, if set
+	contentFilter contentFilter
+
+	// firstMessage, if set, is model1's opening turn, used as written
+	// instead of generated
+	firstMessage string
+
+	// endOnConsensus stops the debate once a model agrees with its opponent
+	endOnConsensus bool
+
+	// moderator, if set, is the model that interjects every moderateEvery
+	// debate turns to steer the discussion
+	moderator     string
+	moderateEvery int
+	moderating    bool // Whether the current generation is a moderator interjection
+
+	// theses has each model state a one-sentence thesis before its first
+	// argument; stating is set while a thesis is generating
+	theses      bool
+	stating     bool
+	thesisTried map[string]bool // Models that have had their chance at a thesis
+
+	// outputWarning notes that the transcript file could not be written
+	outputWarning string
+
+	// autosaveWarning notes that the last autosave failed, until one
+	// succeeds
+	autosaveWarning string
+
+	// meta describes the current debate, set as it starts
+	meta DebateMeta
+
+	// scoreModel, if set, is the judge that scores who is ahead after each
+	// turn for the footer's meter; score is its latest reading, 100 meaning
+	// model1 is far ahead
+	scoreModel  string
+	score       int
+	scored      bool               // Whether a score has been read yet
+	scoreStale  bool               // Whether the latest score could not be read
+	scoreSeq    int                // Request the meter is waiting on
+	scoreCancel context.CancelFunc // Cancels the scoring in flight, if any
+
+	// shuffle draws which model opens each round when -shuffle-rounds is
+	// set
+	shuffle roundShuffle
+
+	// colorBy picks whether turns are colored by model or by stance (see
+	// colorBy*)
+	colorBy string
+
+	// chat sends arguments to the chat API as messages instead of one
+	// prompt; historyAsSystem folds all but the latest exchange into the
+	// system message
+	chat            bool
+	historyAsSystem bool
+
+	// showTimeline adds a bar of turn lengths to the finished view
+	showTimeline bool
+
+	// maxTurns stops the debate after this many turns; 0 has no limit
+	maxTurns int
+
+	// initialTopic, if set, is debated as soon as the program starts
+	// instead of asking for a topic
+	initialTopic string
+
+	// topicLimit is the longest topic that can be typed, in characters, or
+	// 0 for no limit
+	topicLimit int
+
+	// requireEngagement asks a model once more, for a direct rebuttal, when
+	// its turn ignores the opponent's last point. rebutSpeaker is the
+	// opponent named in that request while it is pending.
+	requireEngagement bool
+	rebutSpeaker      string
+
+	// reinforceStance reminds each model of its side on every turn
+	reinforceStance bool
+
+	// swap is the model replacement being typed while the debate is
+	// paused, or nil. swappedOut records the side each model swapped out
+	// argued on, so its turns keep that side's colors.
+	swap       *modelSwap
+	swappedOut map[string]int
+
+	// softLength is the word limit past which a turn is noted as over
+	// length, or 0 for none. lengthReminder asks a model that ran over it
+	// to be concise in its next turn.
+	softLength     int
+	lengthReminder bool
+
+	// onError is the policy applied when a turn fails (see errorPolicy*)
+	onError      string
+	autoRetries  int  // Automatic retries of the current turn so far
+	recordErrors bool // Keep a placeholder turn in history for each failure
+	echoPrompt   bool // Store each turn's prompt so exports can show it
+
+	// typewriter paces how quickly streamed text is revealed in the view
+	typewriter typewriter
+
+	// turnRatio sets how many consecutive turns each model takes
+	turnRatio turnRatio
+
+	// thinking animates the indicator shown while a model generates
+	thinking thinkingIndicator
 
 	// Debate state
 	topic        string
 	history      []Turn
-	currentTurn  int // 0 for model1, 1 for model2
+	historyCap   int  // Most turns kept in history; 0 keeps every turn
+	viewTurns    int  // Most recent turns shown in the view; 0 shows every turn
+	pageTurns    int  // Turns on each page of the view; 0 shows every turn on one page
+	page         int  // Page the reader moved to, counted from the first
+	pageBrowsing bool // Whether the reader is on an earlier page rather than following the debate
+	prunedTurns  int  // Turns dropped from the front of history by the cap
+	prunedArgs   int  // Arguments among the pruned turns
+	prunedUnmod  int  // Pruned arguments made since the last interjection
+	currentTurn  int  // 0 for model1, 1 for model2
+	turnStreak   int  // Turns the current model has taken in a row
+	turnStarted  bool // Whether the in-flight generation has added its turn to the history
 	isGenerating bool
+	cancel       context.CancelFunc // Cancels the in-flight generation, if any
+	stream       <-chan string      // Response stream of the in-flight generation
+	streamErrs   <-chan error       // Error channel of the in-flight generation
+	continuing   bool               // Whether the current generation extends the last turn
+	continueFrom int                // Length of the turn before the continuation began
+
+	// lifetime is the parent of every request's context. endLifetime
+	// cancels it when the program quits, so no request outlives it.
+	lifetime    context.Context
+	endLifetime context.CancelFunc
+
+	// Context window handling
+	promptBudget   int  // Max characters of history sent to models; 0 is unlimited
+	contextRetried bool // Whether the current turn was already retried with a trimmed history
 
 	// UI state
 	state      appState
 	viewport   viewport.Model
 	textInput  textinput.Model
 	errorMsg   string
-	autoscroll bool // When true, viewport automatically scrolls to bottom
+	autoscroll bool           // When true, viewport automatically scrolls to bottom
+	endReason  string         // Why the debate finished on its own, if it did
+	turnCache  []renderedTurn // Formatted turns reused between renders
+
+	// Prompt debugging
+	showPrompt bool   // Whether the prompt pane is shown below the debate
+	lastPrompt string // Prompt sent for the in-flight generation
+
+	// simultaneous has both models answer each round at once, revealing
+	// their turns together; round tracks the round in flight
+	simultaneous bool
+	round        *roundState
+
+	// assertDivergence fails headless debates whose first two arguments
+	// are identical
+	assertDivergence bool
+
+	// Encoding debugging
+	debug     bool     // Whether the raw bytes of the last chunk are shown
+	lastChunk string   // Bytes of the last chunk received, kept only when debugging
+	debugLog  []string // Recent backpressure entries, kept only when debugging
+
+	// sink receives events as the debate runs, if set. Only headless
+	// debates report streamed chunks.
+	sink OutputSink
+
+	// autosavePath, when set, is rewritten with the transcript every
+	// autosaveInterval while the program runs
+	autosavePath     string
+	autosaveInterval time.Duration
+
+	// timestamps formats turn timestamps in the view and exports
+	timestamps timestampFormat
+
+	// minContentWidth is the narrowest a turn box may wrap to
+	minContentWidth int
+
+	// maxContentWidth caps how wide a turn box grows on wide terminals; 0
+	// lets boxes use the full width
+	maxContentWidth int
+
+	// highlightClashes marks turns that directly rebut the previous one
+	highlightClashes bool
+
+	// themeIndex is the position in themes of the active color scheme
+	themeIndex int
 
 	// Dimensions
 	width  int
@@ -64,12 +294,7 @@ func (m *debateModel) Init() tea.Cmd {
 	m.textInput = textinput.New()
 	m.textInput.Placeholder = "Enter a debate topic..."
 	m.textInput.Focus()
-	m.textInput.CharLimit = 0
-	m.textInput.Width = 80
-
-	// Initialize viewport for debate view
-	m.viewport = viewport.New(80, 20)
-	m.viewport.YPosition = 0
+	m.textInput.CharLimit = m.topicLimit
 
 	// Set default dimensions (will be updated by WindowSizeMsg)
 	if m.width == 0 {
@@ -79,42 +304,143 @@ func (m *debateModel) Init() tea.Cmd {
 		m.height = 24
 	}
 
+	// Initialize viewport for debate view
+	m.viewport = viewport.New(m.width, m.height)
+	m.viewport.YPosition = 0
+	m.textInput.Width = textInputWidth(m.width, m.textInput.Prompt)
+
 	m.state = stateInput
 
-	// Return command to focus the text input
-	return textinput.Blink
+	// Start straight away when the topic was given up front, otherwise
+	// focus the text input
+	cmd := textinput.Blink
+	if strings.TrimSpace(m.initialTopic) != "" {
+		m.textInput.SetValue(m.initialTopic)
+		cmd = m.startDebate(m.initialTopic)
+	}
+
+	// Begin saving the transcript periodically if requested
+	if tick := m.autosaveTick(); tick != nil {
+		return tea.Batch(cmd, tick)
+	}
+	return cmd
 }
 
 // Update handles messages and updates the model
 func (m *debateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+
+	// Animate the thinking indicator whenever a model is generating
+	if m.isGenerating && m.thinking.animated() && !m.thinking.ticking {
+		m.thinking.ticking = true
+		cmd = tea.Batch(cmd, thinkingTick())
+	}
+
+	return model, cmd
+}
+
+// update applies a message to the model and returns the next command
+func (m *debateModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
-	ctx, cancel := context.WithCancel(context.Background())
+
+	// Messages from a simultaneous round's streams are handled as a pair
+	if m.round != nil {
+		if cmd, handled := m.updateRound(msg); handled {
+			return m, cmd
+		}
+	}
 
 	switch msg := msg.(type) {
 
 	// Handle keyboard input
 	case tea.KeyMsg:
+		// Once the debate has stopped, 'r' starts over with a new topic, the
+		// transcript can still be scrolled and autoscroll toggled, and any
+		// other key exits
+		if m.state == stateStopped {
+			switch {
+			case msg.String() == "r":
+				m.restart()
+				return m, textinput.Blink
+			case msg.String() == "a":
+				m.toggleAutoscroll()
+				return m, nil
+			case scrollKey(m.viewport.KeyMap, msg):
+				m.viewport, cmd = m.viewport.Update(msg)
+				return m, cmd
+			}
+			return m, m.quit()
+		}
+
+		// While paused to swap a model, keys go to the swap prompt
+		if m.swap != nil {
+			if msg.String() != "ctrl+c" {
+				return m, m.updateSwap(msg)
+			}
+			m.swap = nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			// Handle stop command
 			if m.state == stateDebating {
-				cancel()
+				m.cancelGeneration()
 				m.state = stateStopped
 				return m, nil
 			}
-			return m, tea.Quit
+			return m, m.quit()
 
 		case "a":
 			// Toggle autoscroll when in debating state
-			if m.state == stateDebating || m.state == stateStopped {
-				m.autoscroll = !m.autoscroll
-				if m.autoscroll {
-					m.viewport.GotoBottom()
+			if m.state == stateDebating {
+				m.toggleAutoscroll()
+				return m, nil
+			}
+
+		case "p":
+			// Toggle the pane showing the prompt for the current turn
+			if m.state == stateDebating {
+				m.showPrompt = !m.showPrompt
+				return m, nil
+			}
+
+		case "pgup", "pgdown":
+			// Move between pages of a paged debate instead of scrolling
+			if m.state == stateDebating && m.pageTurns > 0 {
+				if msg.String() == "pgup" {
+					m.turnPage(-1)
+				} else {
+					m.turnPage(1)
 				}
 				return m, nil
 			}
 
+		case "t":
+			// Switch to the next color theme
+			if m.state == stateDebating {
+				m.cycleTheme()
+				return m, nil
+			}
+
+		case "c":
+			// Ask the last speaker to elaborate on its turn
+			if m.state == stateDebating {
+				return m, m.continueTurn()
+			}
+
+		case "s":
+			// Pause to swap the model on one side for another
+			if m.canSwap() {
+				return m, m.pauseForSwap()
+			}
+
+		case "r":
+			// Retry the failed turn with the same model
+			if m.state == stateError {
+				return m, m.retryTurn()
+			}
+
 		case "enter":
 			// Handle topic submission
 			if m.state == stateInput {
@@ -125,15 +451,7 @@ func (m *debateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				// Transition to debating state
-				m.topic = topic
-				m.state = stateDebating
-				m.errorMsg = ""
-				m.isGenerating = true
-				m.currentTurn = 0 // Start with model1
-
-				// Start first model generation
-				return m, m.generateResponse(ctx)
+				return m, m.startDebate(topic)
 			}
 		}
 
@@ -148,26 +466,50 @@ func (m *debateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Resize viewport component
+		// Fit the topic input to the new width
+		m.textInput.Width = textInputWidth(msg.Width, m.textInput.Prompt)
+
+		// Resize viewport component. A size that arrives before the debate
+		// starts is kept and applied when it does.
 		if m.state == stateDebating || m.state == stateStopped {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - 5 // Leave room for header and footer
+			m.fitViewport()
 		}
 
 	// Handle response chunks
 	case responseChunkMsg:
+		if m.isStale(msg.responseChan) {
+			return m, nil
+		}
 		if m.isGenerating && m.state == stateDebating {
 			// Append chunk to current turn content
-			if len(m.history) > 0 && m.history[len(m.history)-1].ModelName == m.getNextModel() {
+			if m.continuing || m.turnStarted {
+				// Separate a continuation from the text it extends
+				last := &m.history[len(m.history)-1]
+				if m.continuing && len(last.Content) == m.continueFrom {
+					last.Content += "\n\n"
+				}
+
 				// Update the last turn if it's from the current model
-				m.history[len(m.history)-1].Content += msg.chunk
+				last.Content += msg.chunk
+			} else if m.moderating {
+				// Create the moderator's interjection
+				m.history = append(m.history, m.moderatorTurn(msg.chunk))
+				m.turnStarted = true
+			} else if m.stating {
+				// Create the model's thesis
+				m.history = append(m.history, m.thesisTurn(m.getNextModel(), msg.chunk))
+				m.turnStarted = true
 			} else {
 				// Create a new turn for this model
 				m.history = append(m.history, Turn{
-					ModelName: m.getNextModel(),
-					Content:   msg.chunk,
-					Timestamp: time.Now(),
+					ModelName:   m.getNextModel(),
+					DisplayName: m.displayName(m.getNextModel()),
+					Content:     applyResponsePrefix(m.promptOptions.ResponsePrefix, msg.chunk),
+					Timestamp:   time.Now(),
+					Prompt:      m.storedPrompt(m.lastPrompt),
+					Stance:      m.stance(m.getNextModel()),
 				})
+				m.turnStarted = true
 			}
 
 			// Autoscroll to bottom if enabled
@@ -175,40 +517,183 @@ func (m *debateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewport.GotoBottom()
 			}
 
-			// Continue listening for more chunks
-			return m, waitForNextChunk(msg.responseChan, msg.errorChan)
+			// Continue listening for more chunks, pacing the reveal if enabled
+			next := waitForNextChunk(msg.responseChan, msg.errorChan)
+			if m.typewriter.enabled() && !m.typewriter.ticking {
+				m.typewriter.ticking = true
+				return m, tea.Batch(next, typewriterTick())
+			}
+			return m, next
+		}
+
+	// Advance the thinking animation while a model is generating
+	case thinkingTickMsg:
+		if m.isGenerating {
+			m.thinking.advance()
+			return m, thinkingTick()
 		}
+		m.thinking.ticking = false
+		return m, nil
+
+	// Reveal more of the transcript at the typewriter's pace
+	case typewriterTickMsg:
+		hidden := m.typewriter.advance(typewriterInterval, transcriptLength(m.history))
+		if m.autoscroll {
+			m.viewport.GotoBottom()
+		}
+		if m.state == stateDebating && (hidden || m.isGenerating) {
+			return m, typewriterTick()
+		}
+		m.typewriter.ticking = false
+		return m, nil
+
+	// Save the transcript so far, so a crash loses at most one interval
+	case autosaveTickMsg:
+		m.autosaveWarning = ""
+		if err := m.autosave(); err != nil {
+			m.autosaveWarning = fmt.Sprintf("Autosave failed: %v", err)
+		}
+		return m, m.autosaveTick()
 
 	// Handle response completion (when channel closes)
 	case responseCompleteMsg:
+		if m.isStale(msg.responseChan) {
+			return m, nil
+		}
+		continued := m.continuing
 		m.isGenerating = false
+		m.contextRetried = false
+		m.continuing = false
+		m.autoRetries = 0
+		m.errorMsg = ""
+
+		// An interjection hands the floor back to the model whose turn it is
+		if m.moderating {
+			return m, m.finishModeration()
+		}
+
+		// A thesis is followed by the same model's argument
+		if m.stating {
+			return m, m.completeThesis()
+		}
+
+		// Clean up the finished turn before it is used as context
+		if m.turnStarted || continued {
+			last := &m.history[len(m.history)-1]
+			last.Content = m.finishContent(last.Content)
+		}
+		m.applyContentFilter()
+		m.applySoftLength()
+
+		// Ask again for a turn that talks past the opponent
+		if !continued && m.turnStarted && m.askRebuttal(m.history[len(m.history)-1]) {
+			return m, m.repromptRebuttal()
+		}
+
+		m.reportTurn(continued)
+		score := m.scoreTurn()
+
+		// Finish the debate if the models have come to agree
+		if m.endOnConsensus && len(m.history) > 1 && detectConsensus(m.history[len(m.history)-1]) {
+			m.cancelGeneration()
+			m.state = stateStopped
+			m.endReason = "🤝 Consensus reached"
+			return m, score
+		}
+
+		// Finish the debate once the turn limit is reached
+		if m.maxTurns > 0 && m.argumentCount() >= m.maxTurns {
+			m.cancelGeneration()
+			m.state = stateStopped
+			m.endReason = fmt.Sprintf("🏁 Finished after %d turns", m.maxTurns)
+			return m, score
+		}
+
+		// Drop the oldest turns beyond the history cap
+		m.pruneHistory()
 
 		// Switch to the opposite model
 		m.switchTurn()
 
-		// Trigger next turn
+		// Let the moderator interject when due, then trigger the next turn
 		m.isGenerating = true
-		return m, m.generateResponse(ctx)
+		if m.moderationDue() {
+			return m, withScore(score, m.generateModeration())
+		}
+		return m, withScore(score, m.generateResponse())
+
+	// Put a validated model in place of the one it swaps out
+	case modelSwapMsg:
+		return m, m.applySwap(msg)
+
+	// Move the score meter
+	case scoreMsg:
+		m.applyScore(msg)
+		return m, nil
+
+	// Keep debating when the transcript cannot be written, but say so
+	case outputWarningMsg:
+		m.outputWarning = formatOutputWarning(msg.err)
+		return m, nil
+
+	// Keep the bytes of the last chunk as they arrived when debugging
+	case rawChunkMsg:
+		if m.debug {
+			m.lastChunk = string(msg.raw)
+		}
+		return m, nil
 
-	// Handle errors
-	// case responseErrorMsg:
-	// 	m.isGenerating = false
+	// Log a consumer too slow to keep up with the stream when debugging
+	case backpressureMsg:
+		if m.debug {
+			m.logBackpressure(msg)
+		}
+		return m, nil
 
-	// 	// Display error message in UI
-	// 	m.errorMsg = fmt.Sprintf("Error: %v", msg.err)
+	// Handle errors by pausing so the failed turn can be retried
+	case responseErrorMsg:
+		if m.isStale(msg.responseChan) || m.state != stateDebating || errors.Is(msg.err, context.Canceled) {
+			break
+		}
 
-	// 	// Preserve existing history (already done by not modifying it)
+		// A failed interjection is skipped rather than holding up the debate
+		if m.moderating {
+			return m, m.skipModeration(msg.err)
+		}
+
+		// So is a failed thesis, leaving the model to argue without one
+		if m.stating {
+			return m, m.skipThesis(msg.err)
+		}
+
+		// Trim the history sent to the model and retry once if the prompt
+		// outgrew its context window
+		if errors.Is(msg.err, ErrContextExceeded) && !m.contextRetried && len(m.history) > 1 {
+			m.contextRetried = true
+			m.promptBudget = len(FormatHistory(m.promptHistory())) / 2
+			return m, m.retryTurn()
+		}
+
+		// Display error message in UI, preserving existing history
+		m.errorMsg = fmt.Sprintf("Error: %v", msg.err)
+		if m.recordErrors {
+			m.recordFailedTurn(msg.err)
+		}
+		return m, m.handleTurnError()
 
-	// 	// Attempt to continue with next turn if recoverable
-	// 	m.switchTurn()
-	// 	m.isGenerating = true
-	// 	return m, m.generateResponse()
+	// Tell the user why the turn is taking longer
+	case rateLimitedMsg:
+		if m.isGenerating {
+			m.errorMsg = fmt.Sprintf("Rate limited, retrying in %s", formatRetryDelay(msg.wait))
+		}
+		return m, nil
 
 	// Handle stop command
 	case stopDebateMsg:
+		m.cancelGeneration()
 		m.isGenerating = false
 		m.state = stateStopped
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	// Update viewport if in debating state
@@ -220,6 +705,20 @@ func (m *debateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// toggleAutoscroll switches autoscroll, jumping to the end of the debate
+// when it is turned on
+func (m *debateModel) toggleAutoscroll() {
+	m.autoscroll = !m.autoscroll
+	if m.autoscroll {
+		m.viewport.GotoBottom()
+	}
+}
+
+// scrollKey reports whether msg is one of keys' scrolling keys
+func scrollKey(keys viewport.KeyMap, msg tea.KeyMsg) bool {
+	return key.Matches(msg, keys.Up, keys.Down, keys.PageUp, keys.PageDown, keys.HalfPageUp, keys.HalfPageDown)
+}
+
 // View renders the UI
 func (m *debateModel) View() string {
 	switch m.state {
@@ -236,6 +735,29 @@ func (m *debateModel) View() string {
 	}
 }
 
+// startDebate begins debating topic with model1's turn
+func (m *debateModel) startDebate(topic string) tea.Cmd {
+	// Transition to debating state
+	m.topic = topic
+	m.state = stateDebating
+	m.fitViewport()
+	m.errorMsg = ""
+	m.isGenerating = true
+	m.openDebate() // Start with model1, unless rounds are shuffled
+	m.startMeta()
+	m.seedOpening()
+
+	// Start first model generation
+	return m.generateResponse()
+}
+
+// fitViewport sizes the debate viewport to the last known window size,
+// leaving room for the footer
+func (m *debateModel) fitViewport() {
+	m.viewport.Width = m.width
+	m.viewport.Height = viewportHeight(m.height, m.width, m.renderFooter())
+}
+
 // getNextModel returns the name of the model that should speak next.
 // It alternates between model1 and model2 based on the current turn counter.
 // currentTurn 0 means model1, currentTurn 1 means model2.
@@ -246,8 +768,142 @@ func (m *debateModel) getNextModel() string {
 	return m.model2Name
 }
 
-// switchTurn toggles the current turn between model1 (0) and model2 (1).
+// promptHistory returns the part of the history sent to models, trimmed to
+// the prompt budget after a context length error. Recorded failures are left
+// out since they are not arguments.
+func (m *debateModel) promptHistory() []Turn {
+	history := m.history
+	if m.recordErrors {
+		history = completedTurns(history)
+	}
+	return TrimHistoryToFit(history, m.promptBudget)
+}
+
+// isArgument reports whether the turn is a debater's argument rather than a
+// moderator interjection, a thesis or a recorded failure
+func (t Turn) isArgument() bool {
+	return !t.Moderator && !t.Thesis && t.Error == ""
+}
+
+// argumentCount returns how many arguments the debate has had, including
+// those pruned from the history. Phases and -max-turns go by arguments, so
+// interjections, theses and failures do not move them along.
+func (m *debateModel) argumentCount() int {
+	count := m.prunedArgs
+	for _, turn := range m.history {
+		if turn.isArgument() {
+			count++
+		}
+	}
+	return count
+}
+
+// completedTurns returns history without its recorded failures
+func completedTurns(history []Turn) []Turn {
+	completed := make([]Turn, 0, len(history))
+	for _, turn := range history {
+		if turn.Error == "" {
+			completed = append(completed, turn)
+		}
+	}
+	return completed
+}
+
+// Each consumer of the history takes its own slice of m.history:
+//
+//   - promptHistory: what models reason over, the full history unless a
+//     context length error forced trimming
+//   - visibleHistory and viewStart: what the view renders, at most the last
+//     viewTurns turns
+//   - exportHistory: what exports and the clipboard receive, always every
+//     turn still in memory
+
+// exportHistory returns the turns written to exports and the clipboard
+func (m *debateModel) exportHistory() []Turn {
+	return m.history
+}
+
+// viewStart returns the index of the first turn the view shows, skipping
+// older turns beyond the view limit
+func (m *debateModel) viewStart() int {
+	if m.viewTurns <= 0 || len(m.history) <= m.viewTurns {
+		return 0
+	}
+	return len(m.history) - m.viewTurns
+}
+
+// visibleHistory returns the history the debate view renders from, holding
+// back text the typewriter has not revealed yet. Turns before viewStart are
+// included so indices match m.history, but are not shown.
+func (m *debateModel) visibleHistory() []Turn {
+	history := m.history
+
+	// Redact the turn still streaming in. Redactions keep the content's
+	// length, so the typewriter's count still lines up.
+	if m.contentFilter != nil && m.isGenerating && (m.turnStarted || m.continuing) && len(history) > 0 {
+		history = append([]Turn(nil), history...)
+		last := &history[len(history)-1]
+		last.Content, _ = m.contentFilter(last.Content)
+	}
+
+	if !m.typewriter.enabled() {
+		return history
+	}
+	return revealHistory(history, m.typewriter.revealed)
+}
+
+// displayName returns the alias configured for a model, or the model tag
+// itself when no alias is set.
+func (m *debateModel) displayName(modelName string) string {
+	switch modelName {
+	case m.model1Name:
+		return m.sideName(0)
+	case m.model2Name:
+		return m.sideName(1)
+	}
+	return safeName(modelName)
+}
+
+// sideName returns the alias configured for side, 0 for model1's and 1 for
+// model2's, or the tag of the model debating on it when no alias is set
+func (m *debateModel) sideName(side int) string {
+	modelName, alias := m.model1Name, m.model1Alias
+	if side == 1 {
+		modelName, alias = m.model2Name, m.model2Alias
+	}
+	if alias != "" {
+		return safeName(alias)
+	}
+	return safeName(modelName)
+}
+
+// hasSpoken reports whether a model already has a completed turn in the
+// history
+func (m *debateModel) hasSpoken(modelName string) bool {
+	for _, turn := range m.history {
+		if turn.ModelName == modelName && turn.Error == "" && !turn.Moderator && !turn.Thesis {
+			return true
+		}
+	}
+	return false
+}
+
+// switchTurn advances to the next turn, toggling between model1 (0) and
+// model2 (1) once the current model has taken its share of the turn ratio.
+// With -shuffle-rounds, each round's opener is drawn instead.
 func (m *debateModel) switchTurn() {
+	m.rebutSpeaker = ""
+	m.turnStreak++
+	if m.turnStreak < m.turnRatio.turns(m.currentTurn) {
+		return
+	}
+
+	if m.shuffle.enabled() {
+		m.switchStint()
+		return
+	}
+
+	m.turnStreak = 0
 	if m.currentTurn == 0 {
 		m.currentTurn = 1
 	} else {
@@ -255,17 +911,409 @@ func (m *debateModel) switchTurn() {
 	}
 }
 
+// seedOpening adds the configured first message to the empty history as
+// model1's opening turn and passes the turn on. It reports whether a turn
+// was added.
+func (m *debateModel) seedOpening() bool {
+	if m.firstMessage == "" || len(m.history) > 0 {
+		return false
+	}
+
+	m.history = append(m.history, Turn{
+		ModelName:   m.model1Name,
+		DisplayName: m.sideName(0),
+		Content:     m.firstMessage,
+		Timestamp:   time.Now(),
+		Stance:      m.stance(m.model1Name),
+	})
+	if m.sink != nil {
+		m.sink.Send(m.turnEvent(m.history[0]))
+	}
+	m.switchTurn()
+	return true
+}
+
+// requestContext returns the context requests are started under. It ends
+// when the program quits, and a new one begins should the model be used
+// again.
+func (m *debateModel) requestContext() context.Context {
+	if m.lifetime == nil || m.lifetime.Err() != nil {
+		m.lifetime, m.endLifetime = context.WithCancel(context.Background())
+	}
+	return m.lifetime
+}
+
+// endRequests cancels every request started under requestContext:
+// generations, rounds and scoring alike
+func (m *debateModel) endRequests() {
+	if m.endLifetime != nil {
+		m.endLifetime()
+	}
+}
+
+// quit ends every request the model started and quits the program. The
+// model quits only through here, so a request never outlives the program.
+func (m *debateModel) quit() tea.Cmd {
+	m.cancelGeneration()
+	m.endRequests()
+	return tea.Quit
+}
+
+// cancelGeneration cancels the in-flight generation request, if any.
+func (m *debateModel) cancelGeneration() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.round = nil
+}
+
+// shutdown stops any in-flight generation and waits for it to finish. The
+// sequence is:
+//
+//  1. Cancel the generation's context, which aborts the HTTP request and
+//     unblocks the client goroutine if it is waiting to send a chunk.
+//  2. Drain the response channel until the goroutine closes it, so a chunk
+//     it sent before noticing the cancellation cannot leave it blocked.
+//  3. Drain the error channel, which is closed once the goroutine returns.
+//
+// When shutdown returns no generation goroutine is left running. Scoring by
+// the judge is cancelled without waiting. Callers running the model in a
+// tea.Program call shutdown once Run returns, as the program may have ended
+// without the model quitting, such as when it was killed. It is safe to
+// call more than once.
+func (m *debateModel) shutdown() {
+	round := m.round
+	m.cancelGeneration()
+	m.stopScoring()
+	m.endRequests()
+	m.isGenerating = false
+
+	drainGeneration(m.stream, m.streamErrs)
+	if round != nil {
+		for i := range round.streams {
+			drainGeneration(round.streams[i], round.errs[i])
+		}
+	}
+	m.stream = nil
+	m.streamErrs = nil
+}
+
+// drainGeneration reads a cancelled generation's channels until the client
+// goroutine closes them. Nil channels are skipped.
+func drainGeneration(stream <-chan string, errs <-chan error) {
+	if stream != nil {
+		for range stream {
+		}
+	}
+	if errs != nil {
+		for range errs {
+		}
+	}
+}
+
+// restart clears the finished debate and returns to the topic input,
+// keeping the configured models and options
+func (m *debateModel) restart() {
+	m.shutdown()
+	m.resetDebate()
+
+	m.textInput.Reset()
+	m.textInput.Focus()
+	m.viewport.GotoTop()
+	m.state = stateInput
+}
+
+// resetDebate clears everything kept about the current debate, leaving the
+// configuration, so the next debate starts afresh. Any generation must have
+// been stopped first.
+func (m *debateModel) resetDebate() {
+	m.topic = ""
+	m.history = []Turn{}
+	m.prunedTurns = 0
+	m.prunedArgs = 0
+	m.prunedUnmod = 0
+	m.meta = DebateMeta{}
+	m.currentTurn = 0
+	m.turnStreak = 0
+	m.swap = nil
+	m.swappedOut = nil
+	m.round = nil
+	m.turnStarted = false
+	m.continuing = false
+	m.moderating = false
+	m.stating = false
+	m.thesisTried = nil
+	m.continueFrom = 0
+	m.promptBudget = 0
+	m.contextRetried = false
+	m.autoRetries = 0
+	m.rebutSpeaker = ""
+	m.lastPrompt = ""
+	m.lastChunk = ""
+	m.debugLog = nil
+	m.outputWarning = ""
+	m.errorMsg = ""
+	m.endReason = ""
+	m.turnCache = nil
+	m.page = 0
+	m.pageBrowsing = false
+	m.typewriter.revealed = 0
+}
+
+// retryTurn discards any partial output from the failed turn and asks the
+// same model to generate it again. The turn is not switched, so a failure
+// never causes a model to lose its turn.
+func (m *debateModel) retryTurn() tea.Cmd {
+	m.state = stateDebating
+	m.errorMsg = ""
+	m.isGenerating = true
+
+	// A failed continuation keeps the turn it was extending
+	if m.continuing {
+		last := &m.history[len(m.history)-1]
+		last.Content = last.Content[:m.continueFrom]
+		return m.generateContinuation()
+	}
+
+	if m.turnStarted {
+		m.history = m.history[:len(m.history)-1]
+	}
+	return m.generateResponse()
+}
+
+// recordFailedTurn marks the failure of the current generation in the
+// history. Partial output becomes the failed turn; otherwise an empty
+// placeholder is added. A failed continuation is cut back to where it began
+// and followed by the placeholder, so a retry generates a fresh turn.
+func (m *debateModel) recordFailedTurn(err error) {
+	modelName := m.getNextModel()
+
+	switch {
+	case m.continuing:
+		last := &m.history[len(m.history)-1]
+		last.Content = last.Content[:m.continueFrom]
+		m.continuing = false
+	case m.turnStarted:
+		last := &m.history[len(m.history)-1]
+		last.Error = err.Error()
+		m.turnStarted = false
+		if m.sink != nil {
+			m.sink.Send(m.turnEvent(*last))
+		}
+		return
+	}
+
+	m.history = append(m.history, Turn{
+		ModelName:   modelName,
+		DisplayName: m.displayName(modelName),
+		Timestamp:   time.Now(),
+		Error:       err.Error(),
+		Prompt:      m.storedPrompt(m.lastPrompt),
+		Stance:      m.stance(modelName),
+	})
+	if m.sink != nil {
+		m.sink.Send(m.turnEvent(m.history[len(m.history)-1]))
+	}
+}
+
+// handleTurnError applies the configured error policy after a turn fails
+func (m *debateModel) handleTurnError() tea.Cmd {
+	m.cancelGeneration()
+
+	switch m.onError {
+	case errorPolicyContinue:
+		// Give the opponent its turn, keeping any partial output
+		m.continuing = false
+		m.switchTurn()
+		m.isGenerating = true
+		return m.generateResponse()
+
+	case errorPolicyStop:
+		m.isGenerating = false
+		m.state = stateStopped
+		m.endReason = "⚠️ Debate stopped after an error"
+		return nil
+
+	case errorPolicyRetry:
+		if m.autoRetries < maxAutoRetries {
+			m.autoRetries++
+			errorMsg := m.errorMsg
+			cmd := m.retryTurn()
+			m.errorMsg = fmt.Sprintf("%s (retrying %d/%d)", errorMsg, m.autoRetries, maxAutoRetries)
+			return cmd
+		}
+	}
+
+	// Pause so the user can retry the turn
+	m.isGenerating = false
+	m.state = stateError
+	return nil
+}
+
+// continueTurn asks the model that spoke last to elaborate on its turn,
+// appending to that turn instead of advancing to the opponent. Any turn the
+// opponent had already started is discarded.
+func (m *debateModel) continueTurn() tea.Cmd {
+	// A recorded failure has nothing to elaborate on
+	target := len(m.history) - 1
+	if m.isGenerating && m.turnStarted && !m.continuing {
+		target--
+	}
+	if target >= 0 && (m.history[target].Error != "" || m.history[target].Moderator || m.history[target].Thesis) {
+		return nil
+	}
+
+	m.cancelGeneration()
+	m.moderating = false
+	m.stating = false
+
+	if m.continuing {
+		// Restart a continuation that is already under way
+		last := &m.history[len(m.history)-1]
+		last.Content = last.Content[:m.continueFrom]
+	} else if m.isGenerating && m.turnStarted {
+		// Drop the unfinished turn in progress
+		m.history = m.history[:len(m.history)-1]
+	}
+
+	if len(m.history) == 0 {
+		m.isGenerating = true
+		return m.generateResponse()
+	}
+
+	// Step the rotation back to the last turn, which the continuation extends
+	last := m.history[len(m.history)-1]
+	lastTurn := 0
+	if !m.onModel1Side(last.ModelName) {
+		lastTurn = 1
+	}
+	if !m.continuing {
+		if m.currentTurn == lastTurn && m.turnStreak > 0 {
+			m.turnStreak--
+		} else {
+			m.turnStreak = m.turnRatio.turns(lastTurn) - 1
+			if m.shuffle.enabled() {
+				m.unswitchStint()
+			}
+		}
+	}
+	m.currentTurn = lastTurn
+
+	m.continuing = true
+	m.continueFrom = len(last.Content)
+	m.isGenerating = true
+	return m.generateContinuation()
+}
+
+// isStale reports whether a message comes from a stream that has since been
+// replaced, for example by a retry or continuation
+func (m *debateModel) isStale(stream <-chan string) bool {
+	return stream != nil && stream != m.stream
+}
+
+// nextPrompt returns the model whose turn it is and the prompt to send it.
+// In chat mode the prompt is the chat messages rendered as text.
+func (m *debateModel) nextPrompt() (modelName, prompt string) {
+	modelName = m.getNextModel()
+	turnIndex := m.argumentCount()
+	if m.chat {
+		return modelName, formatChatMessages(m.chatMessages(modelName, turnIndex))
+	}
+	return modelName, m.promptFor(modelName, turnIndex)
+}
+
+// promptFor builds the prompt asking modelName for the turn at turnIndex,
+// counted from the start of the debate
+func (m *debateModel) promptFor(modelName string, turnIndex int) string {
+	isFirstTurn := !m.hasSpoken(modelName)
+	opts := m.turnPromptOptions(modelName, turnIndex)
+
+	// Build the prompt with full context, addressing the model by its display name
+	return BuildDebatePromptWithOptions(m.topic, m.promptHistory(), m.displayName(modelName), isFirstTurn, opts)
+}
+
+// turnPromptOptions returns the prompt options for modelName's turn at
+// turnIndex
+func (m *debateModel) turnPromptOptions(modelName string, turnIndex int) PromptOptions {
+	opts := m.promptOptions
+	opts.TurnIndex = turnIndex
+	opts.Rebut = m.rebutSpeaker
+	if m.lengthReminderDue(modelName) {
+		opts.LengthLimit = m.softLength
+	}
+
+	// Assign an explicit position when one model was designated pro, when
+	// the side is restated every turn, or once a model has been swapped in
+	// and would otherwise take its side from speaking order
+	if m.proModel != "" || m.reinforceStance || len(m.swappedOut) > 0 {
+		opts.Position = m.stance(modelName)
+		opts.ReinforceStance = m.reinforceStance
+	}
+	return opts
+}
+
+// storedPrompt returns the prompt to keep on a new turn, which is empty
+// unless prompts are echoed in exports
+func (m *debateModel) storedPrompt(prompt string) string {
+	if !m.echoPrompt {
+		return ""
+	}
+	return prompt
+}
+
+// currentPrompt returns the prompt sent for the turn being generated, or
+// the one that will be sent for the next turn when nothing is in flight
+func (m *debateModel) currentPrompt() string {
+	if m.isGenerating && m.lastPrompt != "" {
+		return m.lastPrompt
+	}
+	_, prompt := m.nextPrompt()
+	return prompt
+}
+
 // generateResponse starts generating a response from the current model.
 // It returns a Cmd that will send responseChunkMsg and responseCompleteMsg.
-func (m *debateModel) generateResponse(ctx context.Context) tea.Cmd {
+func (m *debateModel) generateResponse() tea.Cmd {
+	if m.simultaneous {
+		return m.startRound()
+	}
+	if m.thesisDue(m.getNextModel()) {
+		return m.generateThesis()
+	}
+	modelName, prompt := m.nextPrompt()
+	return m.startGenerationOn(m.turnBackend(modelName), modelName, prompt)
+}
+
+// generateContinuation asks the current model to elaborate on its last turn
+func (m *debateModel) generateContinuation() tea.Cmd {
 	modelName := m.getNextModel()
-	isFirstTurn := len(m.history) == 0
+	prompt := BuildContinuePromptWithOptions(m.topic, m.promptHistory(), m.displayName(modelName), m.promptOptions)
+	return m.startGeneration(modelName, prompt)
+}
+
+// startGeneration sends prompt to modelName, replacing any in-flight
+// generation, and returns a Cmd that waits for the first chunk
+func (m *debateModel) startGeneration(modelName, prompt string) tea.Cmd {
+	return m.startGenerationOn(m.backend(), modelName, prompt)
+}
 
-	// Build the prompt with full context
-	prompt := BuildDebatePrompt(m.topic, m.history, modelName, isFirstTurn)
+// startGenerationOn starts a generation like startGeneration, using backend
+func (m *debateModel) startGenerationOn(backend Generator, modelName, prompt string) tea.Cmd {
+	m.cancelGeneration()
+	ctx, cancel := context.WithCancel(m.requestContext())
+	m.cancel = cancel
 
-	// Generate response using Ollama client
-	responseChan, errorChan := m.ollamaClient.GenerateResponse(ctx, modelName, prompt)
+	// Generate response using Ollama client, keeping theses short
+	var overrides map[string]interface{}
+	if m.stating {
+		overrides = thesisOptions
+	}
+	responseChan, errorChan := backend.GenerateResponseWithOptions(ctx, modelName, prompt, overrides)
+	m.stream = responseChan
+	m.streamErrs = errorChan
+	m.lastPrompt = prompt
+	m.turnStarted = false
 
 	// Return a command that waits for the first chunk
 	return waitForNextChunk(responseChan, errorChan)
@@ -278,7 +1326,7 @@ func waitForNextChunk(responseChan <-chan string, errorChan <-chan error) tea.Cm
 		case chunk, ok := <-responseChan:
 			if !ok {
 				// Channel closed, response complete
-				return responseCompleteMsg{}
+				return responseCompleteMsg{responseChan: responseChan}
 			}
 			// Send chunk to UI with channels for continuation
 			return responseChunkMsg{
@@ -290,10 +1338,10 @@ func waitForNextChunk(responseChan <-chan string, errorChan <-chan error) tea.Cm
 		case err, ok := <-errorChan:
 			if !ok {
 				// Channel closed, response complete
-				return responseCompleteMsg{}
+				return responseCompleteMsg{responseChan: responseChan}
 			}
 			if ok && err != nil {
-				return responseErrorMsg{err: err}
+				return responseErrorMsg{err: err, responseChan: responseChan}
 			}
 			// Error channel closed without error, wait for response channel
 			return waitForNextChunk(responseChan, errorChan)()
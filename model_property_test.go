@@ -3,6 +3,8 @@ package main
 import (
 	"strings"
 	"testing"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -48,7 +50,7 @@ func TestProperty_ValidTopicsInitializeBothModels(t *testing.T) {
 			// Simulate the user pressing Enter to submit the topic
 			msg := tea.KeyMsg{Type: tea.KeyEnter}
 			updatedModel, _ := model.Update(msg)
-			m := updatedModel.(debateModel)
+			m := updatedModel.(*debateModel)
 
 			// Property 1: The topic should be set in the model
 			if m.topic != topic {
@@ -94,6 +96,14 @@ func genValidTopic() gopter.Gen {
 		// Valid topics are non-empty after trimming whitespace
 		return len(strings.TrimSpace(s)) > 0
 	}).Map(func(s string) string {
+		// Drop control and invalid runes, which the text input sanitizes away
+		s = strings.Map(func(r rune) rune {
+			if r == utf8.RuneError || !unicode.IsPrint(r) {
+				return -1
+			}
+			return r
+		}, s)
+
 		// Ensure we have a reasonable topic
 		trimmed := strings.TrimSpace(s)
 		if len(trimmed) == 0 {
@@ -0,0 +1,876 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestServer returns a server that records each generate request and
+// replies with a single complete chunk
+func newTestServer(t *testing.T, requests *[]GenerateRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		*requests = append(*requests, req)
+		json.NewEncoder(w).Encode(GenerateResponse{Model: req.Model, Response: "ok", Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestAliases_APIUsesTagViewUsesAlias verifies that aliases only change what
+// is displayed, never the model tag sent to Ollama
+func TestAliases_APIUsesTagViewUsesAlias(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "gemma3n:e4b",
+		model2Name:   "phi3:mini",
+		model1Alias:  "Optimist",
+		model2Alias:  "Skeptic",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Is remote work here to stay?",
+		history: []Turn{
+			{ModelName: "phi3:mini", DisplayName: "Skeptic", Content: "Offices will return.", Timestamp: time.Now()},
+		},
+	}
+
+	// Drain the generation so the request reaches the server
+	msg := m.generateResponse()()
+	if _, ok := msg.(responseChunkMsg); !ok {
+		t.Fatalf("Expected responseChunkMsg, got %T", msg)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if requests[0].Model != "gemma3n:e4b" {
+		t.Errorf("Expected API call to use model tag, got %s", requests[0].Model)
+	}
+	if !strings.Contains(requests[0].Prompt, "You are Optimist") {
+		t.Errorf("Expected prompt to address the model by its alias")
+	}
+	if !strings.Contains(requests[0].Prompt, "[Skeptic]:") {
+		t.Errorf("Expected prompt history to attribute turns to aliases")
+	}
+
+	rendered := formatTurn(m.history[0], false, 80, defaultMinContentWidth, "", timestampFormat{})
+	if !strings.Contains(rendered, "Skeptic") || strings.Contains(rendered, "phi3:mini") {
+		t.Errorf("Expected rendered turn to show the alias instead of the tag, got: %s", rendered)
+	}
+}
+
+func TestTurnSpeaker_FallsBackToModelName(t *testing.T) {
+	turn := Turn{ModelName: "mistral:7b"}
+	if turn.Speaker() != "mistral:7b" {
+		t.Errorf("Expected speaker to fall back to model name, got %s", turn.Speaker())
+	}
+}
+
+// TestRetry_RerunsSameModel verifies that retrying a failed turn keeps the
+// same model and discards its partial output
+func TestRetry_RerunsSameModel(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		state:        stateDebating,
+		isGenerating: true,
+		turnStarted:  true,
+		currentTurn:  1,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Homework builds discipline."},
+			{ModelName: "gemma3:4b", Content: "Partial respo"},
+		},
+	}
+
+	// A generation error pauses the debate instead of advancing
+	m.Update(responseErrorMsg{err: errors.New("connection reset")})
+	if m.state != stateError {
+		t.Fatalf("Expected error state, got %v", m.state)
+	}
+	if m.currentTurn != 1 {
+		t.Errorf("Expected turn to stay with the failed model, got %d", m.currentTurn)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("Expected retry to start a generation")
+	}
+	cmd()
+
+	if m.state != stateDebating || m.errorMsg != "" {
+		t.Errorf("Expected retry to resume debating and clear the error")
+	}
+	if len(m.history) != 1 {
+		t.Errorf("Expected partial turn to be discarded, got %d turns", len(m.history))
+	}
+	if len(requests) != 1 || requests[0].Model != "gemma3:4b" {
+		t.Errorf("Expected retry to re-run gemma3:4b, got %+v", requests)
+	}
+}
+
+// TestProModel_GetsAffirmativeWhenSpeakingSecond verifies positions follow
+// the designated pro model rather than speaking order
+func TestProModel_GetsAffirmativeWhenSpeakingSecond(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		proModel:     "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should cities ban cars?",
+	}
+
+	// mistral:7b opens but argues against
+	m.generateResponse()()
+	m.history = append(m.history, Turn{ModelName: "mistral:7b", Content: "Cars are essential."})
+
+	// gemma3:4b speaks second but argues in favor
+	m.switchTurn()
+	m.generateResponse()()
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	if !strings.Contains(requests[0].Prompt, "arguing against the topic") {
+		t.Errorf("Expected opening model to get the opposing framing")
+	}
+	if !strings.Contains(requests[1].Prompt, "arguing in favor of the topic") {
+		t.Errorf("Expected designated pro model to get the affirmative framing when speaking second")
+	}
+}
+
+// TestContextExceeded_TrimsAndRetriesOnce verifies that a context length
+// error retries the same turn with a shorter history, but only once
+func TestContextExceeded_TrimsAndRetriesOnce(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Is nuclear power safe?",
+		state:        stateDebating,
+		isGenerating: true,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Oldest argument. " + strings.Repeat("x", 200)},
+			{ModelName: "gemma3:4b", Content: "Latest rebuttal."},
+		},
+	}
+
+	_, cmd := m.Update(responseErrorMsg{err: ErrContextExceeded})
+	if cmd == nil || m.state != stateDebating {
+		t.Fatalf("Expected a trimmed retry, got state %v", m.state)
+	}
+	cmd()
+
+	if len(requests) != 1 || requests[0].Model != "mistral:7b" {
+		t.Fatalf("Expected retry to re-run mistral:7b, got %+v", requests)
+	}
+	if strings.Contains(requests[0].Prompt, "Oldest argument.") {
+		t.Errorf("Expected the oldest turn to be trimmed from the prompt")
+	}
+	if !strings.Contains(requests[0].Prompt, "Latest rebuttal.") {
+		t.Errorf("Expected the latest turn to be kept in the prompt")
+	}
+	if len(m.history) != 2 {
+		t.Errorf("Expected the displayed history to be untouched")
+	}
+
+	// A second failure on the same turn surfaces the error
+	m.Update(responseErrorMsg{err: ErrContextExceeded})
+	if m.state != stateError {
+		t.Errorf("Expected the second context error to pause the debate")
+	}
+}
+
+// TestContinue_AppendsToSameTurn verifies that continuing extends the last
+// speaker's turn without switching to the opponent
+func TestContinue_AppendsToSameTurn(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Is chess a sport?",
+		state:        stateDebating,
+		isGenerating: true,
+		turnStarted:  true,
+		currentTurn:  1,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Yes."},
+			{ModelName: "gemma3:4b", Content: "Partial opp"},
+		},
+	}
+	staleStream := make(chan string)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if cmd == nil {
+		t.Fatal("Expected continue to start a generation")
+	}
+	cmd()
+
+	if len(m.history) != 1 {
+		t.Fatalf("Expected the opponent's unfinished turn to be dropped, got %d turns", len(m.history))
+	}
+	if m.currentTurn != 0 {
+		t.Errorf("Expected the turn to return to mistral:7b, got %d", m.currentTurn)
+	}
+
+	// Chunks from the continuation extend the same turn
+	m.Update(responseChunkMsg{chunk: "Chess demands training.", responseChan: m.stream})
+	m.Update(responseChunkMsg{chunk: "stale", responseChan: staleStream})
+	if len(m.history) != 1 || m.history[0].Content != "Yes.\n\nChess demands training." {
+		t.Errorf("Expected continuation appended to the same turn, got %+v", m.history)
+	}
+	if m.currentTurn != 0 {
+		t.Errorf("Expected no turn switch during continuation")
+	}
+
+	// Completing the continuation hands the turn to the opponent
+	_, cmd = m.Update(responseCompleteMsg{responseChan: m.stream})
+	if m.currentTurn != 1 || m.continuing {
+		t.Errorf("Expected the opponent to speak after the continuation")
+	}
+	cmd()
+
+	if len(requests) == 0 || !strings.Contains(requests[0].Prompt, "elaborate on your most recent argument") {
+		t.Errorf("Expected a continuation prompt to be sent first")
+	}
+	if requests[0].Model != "mistral:7b" {
+		t.Errorf("Expected continuation to use mistral:7b, got %s", requests[0].Model)
+	}
+}
+
+// TestErrorPolicies verifies each -on-error policy's branch in Update
+func TestErrorPolicies(t *testing.T) {
+	tests := []struct {
+		policy      string
+		autoRetries int
+		wantState   appState
+		wantTurn    int
+		wantRequest string // Model asked to generate next, if any
+	}{
+		{errorPolicyPause, 0, stateError, 1, ""},
+		{"", 0, stateError, 1, ""},
+		{errorPolicyContinue, 0, stateDebating, 0, "mistral:7b"},
+		{errorPolicyStop, 0, stateStopped, 1, ""},
+		{errorPolicyRetry, 0, stateDebating, 1, "gemma3:4b"},
+		{errorPolicyRetry, maxAutoRetries, stateError, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			var requests []GenerateRequest
+			server := newTestServer(t, &requests)
+
+			m := &debateModel{
+				model1Name:   "mistral:7b",
+				model2Name:   "gemma3:4b",
+				ollamaClient: NewOllamaClient(server.URL),
+				topic:        "Is coffee healthy?",
+				state:        stateDebating,
+				isGenerating: true,
+				currentTurn:  1,
+				onError:      tt.policy,
+				autoRetries:  tt.autoRetries,
+				history:      []Turn{{ModelName: "mistral:7b", Content: "Yes."}},
+			}
+
+			_, cmd := m.Update(responseErrorMsg{err: errors.New("boom")})
+			if cmd != nil {
+				cmd()
+			}
+
+			if m.state != tt.wantState {
+				t.Errorf("Expected state %v, got %v", tt.wantState, m.state)
+			}
+			if m.currentTurn != tt.wantTurn {
+				t.Errorf("Expected turn %d, got %d", tt.wantTurn, m.currentTurn)
+			}
+			if tt.wantRequest == "" && len(requests) != 0 {
+				t.Errorf("Expected no new generation, got %+v", requests)
+			}
+			if tt.wantRequest != "" && (len(requests) != 1 || requests[0].Model != tt.wantRequest) {
+				t.Errorf("Expected %s to generate next, got %+v", tt.wantRequest, requests)
+			}
+			if !strings.Contains(m.errorMsg, "boom") {
+				t.Errorf("Expected the error to be shown, got %q", m.errorMsg)
+			}
+		})
+	}
+}
+
+// TestStopped_AnyKeyQuits verifies that the stopped view's "any other key
+// to exit" instruction holds for keys other than q
+func TestStopped_AnyKeyQuits(t *testing.T) {
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("x")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyEsc},
+	}
+
+	for _, key := range keys {
+		m := &debateModel{state: stateStopped}
+		_, cmd := m.Update(key)
+		if cmd == nil {
+			t.Errorf("Expected %q to quit from the stopped state", key.String())
+			continue
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("Expected %q to quit from the stopped state", key.String())
+		}
+	}
+}
+
+// TestStopped_ScrollsTranscript verifies the finished transcript can still
+// be scrolled and autoscroll toggled without quitting
+func TestStopped_ScrollsTranscript(t *testing.T) {
+	m := &debateModel{model1Name: "phi3:mini", model2Name: "gemma3:4b", width: 80, height: 10}
+	m.Init()
+	m.state = stateStopped
+	for i := 0; i < 10; i++ {
+		m.history = append(m.history, Turn{ModelName: "phi3:mini", Content: "Remote work wins."})
+	}
+	m.View()
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.state != stateStopped || m.viewport.YOffset != 1 {
+		t.Fatalf("Expected down to scroll one line, got state %v offset %d", m.state, m.viewport.YOffset)
+	}
+	if cmd != nil {
+		if _, ok := cmd().(tea.QuitMsg); ok {
+			t.Fatal("Expected scrolling not to quit")
+		}
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if cmd != nil || !m.autoscroll || !m.viewport.AtBottom() {
+		t.Errorf("Expected 'a' to turn autoscroll on and jump to the end, got autoscroll %v at bottom %v", m.autoscroll, m.viewport.AtBottom())
+	}
+}
+
+// TestShutdown_DrainsInFlightGeneration verifies that quitting mid-stream
+// leaves no generation goroutine or request behind
+func TestShutdown_DrainsInFlightGeneration(t *testing.T) {
+	var handlers sync.WaitGroup
+	handlers.Add(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer handlers.Done()
+		// Stream chunks until the client goes away
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			default:
+			}
+			json.NewEncoder(w).Encode(GenerateResponse{Response: "more ", Done: false})
+			w.(http.Flusher).Flush()
+			time.Sleep(time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		state:        stateDebating,
+		isGenerating: true,
+	}
+
+	// Read one chunk, then leave the generation blocked on its next send
+	if msg := m.generateResponse()(); msg == nil {
+		t.Fatal("Expected a chunk from the stream")
+	}
+	stream := m.stream
+
+	done := make(chan struct{})
+	go func() {
+		m.shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown did not return")
+	}
+
+	// The generation goroutine closes its channel when it exits
+	if _, ok := <-stream; ok {
+		t.Errorf("Expected the response channel to be closed after shutdown")
+	}
+
+	handlerDone := make(chan struct{})
+	go func() {
+		handlers.Wait()
+		close(handlerDone)
+	}()
+	select {
+	case <-handlerDone:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the request to be aborted after shutdown")
+	}
+
+	// Shutting down again is a no-op
+	m.shutdown()
+}
+
+// blockingGenerator streams nothing until its request is cancelled. Each
+// stream's goroutine is counted in running and reported on started.
+type blockingGenerator struct {
+	running *sync.WaitGroup
+	started chan string
+}
+
+func (g blockingGenerator) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	responseChan := make(chan string)
+	errorChan := make(chan error, 1)
+	g.running.Add(1)
+	go func() {
+		defer g.running.Done()
+		defer close(responseChan)
+		defer close(errorChan)
+		g.started <- modelName
+		<-ctx.Done()
+		errorChan <- ctx.Err()
+	}()
+	return responseChan, errorChan
+}
+
+// waitOrFail waits for wg, failing the test if it takes too long
+func waitOrFail(t *testing.T, wg *sync.WaitGroup, what string) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected %s to exit", what)
+	}
+}
+
+// TestQuit_EndsRequestsInFlight verifies a quit from the model cancels every
+// request it started, scoring included, without waiting for shutdown
+func TestQuit_EndsRequestsInFlight(t *testing.T) {
+	var running sync.WaitGroup
+	g := blockingGenerator{running: &running, started: make(chan string, 4)}
+	m := &debateModel{
+		model1Name: "mistral:7b",
+		model2Name: "gemma3:4b",
+		generator:  g,
+		topic:      "Should homework be banned?",
+		history:    []Turn{{ModelName: "mistral:7b", Content: "Yes."}},
+		scoreModel: "judge:1b",
+	}
+	m.Init()
+
+	// Score in the background, as the program would run the command
+	score := m.scoreTurn()
+	go score()
+	<-g.started
+
+	m.state = stateStopped
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("Expected a key to quit from the stopped state")
+	}
+	waitOrFail(t, &running, "the scoring goroutine")
+}
+
+// TestShutdown_AfterProgramEndsEarly verifies the generation goroutine
+// exits when the program stops without the model quitting
+func TestShutdown_AfterProgramEndsEarly(t *testing.T) {
+	var running sync.WaitGroup
+	g := blockingGenerator{running: &running, started: make(chan string, 4)}
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		generator:    g,
+		initialTopic: "Should homework be banned?",
+	}
+
+	p := tea.NewProgram(m, tea.WithInput(nil), tea.WithOutput(io.Discard))
+	finished := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		finished <- err
+	}()
+	<-g.started
+
+	p.Quit()
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the program to stop")
+	}
+
+	m.shutdown()
+	waitOrFail(t, &running, "the generation goroutine")
+}
+
+// TestFirstMessage_SeedsOpeningTurn verifies a configured opening is used as
+// model1's turn and model2 generates the reply
+func TestFirstMessage_SeedsOpeningTurn(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		firstMessage: "Homework should be banned in primary schools.",
+	}
+	m.Init()
+	m.textInput.SetValue("Should homework be banned?")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected the debate to start a generation")
+	}
+	cmd()
+
+	if len(m.history) != 1 || m.history[0].ModelName != "mistral:7b" || m.history[0].Content != m.firstMessage {
+		t.Fatalf("Expected the seeded opening from mistral:7b, got %+v", m.history)
+	}
+	if len(requests) != 1 || requests[0].Model != "gemma3:4b" {
+		t.Fatalf("Expected gemma3:4b to generate next, got %+v", requests)
+	}
+	if !strings.Contains(requests[0].Prompt, "[mistral:7b]: Homework should be banned in primary schools.") {
+		t.Errorf("Expected the reply prompt to include the seeded opening")
+	}
+}
+
+// TestRecordErrors_AddsFailedTurn verifies failures are kept in the history
+// when enabled, but not sent to models
+func TestRecordErrors_AddsFailedTurn(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		state:        stateDebating,
+		isGenerating: true,
+		currentTurn:  1,
+		recordErrors: true,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Homework builds discipline."},
+		},
+	}
+
+	m.Update(responseErrorMsg{err: errors.New("connection reset")})
+	if len(m.history) != 2 {
+		t.Fatalf("Expected a failed turn to be recorded, got %d turns", len(m.history))
+	}
+	failed := m.history[1]
+	if failed.ModelName != "gemma3:4b" || failed.Error != "connection reset" {
+		t.Errorf("Unexpected failed turn %+v", failed)
+	}
+
+	// Retrying keeps the failure and generates a fresh turn
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	cmd()
+	if len(m.history) != 2 || m.history[1].Error == "" {
+		t.Errorf("Expected the failure to stay in the history, got %+v", m.history)
+	}
+	if len(requests) != 1 || strings.Contains(requests[0].Prompt, "connection reset") {
+		t.Errorf("Expected the failure to be left out of the prompt")
+	}
+}
+
+// TestRecordErrors_Disabled verifies failures stay out of the history by
+// default
+func TestRecordErrors_Disabled(t *testing.T) {
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		state:        stateDebating,
+		isGenerating: true,
+		history:      []Turn{{ModelName: "gemma3:4b", Content: "Homework builds discipline."}},
+	}
+
+	m.Update(responseErrorMsg{err: errors.New("connection reset")})
+	if len(m.history) != 1 {
+		t.Errorf("Expected no failed turn without -record-errors, got %d turns", len(m.history))
+	}
+}
+
+// TestEchoPrompt_StoresPromptOnTurn verifies turns keep the prompt they were
+// generated from only when prompts are echoed
+func TestEchoPrompt_StoresPromptOnTurn(t *testing.T) {
+	for _, echo := range []bool{true, false} {
+		var requests []GenerateRequest
+		server := newTestServer(t, &requests)
+
+		m := &debateModel{
+			model1Name:   "mistral:7b",
+			model2Name:   "gemma3:4b",
+			ollamaClient: NewOllamaClient(server.URL),
+			topic:        "Should homework be banned?",
+			state:        stateDebating,
+			isGenerating: true,
+			echoPrompt:   echo,
+		}
+		m.Update(m.generateResponse()())
+
+		if len(m.history) != 1 {
+			t.Fatalf("Expected 1 turn, got %d", len(m.history))
+		}
+		if echo && m.history[0].Prompt != requests[0].Prompt {
+			t.Errorf("Expected the turn to store the prompt sent, got %q", m.history[0].Prompt)
+		}
+		if !echo && m.history[0].Prompt != "" {
+			t.Errorf("Expected no stored prompt without -echo-prompt")
+		}
+	}
+}
+
+// TestInit_PersistsFocusedTextInput verifies Init configures the text input
+// on the program's model rather than on a copy
+func TestInit_PersistsFocusedTextInput(t *testing.T) {
+	m := &debateModel{}
+
+	// Bubbletea calls Init through the tea.Model interface
+	var program tea.Model = m
+	if cmd := program.Init(); cmd == nil {
+		t.Error("Expected Init to return the cursor blink command")
+	}
+
+	if !m.textInput.Focused() {
+		t.Error("Expected the text input to be focused after Init")
+	}
+	if m.textInput.Placeholder != "Enter a debate topic..." {
+		t.Errorf("Expected the topic placeholder, got %q", m.textInput.Placeholder)
+	}
+	if m.state != stateInput {
+		t.Errorf("Expected the input state after Init, got %v", m.state)
+	}
+
+	// Typed keys reach the persisted input
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("AI")})
+	if m.textInput.Value() != "AI" {
+		t.Errorf("Expected typed text in the input, got %q", m.textInput.Value())
+	}
+}
+
+// TestStopped_RestartClearsDebate verifies 'r' returns to the topic input
+// with a fresh debate while keeping the configured models
+func TestStopped_RestartClearsDebate(t *testing.T) {
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", model1Alias: "Optimist"}
+	m.Init()
+	m.textInput.SetValue("Should homework be banned?")
+
+	m.topic = "Should homework be banned?"
+	m.state = stateStopped
+	m.history = []Turn{{ModelName: "mistral:7b", Content: "Yes."}, {ModelName: "gemma3:4b", Content: "No."}}
+	m.prunedTurns = 3
+	m.currentTurn = 1
+	m.turnStreak = 1
+	m.continuing = true
+	m.continueFrom = 2
+	m.promptBudget = 100
+	m.errorMsg = "Error: connection reset"
+	m.endReason = "🤝 Consensus reached"
+	m.turnCache = []renderedTurn{{output: "cached"}}
+	m.typewriter.revealed = 6
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+
+	if m.state != stateInput {
+		t.Fatalf("Expected the topic input state, got %v", m.state)
+	}
+	if m.topic != "" || len(m.history) != 0 || m.prunedTurns != 0 || m.currentTurn != 0 || m.turnStreak != 0 || m.isGenerating {
+		t.Errorf("Expected the debate to be cleared, got topic %q, %d turns, turn %d", m.topic, len(m.history), m.currentTurn)
+	}
+	if m.continuing || m.continueFrom != 0 || m.promptBudget != 0 || m.errorMsg != "" || m.endReason != "" || m.turnCache != nil || m.typewriter.revealed != 0 {
+		t.Errorf("Expected per-debate state to be reset")
+	}
+	if m.textInput.Value() != "" || !m.textInput.Focused() {
+		t.Errorf("Expected an empty, focused topic input")
+	}
+	if m.model1Name != "mistral:7b" || m.model2Name != "gemma3:4b" || m.model1Alias != "Optimist" {
+		t.Errorf("Expected the configured models to be kept")
+	}
+}
+
+// TestMaxTurns_StopsDebate verifies the debate ends once the turn limit is
+// reached instead of starting another turn
+func TestMaxTurns_StopsDebate(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		maxTurns:     2,
+		initialTopic: "Should homework be banned?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 20; i++ {
+		msg := cmd()
+		_, cmd = m.Update(msg)
+	}
+
+	if m.state != stateStopped {
+		t.Fatalf("Expected the debate to stop, got state %v", m.state)
+	}
+	if len(m.history) != 2 || len(requests) != 2 {
+		t.Errorf("Expected exactly 2 turns, got %d turns from %d requests", len(m.history), len(requests))
+	}
+	if !strings.Contains(m.endReason, "2 turns") {
+		t.Errorf("Expected the end reason to mention the limit, got %q", m.endReason)
+	}
+}
+
+// TestResponsePrefix_StartsGeneratedTurns verifies the prefix is sent in
+// the prompt and recorded at the start of each generated turn, in both the
+// interactive and headless debates
+func TestResponsePrefix_StartsGeneratedTurns(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:      "mistral:7b",
+		model2Name:      "gemma3:4b",
+		ollamaClient:    NewOllamaClient(server.URL),
+		promptOptions:   PromptOptions{ResponsePrefix: "CLAIM:"},
+		trimBoilerplate: true,
+		maxTurns:        2,
+		initialTopic:    "Should homework be banned?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 20; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	if len(m.history) != 2 {
+		t.Fatalf("Expected 2 turns, got %d", len(m.history))
+	}
+	for i, turn := range m.history {
+		if turn.Content != "CLAIM: ok" {
+			t.Errorf("Turn %d: expected the prefixed response, got %q", i, turn.Content)
+		}
+	}
+	if !strings.HasSuffix(requests[1].Prompt, "CLAIM:") || !strings.Contains(requests[1].Prompt, "[mistral:7b]: CLAIM: ok") {
+		t.Errorf("Expected a primed prompt with the prefixed history, got:\n%s", requests[1].Prompt)
+	}
+
+	headless := &debateModel{
+		model1Name:    "mistral:7b",
+		model2Name:    "gemma3:4b",
+		ollamaClient:  NewOllamaClient(server.URL),
+		promptOptions: PromptOptions{ResponsePrefix: "CLAIM:"},
+		topic:         "Should homework be banned?",
+	}
+	if result := runHeadless(context.Background(), headless, 1); result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+	if headless.history[0].Content != "CLAIM: ok" {
+		t.Errorf("Expected the headless turn to be prefixed, got %q", headless.history[0].Content)
+	}
+}
+
+// TestTextOnDoneChunk_AddedOnce verifies a turn whose final done chunk
+// carries text ends up with that text exactly once
+func TestTextOnDoneChunk_AddedOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Homework", Done: false})
+		json.NewEncoder(w).Encode(GenerateResponse{Response: " builds habits.", Done: true})
+	}))
+	defer server.Close()
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		state:        stateDebating,
+		isGenerating: true,
+	}
+	defer m.shutdown()
+
+	cmd := m.generateResponse()
+	for {
+		msg := cmd()
+		_, cmd = m.Update(msg)
+		if _, ok := msg.(responseCompleteMsg); ok {
+			break
+		}
+		if _, ok := msg.(responseErrorMsg); ok {
+			t.Fatalf("Unexpected error: %v", msg)
+		}
+	}
+
+	if len(m.history) != 1 || m.history[0].Content != "Homework builds habits." {
+		t.Errorf("Expected the done chunk's text once, got %+v", m.history)
+	}
+}
+
+// TestRestart_ClearsDebateState verifies nothing about a finished debate
+// carries over into the next one
+func TestRestart_ClearsDebateState(t *testing.T) {
+	m := &debateModel{
+		model1Name:    "phi3:mini",
+		model2Name:    "gemma3:4b",
+		textInput:     textinput.New(),
+		state:         stateStopped,
+		topic:         "Is remote work better?",
+		history:       []Turn{{ModelName: "phi3:mini", Content: "Remote work wins."}},
+		prunedArgs:    2,
+		meta:          DebateMeta{ID: "abc"},
+		currentTurn:   1,
+		turnStreak:    1,
+		swappedOut:    map[string]int{"llama3:8b": 0},
+		outputWarning: "⚠️ Transcript not saved",
+		endReason:     "🤝 Consensus reached",
+		lastChunk:     "ok",
+	}
+
+	m.restart()
+
+	if m.state != stateInput || m.topic != "" || len(m.history) != 0 || m.prunedArgs != 0 {
+		t.Errorf("Expected an empty debate at the topic input, got state %v topic %q history %+v", m.state, m.topic, m.history)
+	}
+	if m.meta.ID != "" || m.currentTurn != 0 || m.turnStreak != 0 || m.swappedOut != nil {
+		t.Errorf("Expected the metadata, turn order and swaps cleared, got ID %q turn %d streak %d swapped %v", m.meta.ID, m.currentTurn, m.turnStreak, m.swappedOut)
+	}
+	if m.outputWarning != "" || m.endReason != "" || m.lastChunk != "" {
+		t.Errorf("Expected the last debate's notes cleared, got warning %q end %q chunk %q", m.outputWarning, m.endReason, m.lastChunk)
+	}
+	if m.model1Name != "phi3:mini" || m.model2Name != "gemma3:4b" {
+		t.Errorf("Expected the models kept, got %s vs %s", m.model1Name, m.model2Name)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// moderatorName is the speaker name of moderator interjections in the view,
+// exports, and the models' prompts
+const moderatorName = "Moderator"
+
+// defaultModerateEvery is how many debate turns pass between interjections
+// unless -moderate-every says otherwise
+const defaultModerateEvery = 4
+
+// moderationDue reports whether the moderator should interject before the
+// next turn: when every is positive and at least every arguments have been
+// made since the last interjection. pruned is the number of arguments
+// dropped from the front of history since the last interjection, counted
+// when history holds none. Recorded failures and theses are not arguments.
+func moderationDue(history []Turn, pruned, every int) bool {
+	if every <= 0 {
+		return false
+	}
+
+	arguments := pruned
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Moderator {
+			arguments -= pruned
+			break
+		}
+		if history[i].isArgument() {
+			arguments++
+		}
+	}
+	return arguments >= every
+}
+
+// moderationDue reports whether the moderator interjects before the next
+// turn
+func (m *debateModel) moderationDue() bool {
+	return m.moderator != "" && moderationDue(m.history, m.prunedUnmod, m.moderateEvery)
+}
+
+// moderatorTurn returns a new moderator interjection holding content
+func (m *debateModel) moderatorTurn(content string) Turn {
+	return Turn{
+		ModelName:   m.moderator,
+		DisplayName: moderatorName,
+		Content:     content,
+		Timestamp:   time.Now(),
+		Prompt:      m.storedPrompt(m.lastPrompt),
+		Moderator:   true,
+	}
+}
+
+// generateModeration asks the moderator for an interjection. The debaters'
+// rotation is left as it is, so the model whose turn it is speaks next.
+func (m *debateModel) generateModeration() tea.Cmd {
+	m.moderating = true
+	prompt := BuildModeratorPrompt(m.topic, m.promptHistory())
+	return m.startGeneration(m.moderator, prompt)
+}
+
+// finishModeration keeps the completed interjection and starts the next
+// debater's turn
+func (m *debateModel) finishModeration() tea.Cmd {
+	m.applyContentFilter()
+	m.reportTurn(false)
+	m.moderating = false
+	m.pruneHistory()
+
+	m.isGenerating = true
+	return m.generateResponse()
+}
+
+// skipModeration drops a failed interjection, noting why, and lets the
+// debate carry on without it
+func (m *debateModel) skipModeration(err error) tea.Cmd {
+	m.cancelGeneration()
+	m.moderating = false
+	if m.turnStarted {
+		m.history = m.history[:len(m.history)-1]
+		m.turnStarted = false
+	}
+	m.errorMsg = fmt.Sprintf("Moderator skipped: %v", err)
+
+	m.isGenerating = true
+	return m.generateResponse()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestModerationDue(t *testing.T) {
+	argument := Turn{ModelName: "mistral:7b", Content: "An argument."}
+	failed := Turn{ModelName: "mistral:7b", Error: "timeout"}
+	moderator := Turn{ModelName: "llama3:8b", Content: "Consider costs.", Moderator: true}
+
+	tests := []struct {
+		name    string
+		history []Turn
+		pruned  int
+		every   int
+		want    bool
+	}{
+		{"empty", nil, 0, 2, false},
+		{"before cadence", []Turn{argument}, 0, 2, false},
+		{"at cadence", []Turn{argument, argument}, 0, 2, true},
+		{"just interjected", []Turn{argument, argument, moderator}, 0, 2, false},
+		{"counts since last interjection", []Turn{argument, argument, moderator, argument}, 0, 2, false},
+		{"again at cadence", []Turn{argument, argument, moderator, argument, argument}, 0, 2, true},
+		{"failures are not arguments", []Turn{argument, failed}, 0, 2, false},
+		{"disabled", []Turn{argument, argument}, 0, 0, false},
+		{"counts pruned arguments", []Turn{argument}, 3, 4, true},
+		{"pruned before the interjection", []Turn{moderator, argument}, 3, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moderationDue(tt.history, tt.pruned, tt.every); got != tt.want {
+				t.Errorf("moderationDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestModerator_InterjectsAtCadence verifies interjections enter the
+// interactive debate's history every K turns, without disturbing the
+// rotation, and reach the next debater's prompt
+func TestModerator_InterjectsAtCadence(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:    "mistral:7b",
+		model2Name:    "gemma3:4b",
+		ollamaClient:  NewOllamaClient(server.URL),
+		moderator:     "llama3:8b",
+		moderateEvery: 2,
+		maxTurns:      5, // Interjections do not count
+		initialTopic:  "Should homework be banned?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	want := []string{"mistral:7b", "gemma3:4b", "llama3:8b", "mistral:7b", "gemma3:4b", "llama3:8b", "mistral:7b"}
+	if len(m.history) != len(want) {
+		t.Fatalf("Expected %d turns, got %d", len(want), len(m.history))
+	}
+	for i, turn := range m.history {
+		isModerator := i == 2 || i == 5
+		if turn.ModelName != want[i] || turn.Moderator != isModerator {
+			t.Errorf("Turn %d: expected %s (moderator %v), got %s (moderator %v)", i, want[i], isModerator, turn.ModelName, turn.Moderator)
+		}
+	}
+	if m.history[2].Speaker() != moderatorName {
+		t.Errorf("Expected interjections to be attributed to the moderator, got %q", m.history[2].Speaker())
+	}
+
+	if !strings.Contains(requests[2].Prompt, "You are the moderator") {
+		t.Errorf("Expected the moderator prompt, got:\n%s", requests[2].Prompt)
+	}
+	if !strings.Contains(requests[3].Prompt, "[Moderator]: ok") {
+		t.Errorf("Expected the interjection in the next debater's context, got:\n%s", requests[3].Prompt)
+	}
+	if strings.Contains(requests[3].Prompt, "opening argument") {
+		t.Errorf("Expected the moderator not to count as the debater having spoken")
+	}
+}
+
+// TestModerator_Headless verifies headless debates interject at the same
+// cadence, with turns counting only arguments
+func TestModerator_Headless(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:    "mistral:7b",
+		model2Name:    "gemma3:4b",
+		ollamaClient:  NewOllamaClient(server.URL),
+		topic:         "Should homework be banned?",
+		moderator:     "llama3:8b",
+		moderateEvery: 3,
+	}
+	if result := runHeadless(context.Background(), m, 6); result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+
+	var moderatorAt []int
+	for i, turn := range m.history {
+		if turn.Moderator {
+			moderatorAt = append(moderatorAt, i)
+		}
+	}
+	if len(m.history) != 7 || len(moderatorAt) != 1 || moderatorAt[0] != 3 {
+		t.Errorf("Expected 6 arguments with one interjection after the third, got %d turns with interjections at %v", len(m.history), moderatorAt)
+	}
+}
+
+// TestModerator_KeepsCadenceWhenPruned verifies the moderator still
+// interjects when the history cap is smaller than its cadence
+func TestModerator_KeepsCadenceWhenPruned(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:    "mistral:7b",
+		model2Name:    "gemma3:4b",
+		ollamaClient:  NewOllamaClient(server.URL),
+		topic:         "Should homework be banned?",
+		moderator:     "llama3:8b",
+		moderateEvery: 3,
+		historyCap:    1,
+	}
+	if result := runHeadless(context.Background(), m, 7); result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+
+	interjections := 0
+	for _, req := range requests {
+		if req.Model == "llama3:8b" {
+			interjections++
+		}
+	}
+	if interjections != 2 {
+		t.Errorf("Expected interjections after the third and sixth arguments, got %d", interjections)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// safeName makes a model name or alias safe to embed in transcripts and the
+// terminal. Names such as "hf.co/user/model:q4" keep their slashes and
+// colons, but control characters (which could inject terminal escapes or
+// break lines) are replaced with spaces, and square brackets become
+// parentheses so the "[name]:" attribution format stays unambiguous.
+func safeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '[':
+			return '('
+		case r == ']':
+			return ')'
+		case unicode.IsControl(r):
+			return ' '
+		}
+		return r
+	}, name)
+	return strings.TrimSpace(name)
+}
+
+// sanitizeFilename turns a model name into a string safe to use as part of a
+// file name on any platform. Path separators, colons and other reserved
+// characters become dashes.
+func sanitizeFilename(name string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' {
+			b.WriteRune(r)
+			lastDash = false
+		} else if !lastDash {
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	sanitized := strings.Trim(b.String(), "-.")
+	if sanitized == "" {
+		return "model"
+	}
+	return sanitized
+}
+
+// debateFileName derives a transcript file name from the two models and the
+// time the debate ended, e.g. "phi3-mini-vs-gemma3-4b-20240101-150405"
+func debateFileName(model1, model2 string, at time.Time, ext string) string {
+	return fmt.Sprintf("%s-vs-%s-%s%s", sanitizeFilename(model1), sanitizeFilename(model2), at.Format("20060102-150405"), ext)
+}
@@ -1,44 +1,263 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 )
 
-// OllamaClient handles communication with the Ollama API
+// ErrNoModelsInstalled is returned when Ollama is reachable but has no models
+var ErrNoModelsInstalled = errors.New("no models installed; run 'ollama pull <model>'")
+
+// ErrContextExceeded is returned when a prompt is longer than the model's
+// context window
+var ErrContextExceeded = errors.New("prompt exceeds the model's context length")
+
+// ErrIdleStream is returned when a model streams too many consecutive empty
+// chunks without finishing
+var ErrIdleStream = errors.New("model streamed only empty chunks")
+
+// ErrStreamTruncated is returned under SetStrictStream when a stream ends
+// before its final chunk, which marks a response that was cut short
+var ErrStreamTruncated = errors.New("stream ended before the model finished")
+
+// defaultMaxIdleChunks is the number of consecutive empty or whitespace
+// chunks after which a generation is abandoned
+const defaultMaxIdleChunks = 500
+
+// contextExceededSignatures are fragments of Ollama error messages reporting
+// that the prompt did not fit in the context window
+var contextExceededSignatures = []string{
+	"context length",
+	"context window",
+	"exceeds the context",
+	"input too long",
+	"prompt is too long",
+}
+
+// OllamaClient handles communication with the Ollama API. It is safe for
+// concurrent use: settings may be changed while generations are running,
+// and each generation uses the settings in effect when it was started.
 type OllamaClient struct {
 	baseURL    string
 	httpClient *http.Client
+
+	// mu guards the settings below, which setters replace while
+	// generations read them
+	mu        sync.RWMutex
+	options   map[string]interface{}            // Extra model options sent with every generate request
+	perModel  map[string]map[string]interface{} // Options for one model, keyed by tag, overriding options
+	stop      []string                          // Stop sequences sent as the "stop" option
+	userAgent string                            // User-Agent header sent with every request
+
+	// onRateLimit is told how long a rate-limited request waits before
+	// its retry
+	onRateLimit func(wait time.Duration)
+
+	// onBackpressure is told when handing a chunk to the consumer blocked
+	// for at least backpressureThreshold (nil disables the timing)
+	onBackpressure        func(modelName string, blocked time.Duration)
+	backpressureThreshold time.Duration
+
+	// onRawChunk is given each streamed chunk's bytes as they arrived, before
+	// decoding
+	onRawChunk func(modelName string, raw []byte)
+
+	// onDone is told the statistics of each generation that finishes
+	onDone func(modelName string, stats GenerationStats)
+
+	// maxIdleChunks is the number of consecutive empty or whitespace chunks
+	// tolerated before a generation fails with ErrIdleStream (0 disables)
+	maxIdleChunks int
+
+	// strictStream fails a generation with ErrStreamTruncated when its
+	// stream ends without a final chunk, rather than taking it as complete
+	strictStream bool
+
+	// slots holds a token for each generation in flight, bounding how many
+	// run at once (nil for no limit)
+	slots chan struct{}
 }
 
+// version is the CLI's version, set when building a release with
+// -ldflags "-X main.version=v1.2.0"
+var version = "dev"
+
+// defaultUserAgent identifies the CLI to Ollama and any proxies in front of it
+var defaultUserAgent = "go-argue/" + version
+
+// defaultOllamaURL is where Ollama listens unless configured otherwise
+const defaultOllamaURL = "http://localhost:11434"
+
 // NewOllamaClient creates a new Ollama client with the specified base URL.
 // If baseURL is empty, defaults to http://localhost:11434
 func NewOllamaClient(baseURL string) *OllamaClient {
 	if baseURL == "" {
-		baseURL = "http://localhost:11434"
+		baseURL = defaultOllamaURL
 	}
 	return &OllamaClient{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		httpClient:    &http.Client{CheckRedirect: checkRedirect},
+		userAgent:     defaultUserAgent,
+		maxIdleChunks: defaultMaxIdleChunks,
+	}
+}
+
+// endpoint returns the URL of an API path such as "api/generate". The path
+// is joined onto the base URL's own, so Ollama mounted under a prefix like
+// http://host/ollama is reached at http://host/ollama/api/generate, and any
+// query in the base URL is kept.
+func (c *OllamaClient) endpoint(path string) string {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		// Let creating the request report the malformed URL
+		return c.baseURL + "/" + path
+	}
+	return base.JoinPath(path).String()
+}
+
+// maxRedirects is the number of redirects followed before giving up
+const maxRedirects = 10
+
+// checkRedirect only lets requests with a body follow 307 and 308 redirects,
+// which preserve the method and body. Other redirects would silently turn a
+// POST into a body-less GET, so they fail with an explanation instead.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	original := via[0]
+	status := req.Response.StatusCode
+	if original.Method != http.MethodGet && status != http.StatusTemporaryRedirect && status != http.StatusPermanentRedirect {
+		return fmt.Errorf("Ollama endpoint redirected %s %s to %s with status %d, which would drop the request body; "+
+			"use the final URL or configure the proxy to redirect with 307/308", original.Method, original.URL, req.URL, status)
+	}
+
+	return nil
+}
+
+// SetOptions sets model options (such as mirostat or repeat_penalty) that
+// are sent in the "options" field of every generate request
+func (c *OllamaClient) SetOptions(options map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.options = copyOptions(options)
+}
+
+// SetStop sets sequences that end generation when the model emits them.
+// They are sent as the "stop" option, replacing any set with SetOptions.
+func (c *OllamaClient) SetStop(stop []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stop = append([]string(nil), stop...)
+}
+
+// SetModelOptions sets options sent only with requests to modelName, which
+// take precedence over those set with SetOptions
+func (c *OllamaClient) SetModelOptions(modelName string, options map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	perModel := make(map[string]map[string]interface{}, len(c.perModel)+1)
+	for name, modelOptions := range c.perModel {
+		perModel[name] = modelOptions
+	}
+	perModel[modelName] = copyOptions(options)
+	c.perModel = perModel
+}
+
+// copyOptions returns a shallow copy of options, so that later changes by
+// the caller do not reach requests already being encoded
+func copyOptions(options map[string]interface{}) map[string]interface{} {
+	if options == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(options))
+	for key, value := range options {
+		copied[key] = value
+	}
+	return copied
+}
+
+// requestOptions returns the options sent with a generate request to
+// modelName. The caller must hold c.mu. The maps it reads are never changed
+// in place, so the result may be used after the lock is released.
+func (c *OllamaClient) requestOptions(modelName string) map[string]interface{} {
+	modelOptions := c.perModel[modelName]
+	if len(c.stop) == 0 && len(modelOptions) == 0 {
+		return c.options
+	}
+
+	options := make(map[string]interface{}, len(c.options)+len(modelOptions)+1)
+	for key, value := range c.options {
+		options[key] = value
+	}
+	for key, value := range modelOptions {
+		options[key] = value
+	}
+	if len(c.stop) > 0 {
+		options["stop"] = c.stop
 	}
+	return options
+}
+
+// SetUserAgent sets the User-Agent header sent with every request
+func (c *OllamaClient) SetUserAgent(userAgent string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userAgent = userAgent
+}
+
+// agent returns the User-Agent header to send
+func (c *OllamaClient) agent() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.userAgent
+}
+
+// SetMaxIdleChunks sets how many consecutive empty or whitespace chunks a
+// generation may stream before it fails. Zero disables the limit.
+func (c *OllamaClient) SetMaxIdleChunks(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxIdleChunks = n
+}
+
+// SetStrictStream sets whether a stream that ends before its final chunk,
+// marked "done", fails with ErrStreamTruncated. Otherwise such a stream is
+// taken as complete.
+func (c *OllamaClient) SetStrictStream(strict bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.strictStream = strict
 }
 
 // ListModels returns a list of available models from Ollama
 func (c *OllamaClient) ListModels() ([]string, error) {
-	url := fmt.Sprintf("%s/api/tags", c.baseURL)
+	url := c.endpoint("api/tags")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.agent())
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ollama: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Ollama API returned status %d", resp.StatusCode)
+		return nil, statusError(resp)
 	}
 
 	var result struct {
@@ -59,6 +278,52 @@ func (c *OllamaClient) ListModels() ([]string, error) {
 	return models, nil
 }
 
+// Version returns the version of the Ollama server, such as "0.5.7"
+func (c *OllamaClient) Version() (string, error) {
+	url := c.endpoint("api/version")
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.agent())
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp)
+	}
+
+	var result struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse Ollama response: %w", err)
+	}
+	if result.Version == "" {
+		return "", errors.New("Ollama did not report a version")
+	}
+
+	return result.Version, nil
+}
+
+// EnsureModelsInstalled checks that Ollama has at least one model installed.
+// It returns ErrNoModelsInstalled when the model list is empty.
+func (c *OllamaClient) EnsureModelsInstalled() error {
+	models, err := c.ListModels()
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+	if len(models) == 0 {
+		return ErrNoModelsInstalled
+	}
+	return nil
+}
+
 // ValidateModel checks if a model is available in Ollama
 func (c *OllamaClient) ValidateModel(modelName string) error {
 	models, err := c.ListModels()
@@ -77,67 +342,171 @@ func (c *OllamaClient) ValidateModel(modelName string) error {
 
 // GenerateRequest represents the request body for Ollama's generate API
 type GenerateRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model   string                 `json:"model"`
+	Prompt  string                 `json:"prompt"`
+	Stream  bool                   `json:"stream"`
+	Options map[string]interface{} `json:"options,omitempty"`
 }
 
 // GenerateResponse represents a single response chunk from Ollama
 type GenerateResponse struct {
-	Model    string `json:"model"`
-	Response string `json:"response"`
-	Done     bool   `json:"done"`
-	Context  []int  `json:"context,omitempty"`
+	Model    string       `json:"model"`
+	Response string       `json:"response"`
+	Done     bool         `json:"done"`
+	Context  []int        `json:"context,omitempty"`
+	Message  *ChatMessage `json:"message,omitempty"` // Set instead of Response by the chat API
+	Error    string       `json:"error,omitempty"`   // Set instead of a chunk when generation fails mid-stream
+
+	// Set on the final chunk only
+	DoneReason    string `json:"done_reason,omitempty"`
+	EvalCount     int    `json:"eval_count,omitempty"`
+	TotalDuration int64  `json:"total_duration,omitempty"` // Nanoseconds
+}
+
+// text returns the chunk's text from either the generate or the chat API
+func (r GenerateResponse) text() string {
+	if r.Message != nil {
+		return r.Response + r.Message.Content
+	}
+	return r.Response
 }
 
 // GenerateResponse generates a streaming response from a model.
 // It returns two channels: one for response chunks and one for errors.
 // The channels will be closed when the generation is complete or an error occurs.
 func (c *OllamaClient) GenerateResponse(ctx context.Context, modelName, prompt string) (<-chan string, <-chan error) {
+	return c.GenerateResponseWithOptions(ctx, modelName, prompt, nil)
+}
+
+// GenerateResponseWithOptions generates a streaming response like
+// GenerateResponse, with overrides taking precedence over the client's
+// options for this request only
+func (c *OllamaClient) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	return c.stream(ctx, "api/generate", modelName, overrides, func(options map[string]interface{}) interface{} {
+		return GenerateRequest{
+			Model:   modelName,
+			Prompt:  prompt,
+			Stream:  true,
+			Options: options,
+		}
+	})
+}
+
+// stream posts the request built by body to path and streams the text of
+// the response chunks. body is given the request options, which are the
+// client's options for modelName with overrides applied.
+func (c *OllamaClient) stream(ctx context.Context, path, modelName string, overrides map[string]interface{}, body func(options map[string]interface{}) interface{}) (<-chan string, <-chan error) {
 	responseChan := make(chan string)
 	errorChan := make(chan error, 1)
 
+	// Take the settings now so later changes do not affect this generation
+	c.mu.RLock()
+	options := c.requestOptions(modelName)
+	if len(overrides) > 0 {
+		options = copyOptions(options)
+		if options == nil {
+			options = make(map[string]interface{}, len(overrides))
+		}
+		for key, value := range overrides {
+			options[key] = value
+		}
+	}
+	userAgent := c.userAgent
+	maxIdleChunks := c.maxIdleChunks
+	strictStream := c.strictStream
+	slots := c.slots
+	onBackpressure, backpressureThreshold := c.onBackpressure, c.backpressureThreshold
+	onRawChunk := c.onRawChunk
+	onDone := c.onDone
+	c.mu.RUnlock()
+
 	go func() {
 		defer close(responseChan)
 		defer close(errorChan)
 
-		// Prepare the request
-		reqBody := GenerateRequest{
-			Model:  modelName,
-			Prompt: prompt,
-			Stream: true,
+		// Wait for a free slot when too many generations are running
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return
+			}
 		}
 
-		jsonData, err := json.Marshal(reqBody)
+		jsonData, err := json.Marshal(body(options))
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to marshal request: %w", err)
 			return
 		}
 
-		url := fmt.Sprintf("%s/api/generate", c.baseURL)
+		url := c.endpoint(path)
 		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to create request: %w", err)
 			return
 		}
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
 
-		// Send the request
-		resp, err := c.httpClient.Do(req)
+		// Send the request, waiting out one rate limit
+		resp, err := c.do(req)
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to send request: %w", err)
 			return
 		}
 		defer resp.Body.Close()
 
+		// Close the body as soon as the context is cancelled so a read
+		// blocked on a stalled stream returns at once, whatever transport
+		// is in use
+		stopClosing := context.AfterFunc(ctx, func() { resp.Body.Close() })
+		defer stopClosing()
+
 		if resp.StatusCode != http.StatusOK {
-			errorChan <- fmt.Errorf("Ollama API returned status %d", resp.StatusCode)
+			errorChan <- apiError(resp)
 			return
 		}
 
-		// Read the streaming response
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
+		// Read the streaming response. A JSON decoder hands over each
+		// object as soon as its closing brace arrives, so the first token is
+		// not held back waiting for a newline or a full read buffer. It
+		// also skips any whitespace between objects, so blank or
+		// whitespace-only keep-alive lines injected by proxies are ignored.
+		decoder := json.NewDecoder(resp.Body)
+		idleChunks := 0
+		for {
+			// Each object is kept as it arrived, before decoding replaces
+			// invalid UTF-8, for -debug
+			var raw json.RawMessage
+			var genResp GenerateResponse
+			err := decoder.Decode(&raw)
+			if err == nil {
+				err = json.Unmarshal(raw, &genResp)
+			}
+			if err != nil {
+				// The body ended between chunks, but without the final one
+				if errors.Is(err, io.EOF) {
+					if strictStream {
+						errorChan <- ErrStreamTruncated
+					}
+					return
+				}
+				if ctx.Err() != nil {
+					errorChan <- ctx.Err()
+					return
+				}
+				var syntaxErr *json.SyntaxError
+				var typeErr *json.UnmarshalTypeError
+				if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+					errorChan <- fmt.Errorf("failed to parse response: %w", err)
+				} else {
+					errorChan <- fmt.Errorf("error reading response: %w", err)
+				}
+				return
+			}
+
 			// Check if context was cancelled
 			select {
 			case <-ctx.Done():
@@ -146,38 +515,98 @@ func (c *OllamaClient) GenerateResponse(ctx context.Context, modelName, prompt s
 			default:
 			}
 
-			line := scanner.Bytes()
-			if len(line) == 0 {
-				continue
+			if onRawChunk != nil {
+				onRawChunk(modelName, raw)
 			}
 
-			var genResp GenerateResponse
-			if err := json.Unmarshal(line, &genResp); err != nil {
-				errorChan <- fmt.Errorf("failed to parse response: %w", err)
+			// Ollama reports a failure after the stream has begun as an
+			// error object in place of the next chunk
+			if genResp.Error != "" {
+				errorChan <- streamError(genResp.Error)
 				return
 			}
 
-			// Send the response chunk
-			if genResp.Response != "" {
+			// Give up on a stream that never produces text or finishes
+			text := genResp.text()
+			if strings.TrimSpace(text) == "" && !genResp.Done {
+				idleChunks++
+				if maxIdleChunks > 0 && idleChunks >= maxIdleChunks {
+					errorChan <- fmt.Errorf("%w: %d in a row", ErrIdleStream, idleChunks)
+					return
+				}
+			} else {
+				idleChunks = 0
+			}
+
+			// Send the response chunk. Some Ollama versions put the last
+			// of the text on the done chunk, so it is sent before the
+			// stream is closed below, and only here.
+			if text != "" {
+				sendStart := time.Now()
 				select {
-				case responseChan <- genResp.Response:
+				case responseChan <- text:
 				case <-ctx.Done():
 					errorChan <- ctx.Err()
 					return
 				}
+
+				// Report a consumer too slow to keep up with the model
+				if blocked := time.Since(sendStart); onBackpressure != nil && blocked >= backpressureThreshold {
+					onBackpressure(modelName, blocked)
+				}
 			}
 
 			// Check if generation is complete
 			if genResp.Done {
+				if onDone != nil {
+					onDone(modelName, genResp.stats())
+				}
 				return
 			}
 		}
-
-		if err := scanner.Err(); err != nil {
-			errorChan <- fmt.Errorf("error reading response: %w", err)
-			return
-		}
 	}()
 
 	return responseChan, errorChan
 }
+
+// apiError builds an error for a non-OK response, including the message from
+// Ollama's {"error": "..."} body when present. Context length failures wrap
+// ErrContextExceeded.
+func apiError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(data, &body) != nil || body.Error == "" {
+		return statusError(resp)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: Ollama API returned status %d: %s", ErrRateLimited, resp.StatusCode, body.Error)
+	}
+
+	if contextExceeded(body.Error) {
+		return fmt.Errorf("%w: Ollama API returned status %d: %s", ErrContextExceeded, resp.StatusCode, body.Error)
+	}
+	return fmt.Errorf("Ollama API returned status %d: %s", resp.StatusCode, body.Error)
+}
+
+// streamError builds an error for an error object received in place of a
+// chunk. Context length failures wrap ErrContextExceeded.
+func streamError(message string) error {
+	if contextExceeded(message) {
+		return fmt.Errorf("%w: Ollama API reported mid-stream: %s", ErrContextExceeded, message)
+	}
+	return fmt.Errorf("Ollama API reported mid-stream: %s", message)
+}
+
+// contextExceeded reports whether an error message from Ollama says the
+// prompt did not fit the model's context window
+func contextExceeded(message string) bool {
+	lower := strings.ToLower(message)
+	for _, signature := range contextExceededSignatures {
+		if strings.Contains(lower, signature) {
+			return true
+		}
+	}
+	return false
+}
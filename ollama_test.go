@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -68,6 +71,38 @@ func TestListModels_Success(t *testing.T) {
 	}
 }
 
+// TestVersion tests parsing the server version
+func TestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/version" {
+			t.Errorf("Expected path /api/version, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version":"0.5.7"}`))
+	}))
+	defer server.Close()
+
+	version, err := NewOllamaClient(server.URL).Version()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if version != "0.5.7" {
+		t.Errorf("Expected version 0.5.7, got %s", version)
+	}
+}
+
+// TestVersion_Missing tests servers that do not report a version
+func TestVersion_Missing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewOllamaClient(server.URL).Version(); err == nil {
+		t.Error("Expected error when no version is reported")
+	}
+}
+
 // TestListModels_NetworkError tests handling of network failures
 func TestListModels_NetworkError(t *testing.T) {
 	// Use an invalid URL to simulate network failure
@@ -169,6 +204,40 @@ func TestValidateModel_NotFound(t *testing.T) {
 	}
 }
 
+// TestEnsureModelsInstalled_Empty tests detection of an empty model list
+func TestEnsureModelsInstalled_Empty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"models": []}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	err := client.EnsureModelsInstalled()
+
+	if !errors.Is(err, ErrNoModelsInstalled) {
+		t.Fatalf("Expected ErrNoModelsInstalled, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "ollama pull") {
+		t.Errorf("Expected error message to suggest ollama pull, got: %v", err)
+	}
+}
+
+// TestEnsureModelsInstalled_Success tests that installed models pass the check
+func TestEnsureModelsInstalled_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"models": [{"name": "mistral:7b"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	if err := client.EnsureModelsInstalled(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
 // TestGenerateResponse_RequestFormatting tests HTTP request formatting
 func TestGenerateResponse_RequestFormatting(t *testing.T) {
 	requestReceived := false
@@ -286,6 +355,105 @@ func TestGenerateResponse_StreamingParsing(t *testing.T) {
 	}
 }
 
+// TestGenerateResponse_TextOnDoneChunk tests that text carried by the done
+// chunk is delivered exactly once, and nothing after it
+func TestGenerateResponse_TextOnDoneChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Hello", Done: false})
+		json.NewEncoder(w).Encode(GenerateResponse{Response: " world", Done: true})
+		json.NewEncoder(w).Encode(GenerateResponse{Response: " again", Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	response, err := collectResponse(context.Background(), client, "mistral:7b", "test", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response != "Hello world" {
+		t.Errorf("Expected %q, got %q", "Hello world", response)
+	}
+}
+
+// TestGenerateResponse_KeepAliveLines tests that blank and whitespace-only
+// keep-alive lines injected by proxies between chunks are skipped
+func TestGenerateResponse_KeepAliveLines(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("\n  \n"))
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Hello", Done: false})
+		w.Write([]byte("\t\r\n \r\n\n"))
+		json.NewEncoder(w).Encode(GenerateResponse{Response: " world", Done: false})
+		w.Write([]byte("   \n"))
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true})
+		w.Write([]byte(" \n\t\n"))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	response, err := collectResponse(context.Background(), client, "mistral:7b", "test", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response != "Hello world" {
+		t.Errorf("Expected %q, got %q", "Hello world", response)
+	}
+}
+
+// TestGenerateResponse_FirstChunkLatency tests that a chunk is delivered as
+// soon as its JSON object is complete, even when it arrives split across
+// writes and before the line ends
+func TestGenerateResponse_FirstChunkLatency(t *testing.T) {
+	received := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := w.(http.Flusher)
+
+		// Split the first object mid-string and hold back its newline
+		w.Write([]byte(`{"model":"mistral:7b","respon`))
+		f.Flush()
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`se":"Hello","done":false}`))
+		f.Flush()
+
+		// Only finish once the client has seen the first chunk
+		select {
+		case <-received:
+		case <-time.After(2 * time.Second):
+			return
+		}
+		w.Write([]byte("\n"))
+		json.NewEncoder(w).Encode(GenerateResponse{Model: "mistral:7b", Response: " world", Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	start := time.Now()
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+
+	select {
+	case chunk := <-responseChan:
+		if chunk != "Hello" {
+			t.Errorf("Expected first chunk 'Hello', got %q", chunk)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("First chunk took %v", elapsed)
+		}
+		close(received)
+	case <-time.After(2 * time.Second):
+		t.Fatal("First chunk was not delivered before the line ended")
+	}
+
+	var rest []string
+	for chunk := range responseChan {
+		rest = append(rest, chunk)
+	}
+	if err := <-errorChan; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(rest) != 1 || rest[0] != " world" {
+		t.Errorf("Expected final chunk ' world', got %v", rest)
+	}
+}
+
 // TestGenerateResponse_NetworkError tests handling of network errors during generation
 func TestGenerateResponse_NetworkError(t *testing.T) {
 	// Use an invalid URL to simulate network failure
@@ -387,6 +555,61 @@ func TestGenerateResponse_ContextCancellation(t *testing.T) {
 	}
 }
 
+// TestGenerateResponse_CancelDuringStalledBody tests that cancellation
+// unblocks a read waiting on a stream that has stopped mid-object, and a
+// send to a consumer that has stopped reading
+func TestGenerateResponse_CancelDuringStalledBody(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Start"})
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Unread"})
+		w.Write([]byte(`{"response":"Sta`))
+		w.(http.Flusher).Flush()
+
+		// Stall without finishing the object or closing the body
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	for _, name := range []string{"blocked on read", "blocked on send"} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			responseChan, errorChan := NewOllamaClient(server.URL).GenerateResponse(ctx, "mistral:7b", "test")
+
+			if name == "blocked on read" {
+				<-responseChan
+				<-responseChan
+			}
+			time.Sleep(50 * time.Millisecond)
+
+			start := time.Now()
+			cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				for range responseChan {
+				}
+				done <- <-errorChan
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("Expected context.Canceled, got %v", err)
+				}
+				if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+					t.Errorf("Cancellation took %v", elapsed)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Generation did not stop after cancellation")
+			}
+		})
+	}
+}
+
 // TestGenerateResponse_InvalidJSON tests handling of malformed JSON in streaming response
 func TestGenerateResponse_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -424,3 +647,479 @@ func TestGenerateResponse_InvalidJSON(t *testing.T) {
 		t.Errorf("Expected error message about parsing failure, got: %v", err)
 	}
 }
+
+// TestGenerateResponse_ContextExceeded tests detection of context length errors
+func TestGenerateResponse_ContextExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"input length exceeds the context length"}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+
+	for range responseChan {
+	}
+
+	err := <-errorChan
+	if !errors.Is(err, ErrContextExceeded) {
+		t.Fatalf("Expected ErrContextExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("Expected error message to keep the status code, got: %v", err)
+	}
+}
+
+// TestGenerateResponse_ErrorBody tests that other API errors include the message
+func TestGenerateResponse_ErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model 'nope' not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "nope", "test")
+
+	for range responseChan {
+	}
+
+	err := <-errorChan
+	if errors.Is(err, ErrContextExceeded) {
+		t.Errorf("Expected a generic error, got ErrContextExceeded")
+	}
+	if err == nil || !strings.Contains(err.Error(), "model 'nope' not found") {
+		t.Errorf("Expected error message from the response body, got: %v", err)
+	}
+}
+
+// TestGenerateResponse_PreservesBodyAcross307 tests that POST bodies survive
+// temporary redirects from a reverse proxy
+func TestGenerateResponse_PreservesBodyAcross307(t *testing.T) {
+	var received GenerateRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/generate", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ollama/api/generate", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/ollama/api/generate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST after redirect, got %s", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "redirected", Done: true})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test prompt")
+
+	var chunks []string
+	for chunk := range responseChan {
+		chunks = append(chunks, chunk)
+	}
+	if err := <-errorChan; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if received.Model != "mistral:7b" || received.Prompt != "test prompt" {
+		t.Errorf("Expected request body to be preserved, got %+v", received)
+	}
+	if len(chunks) != 1 || chunks[0] != "redirected" {
+		t.Errorf("Expected response from the redirect target, got %v", chunks)
+	}
+}
+
+// TestGenerateResponse_RejectsBodyDroppingRedirect tests that a 302 redirect
+// of a POST fails with a clear message
+func TestGenerateResponse_RejectsBodyDroppingRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	for range responseChan {
+	}
+
+	err := <-errorChan
+	if err == nil || !strings.Contains(err.Error(), "307/308") {
+		t.Errorf("Expected a clear redirect error, got: %v", err)
+	}
+}
+
+// TestUserAgent tests that the User-Agent header is sent with every request
+func TestUserAgent(t *testing.T) {
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		if r.URL.Path == "/api/tags" {
+			w.Write([]byte(`{"models": []}`))
+			return
+		}
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	client.ListModels()
+
+	client.SetUserAgent("debate-bot/2.0")
+	client.ListModels()
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	for range responseChan {
+	}
+	<-errorChan
+
+	want := []string{"go-argue/" + version, "debate-bot/2.0", "debate-bot/2.0"}
+	if len(agents) != len(want) {
+		t.Fatalf("Expected %d requests, got %d", len(want), len(agents))
+	}
+	for i := range want {
+		if agents[i] != want[i] {
+			t.Errorf("Request %d: expected User-Agent %q, got %q", i, want[i], agents[i])
+		}
+	}
+}
+
+// TestGenerateResponse_AbortsIdleStream tests that a stream of endless empty
+// chunks fails once the idle limit is reached instead of hanging
+func TestGenerateResponse_AbortsIdleStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+		encoder.Encode(GenerateResponse{Response: "Hello"})
+		for i := 0; r.Context().Err() == nil; i++ {
+			// Alternate empty and whitespace-only chunks, never finishing
+			if err := encoder.Encode(GenerateResponse{Response: strings.Repeat(" ", i%2)}); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	client.SetMaxIdleChunks(20)
+
+	done := make(chan struct{})
+	var chunks []string
+	var err error
+	go func() {
+		defer close(done)
+		responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+		for chunk := range responseChan {
+			chunks = append(chunks, chunk)
+		}
+		err = <-errorChan
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the idle stream to be aborted")
+	}
+
+	if !errors.Is(err, ErrIdleStream) {
+		t.Errorf("Expected ErrIdleStream, got %v", err)
+	}
+	if len(chunks) == 0 || chunks[0] != "Hello" {
+		t.Errorf("Expected text before the idle run to be delivered, got %v", chunks)
+	}
+}
+
+// TestGenerateResponse_IdleCountResetsOnText tests that only consecutive
+// empty chunks count toward the idle limit
+func TestGenerateResponse_IdleCountResetsOnText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+		for i := 0; i < 5; i++ {
+			encoder.Encode(GenerateResponse{Response: ""})
+			encoder.Encode(GenerateResponse{Response: "\n"})
+			encoder.Encode(GenerateResponse{Response: "word"})
+		}
+		encoder.Encode(GenerateResponse{Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	client.SetMaxIdleChunks(3)
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	var text strings.Builder
+	for chunk := range responseChan {
+		text.WriteString(chunk)
+	}
+
+	if err := <-errorChan; err != nil {
+		t.Errorf("Expected no error when text keeps arriving, got %v", err)
+	}
+	if got := strings.Count(text.String(), "word"); got != 5 {
+		t.Errorf("Expected all 5 words, got %q", text.String())
+	}
+}
+
+// TestGenerateResponse_StreamTermination tests how a stream ending with and
+// without its final chunk is taken, with and without strict streaming
+func TestGenerateResponse_StreamTermination(t *testing.T) {
+	tests := []struct {
+		name    string
+		done    bool
+		strict  bool
+		wantErr error
+	}{
+		{"done", true, false, nil},
+		{"done strict", true, true, nil},
+		{"cut short", false, false, nil},
+		{"cut short strict", false, true, ErrStreamTruncated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				encoder.Encode(GenerateResponse{Response: "Hello"})
+				encoder.Encode(GenerateResponse{Response: " there", Done: tt.done})
+			}))
+			defer server.Close()
+
+			client := NewOllamaClient(server.URL)
+			client.SetStrictStream(tt.strict)
+			content, err := collectResponse(context.Background(), client, "mistral:7b", "test", nil)
+
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && content != "Hello there" {
+				t.Errorf("Expected the whole response, got %q", content)
+			}
+		})
+	}
+}
+
+// TestGenerateResponse_ErrorMidStream tests that an error object sent in
+// place of a chunk fails the generation rather than ending it quietly
+func TestGenerateResponse_ErrorMidStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		wantErr error
+	}{
+		{"runner stopped", "model runner has unexpectedly stopped", nil},
+		{"context length", "input length exceeds the context length", ErrContextExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"model":"mistral:7b","response":"Hello","done":false}`)
+				fmt.Fprintf(w, "{\"error\":%q}\n", tt.message)
+				fmt.Fprintln(w, `{"model":"mistral:7b","response":" never sent","done":true}`)
+			}))
+			defer server.Close()
+
+			client := NewOllamaClient(server.URL)
+			var chunks []string
+			_, err := collectResponse(context.Background(), client, "mistral:7b", "test", func(chunk string) {
+				chunks = append(chunks, chunk)
+			})
+
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Fatalf("Expected an error carrying %q, got %v", tt.message, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(chunks) != 1 || chunks[0] != "Hello" {
+				t.Errorf("Expected only the chunk before the error, got %q", chunks)
+			}
+		})
+	}
+}
+
+// TestGenerateResponse_ConcurrentUse tests that one client can run several
+// generations at once while its settings change. Run with -race to catch
+// unsynchronized access.
+func TestGenerateResponse_ConcurrentUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		encoder := json.NewEncoder(w)
+		for _, word := range []string{"answer ", "from ", req.Model} {
+			encoder.Encode(GenerateResponse{Model: req.Model, Response: word})
+			w.(http.Flusher).Flush()
+		}
+		encoder.Encode(GenerateResponse{Model: req.Model, Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	options := map[string]interface{}{"temperature": 0.5}
+	client.SetOptions(options)
+
+	const n = 8
+	results := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			model := fmt.Sprintf("model-%d", i)
+			responseChan, errorChan := client.GenerateResponse(context.Background(), model, "test")
+			var text strings.Builder
+			for chunk := range responseChan {
+				text.WriteString(chunk)
+			}
+			results[i] = text.String()
+			errs[i] = <-errorChan
+		}(i)
+	}
+
+	// Change every setting while the generations run
+	for i := 0; i < n; i++ {
+		client.SetStop([]string{fmt.Sprintf("stop-%d", i)})
+		client.SetModelOptions(fmt.Sprintf("model-%d", i), map[string]interface{}{"seed": i})
+		client.SetUserAgent(fmt.Sprintf("agent-%d", i))
+		client.SetMaxIdleChunks(i + 1)
+		options["temperature"] = float64(i) // The client keeps its own copy
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Errorf("Generation %d failed: %v", i, errs[i])
+		}
+		if want := fmt.Sprintf("answer from model-%d", i); results[i] != want {
+			t.Errorf("Generation %d: expected %q, got %q", i, want, results[i])
+		}
+	}
+}
+
+// TestSetConcurrency_BoundsGenerations verifies no more generations stream at
+// once than the limit allows
+func TestSetConcurrency_BoundsGenerations(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "ok", Done: true})
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	client.SetConcurrency(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := collectResponse(context.Background(), client, "phi3:mini", "Hello", nil); err != nil {
+				t.Errorf("Generation failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 generations at once, got %d", peak)
+	}
+	if peak < 2 {
+		t.Errorf("Expected generations to run side by side up to the limit, got %d at most", peak)
+	}
+}
+
+// TestSetConcurrency_WaitIsCancellable verifies a generation waiting for a
+// slot gives up when its context is cancelled
+func TestSetConcurrency_WaitIsCancellable(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "ok", Done: true})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewOllamaClient(server.URL)
+	client.SetConcurrency(1)
+	busy, _ := client.GenerateResponse(context.Background(), "phi3:mini", "Hello")
+	go func() {
+		for range busy {
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := collectResponse(ctx, client, "phi3:mini", "Hello", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the waiting generation to be cancelled, got %v", err)
+	}
+}
+
+// TestEndpoint_PathPrefix tests that API paths are joined onto a base URL
+// that mounts Ollama under a prefix
+func TestEndpoint_PathPrefix(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"http://localhost:11434", "http://localhost:11434/api/generate"},
+		{"http://host/ollama", "http://host/ollama/api/generate"},
+		{"http://host/ollama/", "http://host/ollama/api/generate"},
+		{"http://host/a/b//", "http://host/a/b/api/generate"},
+		{"https://host/ollama?key=secret", "https://host/ollama/api/generate?key=secret"},
+	}
+
+	for _, tt := range tests {
+		if got := NewOllamaClient(tt.baseURL).endpoint("api/generate"); got != tt.want {
+			t.Errorf("endpoint with base %q = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
+
+// TestPathPrefix_Requests tests that every request reaches the prefixed path
+func TestPathPrefix_Requests(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/ollama/api/tags":
+			w.Write([]byte(`{"models":[{"name":"phi3:mini"}]}`))
+		case "/ollama/api/version":
+			w.Write([]byte(`{"version":"0.5.7"}`))
+		case "/ollama/api/generate":
+			json.NewEncoder(w).Encode(GenerateResponse{Response: "ok", Done: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL + "/ollama/")
+	if _, err := client.ListModels(); err != nil {
+		t.Errorf("ListModels failed: %v", err)
+	}
+	if _, err := client.Version(); err != nil {
+		t.Errorf("Version failed: %v", err)
+	}
+	if _, err := collectResponse(context.Background(), client, "phi3:mini", "Hello", nil); err != nil {
+		t.Errorf("GenerateResponse failed: %v", err)
+	}
+
+	want := []string{"/ollama/api/tags", "/ollama/api/version", "/ollama/api/generate"}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("Expected requests to %v, got %v", want, paths)
+	}
+}
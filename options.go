@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// optionFlags collects repeatable -option key=value flags into the options
+// map sent with each generate request
+type optionFlags map[string]interface{}
+
+// String implements flag.Value
+func (o optionFlags) String() string {
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", key, o[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, parsing a single key=value pair
+func (o optionFlags) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got '%s'", s)
+	}
+
+	o[key] = parseOptionValue(value)
+	return nil
+}
+
+// parseOptionValue infers the type of an option value: true/false become
+// bools, integers and decimals become numbers, JSON arrays and objects are
+// decoded (useful for stop sequences), and anything else stays a string
+func parseOptionValue(value string) interface{} {
+	value = strings.TrimSpace(value)
+
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			return decoded
+		}
+	}
+
+	return value
+}
+
+// stopFlags collects repeatable -stop flags into Ollama's stop sequences.
+// Backslash escapes such as \n are interpreted, so a sequence can be given
+// as "\n\n[" on the command line.
+type stopFlags []string
+
+// String implements flag.Value
+func (s *stopFlags) String() string {
+	quoted := make([]string, len(*s))
+	for i, stop := range *s {
+		quoted[i] = strconv.Quote(stop)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// Set implements flag.Value, adding a single stop sequence
+func (s *stopFlags) Set(value string) error {
+	if unquoted, err := strconv.Unquote(`"` + value + `"`); err == nil {
+		value = unquoted
+	}
+	if value == "" {
+		return fmt.Errorf("stop sequence must not be empty")
+	}
+
+	*s = append(*s, value)
+	return nil
+}
+
+// ParseTemperatures parses a comma-separated -temperatures list such as
+// "0.3,0.9", assigning each value positionally to a model in models. The
+// number of values must match the number of models, and each must lie
+// between 0 and maxTemperature. The result is keyed by model tag, ready for
+// OllamaClient.SetModelOptions.
+func ParseTemperatures(spec string, models []string) (map[string]float64, error) {
+	values := strings.Split(spec, ",")
+	if len(values) != len(models) {
+		return nil, fmt.Errorf("expected %d temperatures, one per model, got %d", len(models), len(values))
+	}
+
+	temperatures := make(map[string]float64, len(models))
+	for i, value := range values {
+		value = strings.TrimSpace(value)
+		t, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid temperature '%s' for %s", value, models[i])
+		}
+		if t < 0 || t > maxTemperature {
+			return nil, fmt.Errorf("temperature for %s must be between 0 and %g, got %g", models[i], maxTemperature, t)
+		}
+		if previous, ok := temperatures[models[i]]; ok && previous != t {
+			return nil, fmt.Errorf("%s is listed twice with different temperatures", models[i])
+		}
+		temperatures[models[i]] = t
+	}
+	return temperatures, nil
+}
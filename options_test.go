@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestOptionFlags_ParsesMixedTypes(t *testing.T) {
+	options := optionFlags{}
+	for _, arg := range []string{
+		"mirostat=2",
+		"repeat_penalty=1.1",
+		"penalize_newline=false",
+		`stop=["\n\n", "User:"]`,
+		"name=debate-bot",
+	} {
+		if err := options.Set(arg); err != nil {
+			t.Fatalf("Set(%q) failed: %v", arg, err)
+		}
+	}
+
+	want := optionFlags{
+		"mirostat":         int64(2),
+		"repeat_penalty":   1.1,
+		"penalize_newline": false,
+		"stop":             []interface{}{"\n\n", "User:"},
+		"name":             "debate-bot",
+	}
+	if !reflect.DeepEqual(options, want) {
+		t.Errorf("Unexpected options:\n got %#v\nwant %#v", options, want)
+	}
+}
+
+func TestOptionFlags_RejectsMissingKey(t *testing.T) {
+	options := optionFlags{}
+	for _, arg := range []string{"novalue", "=1"} {
+		if err := options.Set(arg); err == nil {
+			t.Errorf("Expected error for %q", arg)
+		}
+	}
+}
+
+func TestGenerateResponse_SendsOptions(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Options map[string]interface{} `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = body.Options
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true})
+	}))
+	defer server.Close()
+
+	options := optionFlags{}
+	options.Set("mirostat=2")
+	options.Set("stop=[\"###\"]")
+
+	client := NewOllamaClient(server.URL)
+	client.SetOptions(options)
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	for range responseChan {
+	}
+	<-errorChan
+
+	if received["mirostat"] != float64(2) {
+		t.Errorf("Expected mirostat to be sent as a number, got %#v", received["mirostat"])
+	}
+	if stop, ok := received["stop"].([]interface{}); !ok || len(stop) != 1 || stop[0] != "###" {
+		t.Errorf("Expected stop to be sent as an array, got %#v", received["stop"])
+	}
+}
+
+func TestStopFlags_InterpretsEscapes(t *testing.T) {
+	var stop stopFlags
+	for _, arg := range []string{`\n\n[`, "User:", `say "hi"`} {
+		if err := stop.Set(arg); err != nil {
+			t.Fatalf("Set(%q) failed: %v", arg, err)
+		}
+	}
+
+	want := stopFlags{"\n\n[", "User:", `say "hi"`}
+	if !reflect.DeepEqual(stop, want) {
+		t.Errorf("Unexpected stop sequences:\n got %#v\nwant %#v", stop, want)
+	}
+	if err := stop.Set(""); err == nil {
+		t.Errorf("Expected error for an empty stop sequence")
+	}
+}
+
+func TestGenerateResponse_SendsStopSequences(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Options map[string]interface{} `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = body.Options
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true})
+	}))
+	defer server.Close()
+
+	options := optionFlags{}
+	options.Set("mirostat=2")
+	options.Set("stop=[\"###\"]")
+
+	client := NewOllamaClient(server.URL)
+	client.SetOptions(options)
+	client.SetStop([]string{"\n\n[", "User:"})
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test")
+	for range responseChan {
+	}
+	<-errorChan
+
+	want := []interface{}{"\n\n[", "User:"}
+	if !reflect.DeepEqual(received["stop"], want) {
+		t.Errorf("Expected stop sequences %#v, got %#v", want, received["stop"])
+	}
+	if received["mirostat"] != float64(2) {
+		t.Errorf("Expected other options to be kept, got %#v", received)
+	}
+	if stop := options["stop"].([]interface{}); len(stop) != 1 {
+		t.Errorf("Expected the configured options not to be modified")
+	}
+}
+
+func TestParseTemperatures_AssignsPositionally(t *testing.T) {
+	got, err := ParseTemperatures("0.3, 0.9", []string{"phi3:mini", "gemma3:4b"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]float64{"phi3:mini": 0.3, "gemma3:4b": 0.9}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got, err = ParseTemperatures("0,1,2", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := (map[string]float64{"a": 0, "b": 1, "c": 2}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestParseTemperatures_RejectsInvalid(t *testing.T) {
+	models := []string{"phi3:mini", "gemma3:4b"}
+	for _, spec := range []string{"0.3", "0.3,0.9,1.2", "0.3,", "hot,0.9", "0.3,2.5", "-0.1,0.9"} {
+		if _, err := ParseTemperatures(spec, models); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+
+	if _, err := ParseTemperatures("0.3,0.9", []string{"phi3:mini", "phi3:mini"}); err == nil {
+		t.Error("Expected an error when the same model gets two temperatures")
+	}
+	if _, err := ParseTemperatures("0.5,0.5", []string{"phi3:mini", "phi3:mini"}); err != nil {
+		t.Errorf("Expected matching temperatures for the same model to be accepted, got %v", err)
+	}
+}
+
+func TestParseTemperatures_SentPerModel(t *testing.T) {
+	received := make(map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model   string                 `json:"model"`
+			Options map[string]interface{} `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received[body.Model] = body.Options["temperature"]
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true})
+	}))
+	defer server.Close()
+
+	models := []string{"phi3:mini", "gemma3:4b"}
+	temperatures, err := ParseTemperatures("0.3,0.9", models)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := NewOllamaClient(server.URL)
+	for modelName, temperature := range temperatures {
+		client.SetModelOptions(modelName, map[string]interface{}{"temperature": temperature})
+	}
+	for _, modelName := range models {
+		responseChan, errorChan := client.GenerateResponse(context.Background(), modelName, "test")
+		for range responseChan {
+		}
+		<-errorChan
+	}
+
+	want := map[string]interface{}{"phi3:mini": 0.3, "gemma3:4b": 0.9}
+	if !reflect.DeepEqual(received, want) {
+		t.Errorf("Expected temperatures %v, got %v", want, received)
+	}
+}
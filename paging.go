@@ -0,0 +1,63 @@
+package main
+
+import "fmt"
+
+// pageCount returns how many pages of size turns it takes to hold n turns,
+// which is at least one
+func pageCount(n, size int) int {
+	if size <= 0 || n <= 0 {
+		return 1
+	}
+	return (n + size - 1) / size
+}
+
+// clampPage limits page to the pages holding n turns
+func clampPage(page, n, size int) int {
+	return min(max(page, 0), pageCount(n, size)-1)
+}
+
+// pageBounds returns the indices [start, end) of the turns on page, after
+// clamping it to the pages holding n turns. Without paging every turn is on
+// the one page.
+func pageBounds(page, n, size int) (start, end int) {
+	if size <= 0 {
+		return 0, n
+	}
+	page = clampPage(page, n, size)
+	return page * size, min((page+1)*size, n)
+}
+
+// currentPage returns the page the view shows out of the pages holding n
+// turns: the one the reader moved to, or the last while following the
+// debate
+func (m *debateModel) currentPage(n int) int {
+	if !m.pageBrowsing {
+		return pageCount(n, m.pageTurns) - 1
+	}
+	return clampPage(m.page, n, m.pageTurns)
+}
+
+// turnPage moves the view delta pages, stopping at the first and last.
+// Reaching the last page follows the debate again.
+func (m *debateModel) turnPage(delta int) {
+	n := len(m.history)
+	m.page = clampPage(m.currentPage(n)+delta, n, m.pageTurns)
+	m.pageBrowsing = m.page < pageCount(n, m.pageTurns)-1
+
+	if !m.pageBrowsing && m.autoscroll {
+		m.viewport.GotoBottom()
+	} else {
+		m.viewport.GotoTop()
+	}
+}
+
+// renderPageNote renders which page of n turns is shown
+func (m *debateModel) renderPageNote(n int) string {
+	page := m.currentPage(n)
+	start, end := pageBounds(page, n, m.pageTurns)
+	if end == start {
+		return ""
+	}
+	return subtleStyle.Render(fmt.Sprintf("📄 Page %d of %d • turns %d–%d • PgUp/PgDn to change page",
+		page+1, pageCount(n, m.pageTurns), start+1, end)) + "\n\n"
+}
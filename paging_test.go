@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		page, n    int
+		size       int
+		start, end int
+		count      int
+	}{
+		{"empty", 0, 0, 20, 0, 0, 1},
+		{"first page", 0, 45, 20, 0, 20, 3},
+		{"middle page", 1, 45, 20, 20, 40, 3},
+		{"short last page", 2, 45, 20, 40, 45, 3},
+		{"exactly full", 1, 40, 20, 20, 40, 2},
+		{"past the end clamps", 7, 45, 20, 40, 45, 3},
+		{"before the start clamps", -3, 45, 20, 0, 20, 3},
+		{"paging off", 2, 45, 0, 0, 45, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := pageBounds(tt.page, tt.n, tt.size)
+			if start != tt.start || end != tt.end {
+				t.Errorf("pageBounds(%d, %d, %d) = [%d, %d), want [%d, %d)", tt.page, tt.n, tt.size, start, end, tt.start, tt.end)
+			}
+			if got := pageCount(tt.n, tt.size); got != tt.count {
+				t.Errorf("pageCount(%d, %d) = %d, want %d", tt.n, tt.size, got, tt.count)
+			}
+		})
+	}
+}
+
+// newPagedModel returns a debate of n turns paged size turns at a time
+func newPagedModel(n, size int) *debateModel {
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", pageTurns: size}
+	m.Init()
+	m.height = 1000 // Tall enough to show the whole page
+	m.state = stateDebating
+	for i := 0; i < n; i++ {
+		m.history = append(m.history, Turn{ModelName: m.model1Name, Content: fmt.Sprintf("turn-%02d", i+1)})
+	}
+	return m
+}
+
+func TestPaging_Navigation(t *testing.T) {
+	m := newPagedModel(45, 20)
+	pgUp, pgDown := tea.KeyMsg{Type: tea.KeyPgUp}, tea.KeyMsg{Type: tea.KeyPgDown}
+
+	// The view follows the last page until the reader moves
+	if got := m.currentPage(len(m.history)); got != 2 {
+		t.Fatalf("Expected to start on the last page, got %d", got)
+	}
+	view := m.renderDebateView()
+	if !strings.Contains(view, "turn-41") || strings.Contains(view, "turn-40") {
+		t.Errorf("Expected only the last page's turns rendered")
+	}
+
+	m.Update(pgUp)
+	m.Update(pgUp)
+	if got := m.currentPage(len(m.history)); got != 0 || !m.pageBrowsing {
+		t.Fatalf("Expected PgUp twice to reach the first page, got %d", got)
+	}
+	m.Update(pgUp)
+	if got := m.currentPage(len(m.history)); got != 0 {
+		t.Errorf("Expected PgUp to stop at the first page, got %d", got)
+	}
+	view = m.renderDebateView()
+	if !strings.Contains(view, "Page 1 of 3") || !strings.Contains(view, "turn-20") || strings.Contains(view, "turn-21") {
+		t.Errorf("Expected the first page rendered, got:\n%s", view)
+	}
+
+	// New turns do not move a reader off an earlier page
+	m.history = append(m.history, Turn{ModelName: m.model2Name, Content: "turn-46"})
+	if got := m.currentPage(len(m.history)); got != 0 {
+		t.Errorf("Expected to stay on the first page, got %d", got)
+	}
+
+	m.Update(pgDown)
+	m.Update(pgDown)
+	m.Update(pgDown)
+	if got := m.currentPage(len(m.history)); got != 2 || m.pageBrowsing {
+		t.Errorf("Expected PgDn to stop at the last page and follow again, got %d (browsing %v)", got, m.pageBrowsing)
+	}
+
+	// Once following again, a new page is shown as soon as it starts
+	for i := len(m.history); i < 61; i++ {
+		m.history = append(m.history, Turn{ModelName: m.model1Name, Content: fmt.Sprintf("turn-%02d", i+1)})
+	}
+	if got := m.currentPage(len(m.history)); got != 3 {
+		t.Errorf("Expected to follow onto the new last page, got %d", got)
+	}
+}
+
+func TestPaging_OffKeepsScrolling(t *testing.T) {
+	m := newPagedModel(45, 0)
+	m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.pageBrowsing || m.page != 0 {
+		t.Errorf("Expected PgUp to leave pages alone without paging")
+	}
+	if view := m.renderDebateView(); !strings.Contains(view, "turn-01") || !strings.Contains(view, "turn-45") {
+		t.Errorf("Expected every turn rendered without paging")
+	}
+}
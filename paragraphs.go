@@ -0,0 +1,35 @@
+package main
+
+import "strings"
+
+// splitParagraphs splits a turn's content into paragraphs at blank lines.
+// Any run of blank or whitespace-only lines is a single break, line breaks
+// within a paragraph are kept, and trailing spaces, carriage returns and
+// empty paragraphs at either end are dropped.
+func splitParagraphs(content string) []string {
+	var paragraphs []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return paragraphs
+}
+
+// normalizeParagraphs rewrites content with exactly one blank line between
+// its paragraphs, the form turns take in the view and in exports
+func normalizeParagraphs(content string) string {
+	return strings.Join(splitParagraphs(content), "\n\n")
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitParagraphs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"single paragraph", "One point.", []string{"One point."}},
+		{"blank line", "First.\n\nSecond.", []string{"First.", "Second."}},
+		{"runs of blank lines", "First.\n\n\n\nSecond.", []string{"First.", "Second."}},
+		{"whitespace-only lines", "First.\n  \t\n \nSecond.", []string{"First.", "Second."}},
+		{"windows line endings", "First.\r\n\r\nSecond.\r\n", []string{"First.", "Second."}},
+		{"line breaks kept", "Points:\n- one\n- two\n\nDone.", []string{"Points:\n- one\n- two", "Done."}},
+		{"trailing spaces", "First.   \n\nSecond.  ", []string{"First.", "Second."}},
+		{"edges trimmed", "\n\n  \nFirst.\n\n", []string{"First."}},
+		{"empty", "", nil},
+		{"blank", "\n \n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitParagraphs(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitParagraphs(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeParagraphs(t *testing.T) {
+	if got := normalizeParagraphs("First.\n\n\n\nSecond.\n \nThird.\n"); got != "First.\n\nSecond.\n\nThird." {
+		t.Errorf("Expected one blank line between paragraphs, got %q", got)
+	}
+	// Normalizing is stable
+	once := normalizeParagraphs("a\n\n\nb")
+	if twice := normalizeParagraphs(once); twice != once {
+		t.Errorf("Expected normalizing twice to change nothing, got %q then %q", once, twice)
+	}
+}
+
+// paragraphTurn has paragraphs separated unevenly, as models often write
+func paragraphTurn() Turn {
+	return Turn{ModelName: "phi3:mini", Content: "First point.\n\n\n\nSecond point.\n  \nThird point.", Timestamp: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)}
+}
+
+func TestExportText_Paragraphs(t *testing.T) {
+	var b strings.Builder
+	if err := writeTextTurns([]Turn{paragraphTurn()}, timestampFormat{}, &b); err != nil {
+		t.Fatalf("writeTextTurns failed: %v", err)
+	}
+	if !strings.Contains(b.String(), "First point.\n\nSecond point.\n\nThird point.\n") {
+		t.Errorf("Expected paragraphs separated by one blank line, got %q", b.String())
+	}
+}
+
+func TestExportHTML_Paragraphs(t *testing.T) {
+	var b strings.Builder
+	if err := ExportHTML("Topic", nil, []Turn{paragraphTurn()}, timestampFormat{}, &b); err != nil {
+		t.Fatalf("ExportHTML failed: %v", err)
+	}
+	if !strings.Contains(b.String(), `<div class="content"><p>First point.</p><p>Second point.</p><p>Third point.</p></div>`) {
+		t.Errorf("Expected each paragraph in its own element, got:\n%s", b.String())
+	}
+}
+
+func TestFormatTurn_Paragraphs(t *testing.T) {
+	rendered := formatTurn(paragraphTurn(), true, 80, defaultMinContentWidth, "", timestampFormat{})
+
+	// Collect the text inside the box, one entry per line
+	var lines []string
+	for _, line := range strings.Split(rendered, "\n")[1:] {
+		lines = append(lines, strings.Trim(line, " │╭╮╰╯─|+-"))
+	}
+	text := strings.TrimSpace(strings.Join(lines, "\n"))
+	if text != "First point.\n\nSecond point.\n\nThird point." {
+		t.Errorf("Expected one blank line between paragraphs in the view, got %q", text)
+	}
+}
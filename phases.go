@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DebatePhase is one stage of a structured debate, such as the opening
+// statements or the closing arguments
+type DebatePhase struct {
+	Name        string
+	Instruction string
+	Turns       int // Number of turns the phase lasts
+}
+
+// builtinPhases are the phases that can be named in a schedule. Each lasts
+// one round, giving both models a turn.
+var builtinPhases = map[string]DebatePhase{
+	"opening": {
+		Name:        "opening",
+		Instruction: "This is the opening statement; introduce your position and your main arguments.",
+		Turns:       2,
+	},
+	"rebuttal": {
+		Name:        "rebuttal",
+		Instruction: "This is the rebuttal; directly challenge the weakest points your opponent has made.",
+		Turns:       2,
+	},
+	"cross-examination": {
+		Name:        "cross-examination",
+		Instruction: "This is the cross-examination; ask your opponent pointed questions and answer any they have asked you.",
+		Turns:       2,
+	},
+	"closing": {
+		Name:        "closing",
+		Instruction: "This is the closing statement; summarize your strongest points and explain why your position prevails.",
+		Turns:       2,
+	},
+}
+
+// standardSchedule is the phase schedule selected by "-phases standard"
+var standardSchedule = []string{"opening", "rebuttal", "cross-examination", "closing"}
+
+// ParsePhaseSchedule parses "standard" or a comma-separated list of phase
+// names into a schedule. An empty spec gives an unstructured debate.
+func ParsePhaseSchedule(spec string) ([]DebatePhase, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	names := standardSchedule
+	if spec != "standard" {
+		names = strings.Split(spec, ",")
+	}
+
+	phases := make([]DebatePhase, 0, len(names))
+	for _, name := range names {
+		phase, ok := builtinPhases[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown phase '%s' (use opening, rebuttal, cross-examination, closing, or standard)", strings.TrimSpace(name))
+		}
+		phases = append(phases, phase)
+	}
+
+	return phases, nil
+}
+
+// phaseForTurn returns the phase that the turn at index turn falls in. It
+// returns false once the schedule is over, after which the debate continues
+// unstructured.
+func phaseForTurn(phases []DebatePhase, turn int) (DebatePhase, bool) {
+	start := 0
+	for _, phase := range phases {
+		if turn < start+phase.Turns {
+			return phase, true
+		}
+		start += phase.Turns
+	}
+	return DebatePhase{}, false
+}
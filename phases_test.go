@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPhaseForTurn(t *testing.T) {
+	phases, err := ParsePhaseSchedule("standard")
+	if err != nil {
+		t.Fatalf("Failed to parse schedule: %v", err)
+	}
+
+	want := []string{"opening", "opening", "rebuttal", "rebuttal", "cross-examination", "cross-examination", "closing", "closing"}
+	for turn, name := range want {
+		phase, ok := phaseForTurn(phases, turn)
+		if !ok || phase.Name != name {
+			t.Errorf("Turn %d: expected phase %s, got %s (ok=%v)", turn, name, phase.Name, ok)
+		}
+	}
+
+	if _, ok := phaseForTurn(phases, len(want)); ok {
+		t.Errorf("Expected no phase after the schedule ends")
+	}
+}
+
+func TestBuildDebatePrompt_PhaseInstruction(t *testing.T) {
+	phases, _ := ParsePhaseSchedule("opening,closing")
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: "Opening."},
+		{ModelName: "gemma3:4b", Content: "Counter-opening."},
+	}
+
+	prompt := BuildDebatePromptWithOptions("Should voting be mandatory?", history, "mistral:7b", false,
+		PromptOptions{Phases: phases, TurnIndex: 2})
+	if !strings.Contains(prompt, "This is the closing statement; summarize your strongest points") {
+		t.Errorf("Expected the closing instruction for turn 2, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "This is the opening statement") {
+		t.Errorf("Expected only the current phase's instruction")
+	}
+
+	unstructured := BuildDebatePrompt("Should voting be mandatory?", history, "mistral:7b", false)
+	if strings.Contains(unstructured, "This is the") {
+		t.Errorf("Expected no phase instruction without a schedule")
+	}
+}
+
+func TestParsePhaseSchedule_Unknown(t *testing.T) {
+	if _, err := ParsePhaseSchedule("opening,filibuster"); err == nil {
+		t.Errorf("Expected error for an unknown phase")
+	}
+	if phases, err := ParsePhaseSchedule(""); err != nil || phases != nil {
+		t.Errorf("Expected an empty spec to give an unstructured debate")
+	}
+}
+
+// TestPhases_CountArgumentsOnly verifies interjections, theses and recorded
+// failures do not move a structured debate through its phases
+func TestPhases_CountArgumentsOnly(t *testing.T) {
+	phases, _ := ParsePhaseSchedule("opening,rebuttal")
+	m := &debateModel{
+		model1Name:    "mistral:7b",
+		model2Name:    "gemma3:4b",
+		topic:         "Should voting be mandatory?",
+		promptOptions: PromptOptions{Phases: phases},
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Voting is a duty.", Thesis: true},
+			{ModelName: "mistral:7b", Content: "Opening."},
+			{ModelName: moderatorName, Content: "What about turnout?", Moderator: true},
+			{ModelName: "gemma3:4b", Error: "connection refused"},
+		},
+	}
+	opening, rebuttal := "This is the opening statement", "This is the rebuttal"
+
+	if prompt := m.promptFor("gemma3:4b", m.argumentCount()); !strings.Contains(prompt, opening) {
+		t.Errorf("Expected the second argument still in the opening phase, got:\n%s", prompt)
+	}
+
+	// Arguments pruned from the history still count
+	m.history = m.history[2:]
+	m.prunedTurns, m.prunedArgs = 2, 1
+	m.history = append(m.history, Turn{ModelName: "gemma3:4b", Content: "Counter-opening."})
+	if prompt := m.promptFor("mistral:7b", m.argumentCount()); !strings.Contains(prompt, rebuttal) {
+		t.Errorf("Expected the third argument in the rebuttal phase, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// stdoutIsTerminal reports whether stdout is a terminal. It is a variable so
+// tests can stand in for a terminal or a pipe.
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// stderrIsTerminal reports whether stderr is a terminal, as it usually
+// shares one with the TUI
+func stderrIsTerminal() bool {
+	return term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// configureOutput turns off colors and other escape codes when stdout is not
+// a terminal, whatever the flags ask for, so piped output is plain text
+func configureOutput(isTerminal bool) {
+	if !isTerminal {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// textSink writes a debate to w as plain text, one whole turn per write, for
+// output piped into another process
+type textSink struct {
+	w      io.Writer
+	stamps timestampFormat
+	topic  bool        // Whether the topic header has been written
+	meta   *DebateMeta // Metadata of the debate, once it has started
+	err    error
+}
+
+// Send writes completed and failed turns, leaving out streamed chunks so
+// that readers only ever see whole lines
+func (s *textSink) Send(event DebateEvent) {
+	if event.Type == eventMeta {
+		s.meta = event.Meta
+		return
+	}
+	if (event.Type != eventTurn && event.Type != eventError) || s.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	if !s.topic {
+		if s.err = ExportText(event.Topic, s.meta, nil, s.stamps, &b); s.err != nil {
+			return
+		}
+		s.topic = true
+	}
+
+	turn := Turn{DisplayName: event.Speaker, Content: event.Content, Timestamp: event.Timestamp, Error: event.Error}
+	if s.err = writeTextTurns([]Turn{turn}, s.stamps, &b); s.err != nil {
+		return
+	}
+	b.WriteString("\n")
+
+	_, s.err = io.WriteString(s.w, b.String())
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// TestConfigureOutput verifies escape codes are only dropped when stdout is
+// not a terminal
+func TestConfigureOutput(t *testing.T) {
+	saved := stdoutIsTerminal
+	t.Cleanup(func() {
+		stdoutIsTerminal = saved
+		lipgloss.SetColorProfile(termenv.Ascii)
+	})
+
+	tests := []struct {
+		name       string
+		isTerminal bool
+		wantPlain  bool
+	}{
+		{"terminal", true, false},
+		{"pipe", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdoutIsTerminal = func() bool { return tt.isTerminal }
+			lipgloss.SetColorProfile(termenv.TrueColor)
+
+			configureOutput(stdoutIsTerminal())
+			rendered := errorStyle.Render("failed")
+			if plain := rendered == "failed"; plain != tt.wantPlain {
+				t.Errorf("Expected plain output %v, got %q", tt.wantPlain, rendered)
+			}
+		})
+	}
+}
+
+// TestTextSink verifies piped debates are written as plain text, a whole
+// turn at a time
+func TestTextSink(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	var out strings.Builder
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+	}
+	m.sink = &textSink{w: &out}
+	if result := runHeadless(context.Background(), m, 2); result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+
+	got := out.String()
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("Expected no escape codes, got %q", got)
+	}
+	if strings.Count(got, "Debate Topic: Should homework be banned?") != 1 {
+		t.Errorf("Expected the topic header once, got:\n%s", got)
+	}
+	if !strings.Contains(got, "mistral:7b:\nok\n\n") || !strings.Contains(got, "gemma3:4b:\nok\n\n") {
+		t.Errorf("Expected both turns as whole lines, got:\n%s", got)
+	}
+}
+
+// TestPipeDebate_SavesOutputAndJudges verifies piped debates honour -output,
+// HTML included, and -judge rather than only writing to stdout
+func TestPipeDebate_SavesOutputAndJudges(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	var out strings.Builder
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+	}
+	m.sink = &textSink{w: &out}
+	output := filepath.Join(t.TempDir(), "debate.html")
+	path, err := pipeDebate(context.Background(), m, 2, &out, output, "llama3:8b")
+	if err != nil {
+		t.Fatalf("pipeDebate failed: %v", err)
+	}
+
+	if path != output {
+		t.Errorf("Expected the debate written to %s, got %q", output, path)
+	}
+	saved, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read the saved debate: %v", err)
+	}
+	if !strings.Contains(string(saved), "<html") || !strings.Contains(string(saved), "Should homework be banned?") {
+		t.Errorf("Expected an HTML page of the debate, got:\n%s", saved)
+	}
+
+	if len(requests) != 3 || requests[2].Model != "llama3:8b" {
+		t.Fatalf("Expected two turns and a judge request, got %d requests", len(requests))
+	}
+	if !strings.HasSuffix(out.String(), "Verdict (llama3:8b): undecided\n") {
+		t.Errorf("Expected the verdict after the debate, got:\n%s", out.String())
+	}
+}
@@ -3,55 +3,283 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
+// Positions a model can be assigned for its first turn
+const (
+	PositionPro = "pro" // Argues in favor of the topic
+	PositionCon = "con" // Argues against the topic
+)
+
+// PromptOptions holds optional additions to the debate prompt
+type PromptOptions struct {
+	// Examples are sample turns showing good debate style
+	Examples []Turn
+
+	// Position is the side the current model argues, PositionPro or
+	// PositionCon. When empty, positions follow speaking order.
+	Position string
+
+	// Phases is the schedule of a structured debate, and TurnIndex the
+	// zero-based index of the turn being generated. Without phases the
+	// debate is unstructured.
+	Phases    []DebatePhase
+	TurnIndex int
+
+	// Date, when set, is stated at the top of the prompt so models can frame
+	// their arguments in the present
+	Date time.Time
+
+	// ResponsePrefix, when set, is text every turn must start with, such as
+	// "CLAIM:". The prompt ends with it so the model continues from there.
+	ResponsePrefix string
+
+	// Language is the catalog code of the language instructions are written
+	// in. When empty, prompts are in English.
+	Language string
+
+	// Style is the debate style preset setting the tone of each turn, such
+	// as "formal". When empty or neutral, no style instructions are added.
+	Style string
+
+	// ReinforceStance restates Position on every turn rather than only the
+	// model's first, to keep the model from drifting off its side
+	ReinforceStance bool
+
+	// CompactHistory sends the previous discussion as CompactHistory
+	// formats it, to spend fewer tokens on it
+	CompactHistory bool
+
+	// LengthLimit, when set, is the word limit the model ran over last turn
+	// and is reminded of
+	LengthLimit int
+
+	// Rebut, when set, is the name of the opponent whose last point the
+	// model ignored and is now asked to answer directly
+	Rebut string
+}
+
 // BuildDebatePrompt constructs a debate prompt with full context for a model.
 // It includes the debate topic, conversation history, and instructions for the model
 // to engage in debate. For the first turn, it assigns initial positions.
 func BuildDebatePrompt(topic string, history []Turn, currentModel string, isFirstTurn bool) string {
+	return BuildDebatePromptWithOptions(topic, history, currentModel, isFirstTurn, PromptOptions{})
+}
+
+// BuildDebatePromptWithOptions constructs a debate prompt like BuildDebatePrompt,
+// applying any optional additions from opts.
+func BuildDebatePromptWithOptions(topic string, history []Turn, currentModel string, isFirstTurn bool, opts PromptOptions) string {
+	return buildDebatePrompt(topic, history, history, currentModel, isFirstTurn, opts, true)
+}
+
+// buildDebatePrompt builds a debate prompt whose "Previous discussion"
+// section holds shown, which may be less than the history deciding whether
+// the turn opens the debate. The closing instruction is added only when
+// closing is set; chat mode sends it as a message of its own.
+func buildDebatePrompt(topic string, history, shown []Turn, currentModel string, isFirstTurn bool, opts PromptOptions, closing bool) string {
 	var prompt strings.Builder
+	msgs := messagesFor(opts.Language)
+
+	// State the current date if requested
+	writeDate(&prompt, msgs, opts.Date)
 
 	// Add debate context
-	prompt.WriteString(fmt.Sprintf("You are participating in a debate on the topic: \"%s\"\n\n", topic))
-	prompt.WriteString(fmt.Sprintf("You are %s. Your role is to present arguments and respond to your opponent's points.\n\n", currentModel))
+	prompt.WriteString(fmt.Sprintf(msgs.Topic+"\n\n", topic))
+	prompt.WriteString(fmt.Sprintf(msgs.Role+"\n\n", currentModel))
+
+	// A debate whose history holds only theses has not been opened yet
+	opening := !hasArgument(history)
 
 	// For the first turn, assign positions
-	if isFirstTurn {
+	if isFirstTurn && opts.Position != "" {
+		// An explicit position takes precedence over speaking order
+		if opts.Position == PositionPro {
+			prompt.WriteString(msgs.AssignedPro + "\n")
+		} else {
+			prompt.WriteString(msgs.AssignedCon + "\n")
+		}
+		if opening {
+			prompt.WriteString(msgs.AssignedOpening + "\n\n")
+		} else {
+			prompt.WriteString(msgs.AssignedResponse + "\n\n")
+		}
+	} else if isFirstTurn {
 		// Determine if this is model1 or model2 based on position in debate
 		// Model1 (first to speak) takes the "pro" position
 		// Model2 takes the "con" position
-		if len(history) == 0 {
-			prompt.WriteString("You will be presenting the opening argument. Take a clear position on this topic and present your initial arguments.\n\n")
+		if opening {
+			prompt.WriteString(msgs.DefaultOpening + "\n\n")
+		} else {
+			prompt.WriteString(msgs.DefaultResponse + "\n\n")
+		}
+	} else if opts.ReinforceStance && opts.Position != "" {
+		// Remind the model of its side on later turns
+		if opts.Position == PositionPro {
+			prompt.WriteString(msgs.AssignedPro + "\n\n")
 		} else {
-			prompt.WriteString("You will be responding to the opening argument. Take an opposing or alternative perspective and present your counterarguments.\n\n")
+			prompt.WriteString(msgs.AssignedCon + "\n\n")
 		}
 	}
 
+	// Add few-shot examples before the real history
+	if len(opts.Examples) > 0 {
+		prompt.WriteString(formatExamples(opts.Examples, msgs))
+		prompt.WriteString("\n")
+	}
+
 	// Add conversation history if it exists
-	if len(history) > 0 {
-		prompt.WriteString("Previous discussion:\n")
-		prompt.WriteString(FormatHistory(history))
+	if len(shown) > 0 {
+		prompt.WriteString(msgs.PreviousDiscussion + "\n")
+		prompt.WriteString(opts.formatHistory(shown))
+		prompt.WriteString("\n")
+	}
+
+	// Add phase-specific instructions for structured debates
+	if phase, ok := phaseForTurn(opts.Phases, opts.TurnIndex); ok {
+		prompt.WriteString(msgs.phaseInstruction(phase))
+		prompt.WriteString("\n")
+	}
+
+	// Set the tone of the response
+	if instruction := msgs.styleInstruction(opts.Style); instruction != "" {
+		prompt.WriteString(instruction)
 		prompt.WriteString("\n")
 	}
 
+	if !closing {
+		return prompt.String()
+	}
+
 	// Add instructions for the response
+	if !opening {
+		prompt.WriteString(msgs.NextArgument + "\n")
+	} else {
+		prompt.WriteString(msgs.OpeningArgument + "\n")
+	}
+	if opts.Rebut != "" {
+		prompt.WriteString(fmt.Sprintf(msgs.Rebut+"\n", opts.Rebut))
+	}
+	if opts.LengthLimit > 0 {
+		prompt.WriteString(fmt.Sprintf(msgs.Concise+"\n", opts.LengthLimit, opts.LengthLimit))
+	}
+
+	// Prime the response with the required prefix
+	if opts.ResponsePrefix != "" {
+		prompt.WriteString(fmt.Sprintf(msgs.ResponsePrefix+"\n\n", opts.ResponsePrefix))
+		prompt.WriteString(opts.ResponsePrefix)
+	}
+
+	return prompt.String()
+}
+
+// hasArgument reports whether history holds a turn other than a thesis
+func hasArgument(history []Turn) bool {
+	for _, turn := range history {
+		if !turn.Thesis {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildComparePrompt asks a model for its position on topic outside of a
+// debate, so that two models can answer the identical prompt
+func BuildComparePrompt(topic string, opts PromptOptions) string {
+	var prompt strings.Builder
+	msgs := messagesFor(opts.Language)
+
+	writeDate(&prompt, msgs, opts.Date)
+	prompt.WriteString(fmt.Sprintf(msgs.CompareTopic+"\n\n", topic))
+	prompt.WriteString(msgs.CompareInstruction + "\n")
+
+	return prompt.String()
+}
+
+// BuildContinuePrompt asks a model to elaborate on its most recent turn,
+// which is the last entry in history
+func BuildContinuePrompt(topic string, history []Turn, currentModel string) string {
+	return BuildContinuePromptWithOptions(topic, history, currentModel, PromptOptions{})
+}
+
+// BuildContinuePromptWithOptions constructs a continuation prompt like
+// BuildContinuePrompt in the language from opts
+func BuildContinuePromptWithOptions(topic string, history []Turn, currentModel string, opts PromptOptions) string {
+	var prompt strings.Builder
+	msgs := messagesFor(opts.Language)
+
+	prompt.WriteString(fmt.Sprintf(msgs.Topic+"\n\n", topic))
+	prompt.WriteString(fmt.Sprintf(msgs.ContinueRole+"\n\n", currentModel))
+
+	if len(history) > 0 {
+		prompt.WriteString(msgs.PreviousDiscussion + "\n")
+		prompt.WriteString(opts.formatHistory(history))
+		prompt.WriteString("\n\n")
+	}
+
+	prompt.WriteString(msgs.Continue + "\n")
+
+	return prompt.String()
+}
+
+// BuildModeratorPrompt asks a neutral moderator for a short interjection
+// that steers the debate toward ground the debaters have not yet covered
+func BuildModeratorPrompt(topic string, history []Turn) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("You are the moderator of a debate on the topic: \"%s\"\n\n", topic))
+
 	if len(history) > 0 {
-		prompt.WriteString("Provide your next argument or response. Be thoughtful, specific, and engage directly with the previous points made.\n")
+		prompt.WriteString("Previous discussion:\n")
+		prompt.WriteString(FormatHistory(history))
+		prompt.WriteString("\n\n")
+	}
+
+	prompt.WriteString("In one or two sentences, steer the debate: point out an angle neither side has addressed yet, or ask both debaters a pointed question. Stay neutral and do not argue for either side.\n")
+
+	return prompt.String()
+}
+
+// BuildThesisPrompt asks a model for the one-sentence thesis it will defend,
+// before its first argument. position is PositionPro or PositionCon. The
+// opponent's turns so far are included so a thesis can answer them.
+func BuildThesisPrompt(topic string, history []Turn, currentModel, position string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("You are about to debate the topic: \"%s\"\n\n", topic))
+	prompt.WriteString(fmt.Sprintf("You are %s. ", currentModel))
+	if position == PositionPro {
+		prompt.WriteString("You will argue in favor of the topic.\n\n")
 	} else {
-		prompt.WriteString("Provide your opening argument. Be thoughtful, specific, and clearly state your position.\n")
+		prompt.WriteString("You will argue against the topic.\n\n")
+	}
+
+	if len(history) > 0 {
+		prompt.WriteString("Previous discussion:\n")
+		prompt.WriteString(FormatHistory(history))
+		prompt.WriteString("\n\n")
 	}
 
+	prompt.WriteString("Before you argue, state your thesis: the single central claim you will defend. Reply with exactly one sentence and nothing else.\n")
+
 	return prompt.String()
 }
 
+// writeDate states date at the top of a prompt when it is set
+func writeDate(prompt *strings.Builder, msgs promptMessages, date time.Time) {
+	if !date.IsZero() {
+		prompt.WriteString(fmt.Sprintf(msgs.Date+"\n\n", date.Format(msgs.DateFormat)))
+	}
+}
+
 // FormatHistory structures the conversation history for model consumption.
-// Each turn is formatted with the model name and content, making it clear
+// Each turn is formatted with the speaker's name and content, making it clear
 // which model made each statement.
 func FormatHistory(history []Turn) string {
 	var formatted strings.Builder
 
 	for i, turn := range history {
-		formatted.WriteString(fmt.Sprintf("%s", turn.Content))
+		formatted.WriteString(fmt.Sprintf("[%s]: %s", turn.Speaker(), turn.Content))
 
 		// Add newline between turns, but not after the last one
 		if i < len(history)-1 {
@@ -61,3 +289,63 @@ func FormatHistory(history []Turn) string {
 
 	return formatted.String()
 }
+
+// CompactHistory structures the conversation history for model consumption
+// like FormatHistory, in fewer characters: each turn takes a single line,
+// with the runs of whitespace and paragraph breaks in its content collapsed
+// to single spaces.
+func CompactHistory(history []Turn) string {
+	var formatted strings.Builder
+
+	for i, turn := range history {
+		formatted.WriteString(fmt.Sprintf("[%s]: %s", turn.Speaker(), strings.Join(strings.Fields(turn.Content), " ")))
+		if i < len(history)-1 {
+			formatted.WriteString("\n")
+		}
+	}
+
+	return formatted.String()
+}
+
+// formatHistory formats history for a prompt built with opts
+func (opts PromptOptions) formatHistory(history []Turn) string {
+	if opts.CompactHistory {
+		return CompactHistory(history)
+	}
+	return FormatHistory(history)
+}
+
+// FormatExamples formats sample turns as a clearly separated section so that
+// models do not mistake them for the real debate history.
+func FormatExamples(examples []Turn) string {
+	return formatExamples(examples, messagesFor(defaultLanguage))
+}
+
+// formatExamples formats sample turns like FormatExamples, with the section
+// labels in the language of msgs
+func formatExamples(examples []Turn, msgs promptMessages) string {
+	var formatted strings.Builder
+
+	formatted.WriteString(msgs.ExamplesHeader + "\n")
+	formatted.WriteString("---\n")
+	for _, example := range examples {
+		formatted.WriteString(fmt.Sprintf("%s: %s\n", example.Speaker(), example.Content))
+	}
+	formatted.WriteString("---\n")
+	formatted.WriteString(msgs.ExamplesFooter + "\n")
+
+	return formatted.String()
+}
+
+// TrimHistoryToFit drops the oldest turns until the formatted history is at
+// most maxChars long. The most recent turn is always kept so the model can
+// respond to it. A maxChars of zero or less disables trimming.
+func TrimHistoryToFit(history []Turn, maxChars int) []Turn {
+	if maxChars <= 0 {
+		return history
+	}
+	for len(history) > 1 && len(FormatHistory(history)) > maxChars {
+		history = history[1:]
+	}
+	return history
+}
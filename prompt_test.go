@@ -1,6 +1,7 @@
 package main
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -153,3 +154,314 @@ func TestFormatHistory_MultipleTurns(t *testing.T) {
 		t.Errorf("Multiple turns should be separated by double newlines")
 	}
 }
+
+func TestCompactHistory(t *testing.T) {
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: "First argument.\n\nIt has   two paragraphs.", Timestamp: time.Now()},
+		{ModelName: "gemma3:4b", DisplayName: "Gemma", Content: "  Counter argument.\n", Timestamp: time.Now()},
+		{ModelName: "mistral:7b", Content: "Rebuttal.", Timestamp: time.Now()},
+	}
+
+	compact := CompactHistory(history)
+	want := "[mistral:7b]: First argument. It has two paragraphs.\n[Gemma]: Counter argument.\n[mistral:7b]: Rebuttal."
+	if compact != want {
+		t.Errorf("Expected compact history:\n%s\ngot:\n%s", want, compact)
+	}
+	if len(compact) >= len(FormatHistory(history)) {
+		t.Errorf("Expected compact history shorter than %d characters, got %d", len(FormatHistory(history)), len(compact))
+	}
+
+	// Every word of every turn survives, attributed to its speaker
+	for _, turn := range history {
+		if !strings.Contains(compact, "["+turn.Speaker()+"]: ") {
+			t.Errorf("Expected %s attributed, got:\n%s", turn.Speaker(), compact)
+		}
+		for _, word := range strings.Fields(turn.Content) {
+			if !strings.Contains(compact, word) {
+				t.Errorf("Expected %q kept, got:\n%s", word, compact)
+			}
+		}
+	}
+}
+
+func TestBuildDebatePrompt_CompactHistory(t *testing.T) {
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: "First argument.\n\nSecond paragraph."},
+		{ModelName: "gemma3:4b", Content: "Counter argument."},
+	}
+
+	prompt := BuildDebatePromptWithOptions("Should we tax sugar?", history, "mistral:7b", false, PromptOptions{CompactHistory: true})
+	if !strings.Contains(prompt, CompactHistory(history)) {
+		t.Errorf("Expected the compact history in the prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, FormatHistory(history)) {
+		t.Errorf("Expected no readable history in the prompt, got:\n%s", prompt)
+	}
+
+	prompt = BuildContinuePromptWithOptions("Should we tax sugar?", history, "gemma3:4b", PromptOptions{CompactHistory: true})
+	if !strings.Contains(prompt, CompactHistory(history)) {
+		t.Errorf("Expected the compact history in the continuation prompt, got:\n%s", prompt)
+	}
+}
+
+func TestBuildDebatePrompt_WithExamples(t *testing.T) {
+	examples, err := ParseExamples(strings.NewReader(
+		"Pro: Cities should ban cars downtown.\nIt cuts pollution.\n\nCon: You said banning cars cuts pollution, but trucks remain.\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse examples: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 example turns, got %d", len(examples))
+	}
+	if examples[0].Content != "Cities should ban cars downtown.\nIt cuts pollution." {
+		t.Errorf("Expected continuation lines to join the turn, got %q", examples[0].Content)
+	}
+
+	history := []Turn{{ModelName: "mistral:7b", Content: "Real argument.", Timestamp: time.Now()}}
+	prompt := BuildDebatePromptWithOptions("Should we tax sugar?", history, "gemma3:4b", false, PromptOptions{Examples: examples})
+
+	if !strings.Contains(prompt, "Here's an example of good debate style:") {
+		t.Errorf("Prompt should introduce the examples section")
+	}
+	if !strings.Contains(prompt, "Con: You said banning cars cuts pollution") {
+		t.Errorf("Prompt should contain example content")
+	}
+	if strings.Contains(prompt, "[Pro]:") {
+		t.Errorf("Examples should not be formatted like real history")
+	}
+	if strings.Index(prompt, "Here's an example") > strings.Index(prompt, "Previous discussion:") {
+		t.Errorf("Examples should appear before the real history")
+	}
+}
+
+func TestBuildDebatePrompt_NoExamples(t *testing.T) {
+	prompt := BuildDebatePrompt("Should we tax sugar?", nil, "gemma3:4b", true)
+	if strings.Contains(prompt, "example of good debate style") {
+		t.Errorf("Prompt should not mention examples when none are provided")
+	}
+}
+
+func TestBuildDebatePrompt_InjectDate(t *testing.T) {
+	date := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+	prompt := BuildDebatePromptWithOptions("Should we tax sugar?", nil, "gemma3:4b", true, PromptOptions{Date: date})
+
+	if !strings.HasPrefix(prompt, "Today's date is March 15, 2024.") {
+		t.Errorf("Prompt should start with the date, got %q", prompt)
+	}
+
+	prompt = BuildDebatePrompt("Should we tax sugar?", nil, "gemma3:4b", true)
+	if strings.Contains(prompt, "Today's date") {
+		t.Errorf("Prompt should not mention the date unless enabled")
+	}
+}
+
+// TestBuildDebatePrompt_Language verifies that a non-English language
+// translates the instructions but leaves the topic and turns untouched
+func TestBuildDebatePrompt_Language(t *testing.T) {
+	history := []Turn{{ModelName: "mistral:7b", Content: "Tabs respect user preferences."}}
+	opts := PromptOptions{Language: "pl"}
+	opts.Phases, _ = ParsePhaseSchedule("opening")
+
+	prompt := BuildDebatePromptWithOptions("Tabs or spaces?", history, "gemma3:4b", true, opts)
+	for _, want := range []string{
+		"Bierzesz udział w debacie na temat: \"Tabs or spaces?\"",
+		"Jesteś gemma3:4b.",
+		"Dotychczasowa dyskusja:\n[mistral:7b]: Tabs respect user preferences.",
+		"To jest wystąpienie otwierające",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected Polish prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+	for _, english := range []string{"You are participating", "Previous discussion:", "This is the opening statement"} {
+		if strings.Contains(prompt, english) {
+			t.Errorf("Expected no English instruction %q in a Polish prompt", english)
+		}
+	}
+
+	// English stays the default
+	opts.Language = ""
+	if english := BuildDebatePromptWithOptions("Tabs or spaces?", history, "gemma3:4b", true, opts); !strings.Contains(english, "Previous discussion:") {
+		t.Errorf("Expected an English prompt without a language")
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	if code, err := ParseLanguage(" DE "); err != nil || code != "de" {
+		t.Errorf("Expected \"de\", got %q (%v)", code, err)
+	}
+	if code, err := ParseLanguage(""); err != nil || code != defaultLanguage {
+		t.Errorf("Expected the default language, got %q (%v)", code, err)
+	}
+	if _, err := ParseLanguage("xx"); err == nil || !strings.Contains(err.Error(), "en, es, fr, pl") {
+		t.Errorf("Expected an error listing supported languages, got %v", err)
+	}
+}
+
+// TestPromptCatalog_Complete verifies every language defines every message
+// and translates every built-in phase
+func TestPromptCatalog_Complete(t *testing.T) {
+	for code, msgs := range promptCatalog {
+		fields := reflect.ValueOf(msgs)
+		for i := 0; i < fields.NumField(); i++ {
+			if field := fields.Field(i); field.Kind() == reflect.String && field.String() == "" {
+				t.Errorf("%s: %s is empty", code, fields.Type().Field(i).Name)
+			}
+		}
+		if code == defaultLanguage {
+			continue
+		}
+		for name := range builtinPhases {
+			if msgs.Phases[name] == "" {
+				t.Errorf("%s: phase %s is not translated", code, name)
+			}
+		}
+		for name := range debateStyles {
+			if msgs.Styles[name] == "" {
+				t.Errorf("%s: style %s is not translated", code, name)
+			}
+		}
+	}
+}
+
+func TestParseExamples_MissingSpeaker(t *testing.T) {
+	if _, err := ParseExamples(strings.NewReader("no speaker here\n")); err == nil {
+		t.Errorf("Expected error for a turn without a speaker")
+	}
+}
+
+func TestTrimHistoryToFit(t *testing.T) {
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: strings.Repeat("a", 100)},
+		{ModelName: "gemma3:4b", Content: strings.Repeat("b", 100)},
+		{ModelName: "mistral:7b", Content: strings.Repeat("c", 100)},
+	}
+
+	trimmed := TrimHistoryToFit(history, 250)
+	if len(trimmed) != 2 || trimmed[0].ModelName != "gemma3:4b" {
+		t.Errorf("Expected the oldest turn to be dropped, got %d turns", len(trimmed))
+	}
+
+	if trimmed := TrimHistoryToFit(history, 10); len(trimmed) != 1 || trimmed[0].Content != history[2].Content {
+		t.Errorf("Expected the latest turn to always be kept")
+	}
+
+	if trimmed := TrimHistoryToFit(history, 0); len(trimmed) != 3 {
+		t.Errorf("Expected no trimming when the budget is zero")
+	}
+}
+
+func TestBuildDebatePrompt_ResponsePrefix(t *testing.T) {
+	opts := PromptOptions{ResponsePrefix: "CLAIM:"}
+	prompt := BuildDebatePromptWithOptions("Tabs or spaces?", nil, "mistral:7b", true, opts)
+
+	if !strings.HasSuffix(prompt, "Begin your response with \"CLAIM:\".\n\nCLAIM:") {
+		t.Errorf("Expected the prompt to end primed with the prefix, got:\n%s", prompt)
+	}
+	if strings.Contains(BuildDebatePrompt("Tabs or spaces?", nil, "mistral:7b", true), "Begin your response") {
+		t.Errorf("Expected no prefix instruction unless set")
+	}
+}
+
+// TestBuildDebatePrompt_ReinforceStance verifies the side is restated on
+// later turns only when asked
+func TestBuildDebatePrompt_ReinforceStance(t *testing.T) {
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: "Homework builds discipline."},
+		{ModelName: "gemma3:4b", Content: "It mostly builds stress."},
+	}
+	pro := promptCatalog["en"].AssignedPro
+
+	opts := PromptOptions{Position: PositionPro}
+	if prompt := BuildDebatePromptWithOptions("Should homework be banned?", history, "mistral:7b", false, opts); strings.Contains(prompt, pro) {
+		t.Errorf("Expected no side on a later turn by default, got:\n%s", prompt)
+	}
+
+	opts.ReinforceStance = true
+	prompt := BuildDebatePromptWithOptions("Should homework be banned?", history, "mistral:7b", false, opts)
+	if !strings.Contains(prompt, pro) {
+		t.Errorf("Expected the side restated on a later turn, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "opening argument") {
+		t.Errorf("Expected no opening framing on a later turn, got:\n%s", prompt)
+	}
+}
+
+// TestReinforceStance_EveryTurn verifies each model hears its own side on
+// every turn, following speaking order without -pro
+func TestReinforceStance_EveryTurn(t *testing.T) {
+	m := &debateModel{
+		model1Name:      "phi3:mini",
+		model2Name:      "gemma3:4b",
+		topic:           "Is remote work better?",
+		reinforceStance: true,
+		history: []Turn{
+			{ModelName: "phi3:mini", Content: "Remote work wins."},
+			{ModelName: "gemma3:4b", Content: "Offices win."},
+			{ModelName: "phi3:mini", Content: "Commutes waste time."},
+		},
+	}
+	msgs := promptCatalog["en"]
+
+	if prompt := m.promptFor("phi3:mini", 4); !strings.Contains(prompt, msgs.AssignedPro) {
+		t.Errorf("Expected the opener reminded it argues in favor, got:\n%s", prompt)
+	}
+	if prompt := m.promptFor("gemma3:4b", 3); !strings.Contains(prompt, msgs.AssignedCon) {
+		t.Errorf("Expected the responder reminded it argues against, got:\n%s", prompt)
+	}
+
+	m.reinforceStance = false
+	if prompt := m.promptFor("gemma3:4b", 3); strings.Contains(prompt, msgs.AssignedCon) {
+		t.Errorf("Expected no reminder without -reinforce-stance, got:\n%s", prompt)
+	}
+}
+
+// TestBuildDebatePrompt_Style verifies each style preset adds its own
+// instructions and the neutral default adds none
+func TestBuildDebatePrompt_Style(t *testing.T) {
+	topic := "Should homework be banned?"
+	neutral := BuildDebatePromptWithOptions(topic, nil, "mistral:7b", true, PromptOptions{})
+
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{"formal", "support each one with evidence"},
+		{"casual", "conversational tone"},
+		{"socratic", "asking your opponent probing questions"},
+	}
+	for _, tt := range tests {
+		prompt := BuildDebatePromptWithOptions(topic, nil, "mistral:7b", true, PromptOptions{Style: tt.style})
+		if !strings.Contains(prompt, tt.want) {
+			t.Errorf("%s: expected %q in prompt:\n%s", tt.style, tt.want, prompt)
+		}
+		for _, other := range tests {
+			if other.style != tt.style && strings.Contains(prompt, other.want) {
+				t.Errorf("%s: prompt also has the %s instructions", tt.style, other.style)
+			}
+		}
+		if strings.Contains(neutral, tt.want) {
+			t.Errorf("Expected the neutral prompt to leave out the %s instructions", tt.style)
+		}
+	}
+
+	if got := BuildDebatePromptWithOptions(topic, nil, "mistral:7b", true, PromptOptions{Style: styleNeutral}); got != neutral {
+		t.Errorf("Expected the neutral style to match the default prompt, got:\n%s", got)
+	}
+
+	polish := BuildDebatePromptWithOptions(topic, nil, "mistral:7b", true, PromptOptions{Style: "socratic", Language: "pl"})
+	if !strings.Contains(polish, "metodą sokratejską") {
+		t.Errorf("Expected the style instructions in the prompt's language, got:\n%s", polish)
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for input, want := range map[string]string{"": styleNeutral, "neutral": styleNeutral, " Formal ": "formal", "socratic": "socratic"} {
+		if got, err := ParseStyle(input); err != nil || got != want {
+			t.Errorf("ParseStyle(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseStyle("poetic"); err == nil {
+		t.Error("Expected an error for an unknown style")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited is returned when Ollama, or a hosted endpoint compatible
+// with it, still answers 429 Too Many Requests after one retry
+var ErrRateLimited = errors.New("rate limited")
+
+const (
+	// defaultRetryAfter is how long to wait after a 429 response without a
+	// usable Retry-After header
+	defaultRetryAfter = 5 * time.Second
+
+	// maxRetryAfter caps the wait so a misconfigured server cannot stall a
+	// debate indefinitely
+	maxRetryAfter = 2 * time.Minute
+)
+
+// parseRetryAfter returns how long a Retry-After header asks clients to
+// wait, given either as seconds or as an HTTP date. It reports false when
+// the header is missing or malformed.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryDelay returns how long to wait before retrying a 429 response
+func retryDelay(resp *http.Response) time.Duration {
+	wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return defaultRetryAfter
+	}
+	return min(wait, maxRetryAfter)
+}
+
+// formatRetryDelay renders a wait as whole seconds, rounded up, for the
+// "rate limited" notice
+func formatRetryDelay(wait time.Duration) string {
+	return fmt.Sprintf("%ds", int(math.Ceil(wait.Seconds())))
+}
+
+// SetRateLimitHandler sets a function called with the wait whenever a
+// request is rate limited and is about to be retried. It may be called from
+// any goroutine.
+func (c *OllamaClient) SetRateLimitHandler(handler func(wait time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRateLimit = handler
+}
+
+// do sends req. When the server answers 429 Too Many Requests, it waits for
+// the time given by Retry-After and retries once; a second 429 is returned
+// to the caller.
+func (c *OllamaClient) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	wait := retryDelay(resp)
+	resp.Body.Close()
+
+	c.mu.RLock()
+	handler := c.onRateLimit
+	c.mu.RUnlock()
+	if handler != nil {
+		handler(wait)
+	}
+
+	// Wait out the limit unless the request is cancelled first
+	timer := time.NewTimer(wait)
+	select {
+	case <-timer.C:
+	case <-req.Context().Done():
+		timer.Stop()
+		return nil, req.Context().Err()
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return c.httpClient.Do(retry)
+}
+
+// statusError describes a non-OK response that carries no error body
+func statusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: Ollama API returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	return fmt.Errorf("Ollama API returned status %d", resp.StatusCode)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"3", 3 * time.Second, true},
+		{" 0 ", 0, true},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestGenerateResponse_RetriesAfterRateLimit tests that a 429 response is
+// retried once after its Retry-After, with the same request body
+func TestGenerateResponse_RetriesAfterRateLimit(t *testing.T) {
+	var bodies []GenerateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		bodies = append(bodies, req)
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "after the wait", Done: true})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	var notices []time.Duration
+	client.SetRateLimitHandler(func(wait time.Duration) { notices = append(notices, wait) })
+
+	start := time.Now()
+	responseChan, errorChan := client.GenerateResponse(context.Background(), "mistral:7b", "test prompt")
+	var text strings.Builder
+	for chunk := range responseChan {
+		text.WriteString(chunk)
+	}
+	if err := <-errorChan; err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After, only took %v", elapsed)
+	}
+	if text.String() != "after the wait" {
+		t.Errorf("Expected the retried response, got %q", text.String())
+	}
+	if len(bodies) != 2 || bodies[1].Prompt != "test prompt" {
+		t.Errorf("Expected the retry to resend the prompt, got %+v", bodies)
+	}
+	if len(notices) != 1 || notices[0] != time.Second {
+		t.Errorf("Expected one 1s rate limit notice, got %v", notices)
+	}
+	if got := formatRetryDelay(notices[0]); got != "1s" {
+		t.Errorf("Expected the notice to read 1s, got %s", got)
+	}
+}
+
+// TestListModels_RateLimitedTwice tests that a second 429 fails with
+// ErrRateLimited instead of retrying again
+func TestListModels_RateLimitedTwice(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	_, err := client.ListModels()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected exactly one retry, got %d requests", requests)
+	}
+}
+
+// TestRateLimit_CancelDuringWait tests that cancelling a generation stops
+// the wait for Retry-After
+func TestRateLimit_CancelDuringWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	client.SetRateLimitHandler(func(time.Duration) { cancel() })
+
+	start := time.Now()
+	responseChan, errorChan := client.GenerateResponse(ctx, "mistral:7b", "test")
+	for range responseChan {
+	}
+	if err := <-errorChan; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected cancellation to end the wait, took %v", elapsed)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// turnRatio sets how many consecutive turns each model takes per round. The
+// zero value means strict alternation.
+type turnRatio struct {
+	model1 int
+	model2 int
+}
+
+// parseTurnRatio parses a ratio such as "2:1"
+func parseTurnRatio(s string) (turnRatio, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 {
+		return turnRatio{}, fmt.Errorf("invalid turn ratio '%s' (expected e.g. 2:1)", s)
+	}
+
+	var counts [2]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 {
+			return turnRatio{}, fmt.Errorf("invalid turn ratio '%s' (each side needs at least one turn)", s)
+		}
+		counts[i] = n
+	}
+
+	return turnRatio{model1: counts[0], model2: counts[1]}, nil
+}
+
+// turns returns how many consecutive turns a model takes: turn is 0 for
+// model1 and 1 for model2
+func (r turnRatio) turns(turn int) int {
+	n := r.model1
+	if turn != 0 {
+		n = r.model2
+	}
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+package main
+
+import "testing"
+
+func TestParseTurnRatio(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    turnRatio
+		wantErr bool
+	}{
+		{"1:1", turnRatio{1, 1}, false},
+		{"2:1", turnRatio{2, 1}, false},
+		{" 1 : 3 ", turnRatio{1, 3}, false},
+		{"2", turnRatio{}, true},
+		{"0:1", turnRatio{}, true},
+		{"a:b", turnRatio{}, true},
+		{"1:2:3", turnRatio{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTurnRatio(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTurnRatio(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("parseTurnRatio(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestSwitchTurn_FollowsRatio verifies the rotation gives each model its
+// share of turns over many rounds
+func TestSwitchTurn_FollowsRatio(t *testing.T) {
+	tests := []struct {
+		ratio turnRatio
+		want  string // Speakers of the first turns, 0 for model1 and 1 for model2
+	}{
+		{turnRatio{}, "010101"},
+		{turnRatio{1, 1}, "010101"},
+		{turnRatio{2, 1}, "001001"},
+		{turnRatio{1, 3}, "01110111"},
+	}
+
+	for _, tt := range tests {
+		m := &debateModel{turnRatio: tt.ratio}
+
+		var got []byte
+		counts := [2]int{}
+		for i := 0; i < 300; i++ {
+			if i < len(tt.want) {
+				got = append(got, byte('0'+m.currentTurn))
+			}
+			counts[m.currentTurn]++
+			m.switchTurn()
+		}
+
+		if string(got) != tt.want {
+			t.Errorf("Ratio %+v: expected rotation %s, got %s", tt.ratio, tt.want, got)
+		}
+		if counts[0]*tt.ratio.turns(1) != counts[1]*tt.ratio.turns(0) {
+			t.Errorf("Ratio %+v: expected turn counts in proportion, got %d:%d", tt.ratio, counts[0], counts[1])
+		}
+	}
+}
+
+// TestTurnRatio_ConsecutiveTurnsStaySeparate verifies a model speaking twice
+// in a row gets two turns rather than one merged turn
+func TestTurnRatio_ConsecutiveTurnsStaySeparate(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Is chess a sport?",
+		state:        stateDebating,
+		turnRatio:    turnRatio{2, 1},
+		history:      []Turn{{ModelName: "mistral:7b", Content: "Yes."}},
+	}
+
+	// Completing model1's first turn hands it a second one
+	m.isGenerating = true
+	m.startGeneration(m.model1Name, "prompt")
+	m.turnStarted = true
+	_, cmd := m.Update(responseCompleteMsg{responseChan: m.stream})
+	if m.getNextModel() != "mistral:7b" {
+		t.Fatalf("Expected mistral:7b to speak again, got %s", m.getNextModel())
+	}
+	cmd()
+
+	m.Update(responseChunkMsg{chunk: "Also, it is competitive.", responseChan: m.stream})
+	if len(m.history) != 2 || m.history[1].Content != "Also, it is competitive." {
+		t.Errorf("Expected a second, separate turn, got %+v", m.history)
+	}
+}
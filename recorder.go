@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// recordingGenerator passes requests to next and records each response as
+// it streams, for -record to save as a fixture. Responses are kept in the
+// order they were requested, which is the order -fixture hands them out.
+// It is safe for concurrent use.
+type recordingGenerator struct {
+	next Generator
+	now  func() time.Time
+
+	mu        sync.Mutex
+	responses []*recordedResponse
+}
+
+// recordedResponse is a response being recorded. Only complete ones are
+// saved, since a fixture cannot replay a failure.
+type recordedResponse struct {
+	response FixtureResponse
+	complete bool
+}
+
+// newRecordingGenerator returns a recorder in front of next
+func newRecordingGenerator(next Generator) *recordingGenerator {
+	return &recordingGenerator{next: next, now: time.Now}
+}
+
+// GenerateResponseWithOptions streams next's response, recording each chunk
+// with how long after the previous one it arrived, the first timed from the
+// request
+func (g *recordingGenerator) GenerateResponseWithOptions(ctx context.Context, modelName, prompt string, overrides map[string]interface{}) (<-chan string, <-chan error) {
+	recorded := &recordedResponse{response: FixtureResponse{Model: modelName}}
+	g.mu.Lock()
+	g.responses = append(g.responses, recorded)
+	g.mu.Unlock()
+
+	last := g.now()
+	upstream, upstreamErrs := g.next.GenerateResponseWithOptions(ctx, modelName, prompt, overrides)
+	responseChan := make(chan string)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		for chunk := range upstream {
+			now := g.now()
+			g.mu.Lock()
+			recorded.response.Chunks = append(recorded.response.Chunks, FixtureChunk{Text: chunk, DelayMS: int(now.Sub(last).Milliseconds())})
+			g.mu.Unlock()
+			last = now
+
+			select {
+			case responseChan <- chunk:
+			case <-ctx.Done():
+				drainGeneration(upstream, upstreamErrs)
+				errorChan <- ctx.Err()
+				return
+			}
+		}
+		if err := <-upstreamErrs; err != nil {
+			errorChan <- err
+			return
+		}
+		g.mu.Lock()
+		recorded.complete = true
+		g.mu.Unlock()
+	}()
+	return responseChan, errorChan
+}
+
+// Fixture returns the complete responses recorded so far
+func (g *recordingGenerator) Fixture() *Fixture {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fixture := &Fixture{Responses: []FixtureResponse{}}
+	for _, recorded := range g.responses {
+		if recorded.complete {
+			fixture.Responses = append(fixture.Responses, recorded.response)
+		}
+	}
+	return fixture
+}
+
+// WriteFixture saves the complete responses recorded so far to path as a
+// fixture -fixture can play back, and returns how many there were
+func (g *recordingGenerator) WriteFixture(path string) (int, error) {
+	fixture := g.Fixture()
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode recording: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return 0, fmt.Errorf("failed to write recording: %w", err)
+	}
+	return len(fixture.Responses), nil
+}
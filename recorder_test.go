@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// steppingClock returns times 25ms apart
+func steppingClock() func() time.Time {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(25 * time.Millisecond)
+		return now
+	}
+}
+
+func TestRecordingGenerator_RecordsChunksWithDelays(t *testing.T) {
+	g := newRecordingGenerator(newFixtureGenerator(testFixture()))
+	g.now = steppingClock()
+
+	if _, err := collectResponse(context.Background(), g, "phi3:mini", "Argue.", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []FixtureResponse{{Model: "phi3:mini", Chunks: []FixtureChunk{
+		{Text: "Remote ", DelayMS: 25}, {Text: "work ", DelayMS: 25}, {Text: "wins.", DelayMS: 25},
+	}}}
+	if got := g.Fixture().Responses; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestRecordingGenerator_SkipsFailures(t *testing.T) {
+	g := newRecordingGenerator(&countingGenerator{text: "Partial", err: errors.New("connection reset")})
+	if _, err := collectResponse(context.Background(), g, "phi3:mini", "Argue.", nil); err == nil {
+		t.Fatal("Expected the failure to be passed on")
+	}
+	if got := g.Fixture().Responses; len(got) != 0 {
+		t.Errorf("Expected a failed response to be left out, got %+v", got)
+	}
+}
+
+// TestRecord_ReplaysIntoIdenticalTurns verifies a debate recorded with
+// -record plays back from the saved fixture into the same turns
+func TestRecord_ReplaysIntoIdenticalTurns(t *testing.T) {
+	run := func(g Generator) []Turn {
+		m := &debateModel{
+			model1Name: "phi3:mini",
+			model2Name: "gemma3:4b",
+			generator:  g,
+			topic:      "Is remote work better?",
+		}
+		result := runHeadless(context.Background(), m, 3)
+		if result.Err != nil {
+			t.Fatalf("Unexpected error: %v", result.Err)
+		}
+		return result.Turns
+	}
+
+	recorder := newRecordingGenerator(newFixtureGenerator(testFixture()))
+	live := run(recorder)
+
+	path := filepath.Join(t.TempDir(), "demo.json")
+	if n, err := recorder.WriteFixture(path); err != nil || n != 3 {
+		t.Fatalf("Expected 3 responses written, got %d (%v)", n, err)
+	}
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("Expected the recording to load as a fixture: %v", err)
+	}
+	replayed := run(newFixtureGenerator(fixture))
+
+	if len(replayed) != len(live) {
+		t.Fatalf("Expected %d turns, got %d", len(live), len(replayed))
+	}
+	for i := range live {
+		if replayed[i].ModelName != live[i].ModelName || replayed[i].Content != live[i].Content {
+			t.Errorf("Turn %d: expected %s %q, got %s %q", i, live[i].ModelName, live[i].Content, replayed[i].ModelName, replayed[i].Content)
+		}
+	}
+}
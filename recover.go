@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// restoreTerminalSequence leaves the alternate screen and shows the cursor
+const restoreTerminalSequence = "\x1b[?1049l\x1b[?25h"
+
+// runWithRecovery calls run, turning a panic into an error after restoring
+// the terminal so the panic is printed to a usable screen. restore, if
+// set, releases the program's hold on the terminal (e.g. raw mode) before
+// the escape sequences are written to w.
+func runWithRecovery(w io.Writer, restore func(), run func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if restore != nil {
+				restore()
+			}
+			fmt.Fprint(w, restoreTerminalSequence)
+			err = fmt.Errorf("panic: %v\n\n%s", r, debug.Stack())
+		}
+	}()
+
+	return run()
+}
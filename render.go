@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Render modes, chosen at startup (see renderModeFor)
+const (
+	renderStyled = "styled" // Colors and box-drawing borders
+	renderPlain  = "plain"  // No colors and ASCII borders, for terminals without color support
+)
+
+// renderMode is how the view is drawn. applyTheme builds its styles for it.
+var renderMode = renderStyled
+
+// asciiBorder draws boxes with characters every terminal can show
+var asciiBorder = lipgloss.Border{
+	Top:         "-",
+	Bottom:      "-",
+	Left:        "|",
+	Right:       "|",
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+}
+
+// renderModeFor returns the render mode suited to a terminal's color
+// profile: plain when it supports no color at all, which is also what is
+// detected when stdout is not a terminal or NO_COLOR is set
+func renderModeFor(profile termenv.Profile) string {
+	if profile == termenv.Ascii {
+		return renderPlain
+	}
+	return renderStyled
+}
+
+// configureRendering picks the render mode for the terminal's detected color
+// profile, forcing plain rendering and no color when noColor is set. It runs
+// before the theme is applied.
+func configureRendering(noColor bool) {
+	if noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	renderMode = renderModeFor(lipgloss.ColorProfile())
+}
+
+// plainStyles strips the colors from the styles applyTheme built and swaps
+// their borders for ASCII ones, keeping the layout
+func plainStyles() {
+	plain := func(style lipgloss.Style) lipgloss.Style {
+		style = style.UnsetForeground().UnsetBorderForeground()
+		if border, _, _, _, _ := style.GetBorder(); border != (lipgloss.Border{}) {
+			style = style.BorderStyle(asciiBorder)
+		}
+		return style
+	}
+
+	model1Style = plain(model1Style)
+	model1LabelStyle = plain(model1LabelStyle)
+	model2Style = plain(model2Style)
+	model2LabelStyle = plain(model2LabelStyle)
+	moderatorStyle = plain(moderatorStyle)
+	moderatorLabelStyle = plain(moderatorLabelStyle)
+	headerStyle = plain(headerStyle)
+	errorStyle = plain(errorStyle)
+	subtleStyle = plain(subtleStyle)
+	timestampStyle = plain(timestampStyle)
+	badgeStyle = plain(badgeStyle)
+	promptPaneStyle = plain(promptPaneStyle)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestRenderModeFor(t *testing.T) {
+	tests := []struct {
+		profile termenv.Profile
+		want    string
+	}{
+		{termenv.Ascii, renderPlain},
+		{termenv.ANSI, renderStyled},
+		{termenv.ANSI256, renderStyled},
+		{termenv.TrueColor, renderStyled},
+	}
+	for _, tt := range tests {
+		if got := renderModeFor(tt.profile); got != tt.want {
+			t.Errorf("renderModeFor(%v) = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
+
+// usePlainRendering switches to plain rendering for the rest of the test
+func usePlainRendering(t *testing.T) {
+	t.Helper()
+	renderMode = renderPlain
+	applyTheme(themes[0])
+	t.Cleanup(func() {
+		renderMode = renderStyled
+		applyTheme(themes[0])
+	})
+}
+
+func TestPlainRendering_Styles(t *testing.T) {
+	usePlainRendering(t)
+
+	if _, ok := model1Style.GetForeground().(lipgloss.NoColor); !ok {
+		t.Errorf("Expected no foreground color, got %v", model1Style.GetForeground())
+	}
+	if border, _, _, _, _ := model2Style.GetBorder(); border != asciiBorder {
+		t.Errorf("Expected an ASCII border, got %+v", border)
+	}
+
+	turn := Turn{ModelName: "mistral:7b", Content: "Plain words.", Thesis: true}
+	rendered := formatTurn(turn, true, 60, defaultMinContentWidth, "", timestampFormat{})
+	if !strings.Contains(rendered, "+----") || !strings.Contains(rendered, "| Plain words.") {
+		t.Errorf("Expected the turn boxed in ASCII, got:\n%s", rendered)
+	}
+	if strings.ContainsAny(rendered, "╭┃━│") {
+		t.Errorf("Expected no box-drawing characters, got:\n%s", rendered)
+	}
+}
+
+func TestPlainRendering_Timeline(t *testing.T) {
+	usePlainRendering(t)
+
+	m := &debateModel{model1Name: "a", model2Name: "b"}
+	history := []Turn{{ModelName: "a", Content: "xxxx"}, {ModelName: "b", Content: "xxxx"}}
+	timeline := m.renderTimeline(history, 8)
+	if !strings.HasPrefix(timeline, "####====\n") {
+		t.Errorf("Expected the bar drawn in characters, got:\n%s", timeline)
+	}
+}
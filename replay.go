@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultReplayDelay is the default pause between turns when replaying a
+// saved debate
+const defaultReplayDelay = 2 * time.Second
+
+// Bounds for the replay delay as +/- change the speed
+const (
+	minReplayDelay = 250 * time.Millisecond
+	maxReplayDelay = 30 * time.Second
+)
+
+// replayTickMsg is sent when the next turn of a replay is due. seq ties the
+// tick to the schedule it was made for, so that ticks from before a pause or
+// speed change are ignored.
+type replayTickMsg struct {
+	seq int
+}
+
+// replayModel plays back a saved debate, revealing one turn at a time as if
+// it were happening live
+type replayModel struct {
+	debate *debateModel // Supplies width settings and the timestamp format
+	topic  string
+	turns  []Turn
+	model1 string // Model tag of the first speaker, shown in model1's colors
+
+	shown  int           // Turns revealed so far
+	delay  time.Duration // Pause before the next turn is revealed
+	paused bool
+	seq    int // Schedule of the pending tick; bumped to cancel it
+
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+// newReplayModel prepares a replay of turns on topic, rendered with the
+// debate's width settings and revealed delay apart
+func newReplayModel(m *debateModel, topic string, turns []Turn, delay time.Duration) *replayModel {
+	rm := &replayModel{
+		debate: m,
+		topic:  topic,
+		turns:  turns,
+		delay:  delay,
+		width:  80,
+		height: 24,
+	}
+	for _, turn := range turns {
+		if !turn.Moderator {
+			rm.model1 = turn.ModelName
+			break
+		}
+	}
+	return rm
+}
+
+// Init schedules the first turn
+func (m *replayModel) Init() tea.Cmd {
+	m.viewport = viewport.New(m.width, viewportHeight(m.height, m.width, m.renderHeader(), m.renderFooter()))
+	return m.schedule()
+}
+
+// schedule cancels any pending tick and, unless the replay is paused or
+// finished, schedules the next turn after the current delay
+func (m *replayModel) schedule() tea.Cmd {
+	m.seq++
+	if m.paused || m.finished() {
+		return nil
+	}
+	seq := m.seq
+	return tea.Tick(m.delay, func(time.Time) tea.Msg {
+		return replayTickMsg{seq: seq}
+	})
+}
+
+// finished reports whether every turn has been revealed
+func (m *replayModel) finished() bool {
+	return m.shown >= len(m.turns)
+}
+
+// togglePause pauses or resumes the replay. Resuming a finished replay
+// starts it over.
+func (m *replayModel) togglePause() tea.Cmd {
+	if m.finished() {
+		m.shown = 0
+		m.paused = false
+		return m.schedule()
+	}
+	m.paused = !m.paused
+	return m.schedule()
+}
+
+// faster halves the delay between turns, down to minReplayDelay
+func (m *replayModel) faster() tea.Cmd {
+	m.delay = max(m.delay/2, minReplayDelay)
+	return m.schedule()
+}
+
+// slower doubles the delay between turns, up to maxReplayDelay
+func (m *replayModel) slower() tea.Cmd {
+	m.delay = min(m.delay*2, maxReplayDelay)
+	return m.schedule()
+}
+
+// Update handles playback keys, resizes and ticks
+func (m *replayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case " ":
+			cmd = m.togglePause()
+		case "+", "=":
+			cmd = m.faster()
+		case "-":
+			cmd = m.slower()
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = viewportHeight(msg.Height, msg.Width, m.renderHeader(), m.renderFooter())
+
+	case replayTickMsg:
+		if msg.seq != m.seq || m.paused || m.finished() {
+			break
+		}
+		m.shown++
+		cmd = m.schedule()
+	}
+
+	atBottom := m.viewport.AtBottom()
+	m.viewport.SetContent(m.renderTurns())
+	if _, ok := msg.(replayTickMsg); ok && atBottom {
+		// Follow new turns unless the reader has scrolled back
+		m.viewport.GotoBottom()
+	}
+	var scrollCmd tea.Cmd
+	m.viewport, scrollCmd = m.viewport.Update(msg)
+
+	return m, tea.Batch(cmd, scrollCmd)
+}
+
+// View renders the topic, the revealed turns and the playback controls
+func (m *replayModel) View() string {
+	var b strings.Builder
+
+	header, footer := m.renderHeader(), m.renderFooter()
+	m.viewport.Height = viewportHeight(m.height, m.width, header, footer)
+
+	b.WriteString(header)
+	b.WriteString("\n")
+	b.WriteString(m.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(footer)
+
+	return b.String()
+}
+
+// renderHeader renders the topic shown above the replay
+func (m *replayModel) renderHeader() string {
+	return headerStyle.Render(fmt.Sprintf("⏯️  Replaying: %s", m.topic))
+}
+
+// renderFooter renders the playback state and key help
+func (m *replayModel) renderFooter() string {
+	progress := fmt.Sprintf("Turn %d/%d", m.shown, len(m.turns))
+	switch {
+	case m.finished():
+		return subtleStyle.Render(fmt.Sprintf("✓ %s • space to replay again • ↑/↓ to scroll • 'q' to exit", progress))
+	case m.paused:
+		return subtleStyle.Render(fmt.Sprintf("⏸ %s • space to resume • +/- speed (%s per turn) • 'q' to exit", progress, m.delay))
+	}
+	return subtleStyle.Render(fmt.Sprintf("▶ %s • space to pause • +/- speed (%s per turn) • 'q' to exit", progress, m.delay))
+}
+
+// renderTurns renders the turns revealed so far
+func (m *replayModel) renderTurns() string {
+	var b strings.Builder
+	for i, turn := range m.turns[:m.shown] {
+		badge := ""
+		if turn.Flagged {
+			badge = "🚩 filtered"
+		}
+		b.WriteString(m.debate.formatTurn(turn, turn.ModelName == m.model1, m.width, badge))
+		b.WriteString("\n")
+		if i < m.shown-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestReplay returns a replay of three turns that has been initialized
+func newTestReplay() *replayModel {
+	turns := []Turn{
+		{ModelName: "phi3:mini", Content: "Opening"},
+		{ModelName: "gemma3:4b", Content: "Rebuttal"},
+		{ModelName: "phi3:mini", Content: "Closing"},
+	}
+	m := newReplayModel(&debateModel{minContentWidth: defaultMinContentWidth}, "Is remote work better?", turns, defaultReplayDelay)
+	m.Init()
+	return m
+}
+
+func sendReplayKey(m *replayModel, key tea.KeyMsg) tea.Cmd {
+	_, cmd := m.Update(key)
+	return cmd
+}
+
+func TestReplay_TickRevealsTurns(t *testing.T) {
+	m := newTestReplay()
+
+	m.Update(replayTickMsg{seq: m.seq})
+	if m.shown != 1 {
+		t.Fatalf("Expected one turn shown after a tick, got %d", m.shown)
+	}
+	if !strings.Contains(m.renderTurns(), "Opening") || strings.Contains(m.renderTurns(), "Rebuttal") {
+		t.Errorf("Expected only the first turn rendered, got %q", m.renderTurns())
+	}
+
+	m.Update(replayTickMsg{seq: m.seq})
+	m.Update(replayTickMsg{seq: m.seq})
+	if !m.finished() {
+		t.Fatalf("Expected the replay to finish after three ticks, got %d shown", m.shown)
+	}
+	if cmd := m.schedule(); cmd != nil {
+		t.Error("Expected no tick to be scheduled once finished")
+	}
+}
+
+func TestReplay_PauseAndResume(t *testing.T) {
+	m := newTestReplay()
+	stale := m.seq
+
+	if cmd := sendReplayKey(m, tea.KeyMsg{Type: tea.KeySpace}); !m.paused {
+		t.Fatal("Expected space to pause the replay")
+	} else if cmd != nil {
+		t.Error("Expected no tick to be scheduled while paused")
+	}
+
+	m.Update(replayTickMsg{seq: stale})
+	m.Update(replayTickMsg{seq: m.seq})
+	if m.shown != 0 {
+		t.Errorf("Expected no turns revealed while paused, got %d", m.shown)
+	}
+
+	sendReplayKey(m, tea.KeyMsg{Type: tea.KeySpace})
+	if m.paused {
+		t.Fatal("Expected space to resume the replay")
+	}
+	m.Update(replayTickMsg{seq: stale})
+	if m.shown != 0 {
+		t.Error("Expected a tick scheduled before the pause to be ignored")
+	}
+	m.Update(replayTickMsg{seq: m.seq})
+	if m.shown != 1 {
+		t.Errorf("Expected a turn revealed after resuming, got %d", m.shown)
+	}
+}
+
+func TestReplay_SpeedKeys(t *testing.T) {
+	m := newTestReplay()
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	stale := m.seq
+	sendReplayKey(m, runes("+"))
+	if m.delay != defaultReplayDelay/2 {
+		t.Errorf("Expected + to halve the delay, got %s", m.delay)
+	}
+	m.Update(replayTickMsg{seq: stale})
+	if m.shown != 0 {
+		t.Error("Expected the tick scheduled at the old speed to be ignored")
+	}
+
+	sendReplayKey(m, runes("-"))
+	sendReplayKey(m, runes("-"))
+	if m.delay != defaultReplayDelay*2 {
+		t.Errorf("Expected - to double the delay, got %s", m.delay)
+	}
+
+	for i := 0; i < 10; i++ {
+		sendReplayKey(m, runes("+"))
+	}
+	if m.delay != minReplayDelay {
+		t.Errorf("Expected the delay to stop at %s, got %s", minReplayDelay, m.delay)
+	}
+	for i := 0; i < 10; i++ {
+		sendReplayKey(m, runes("-"))
+	}
+	if m.delay != maxReplayDelay {
+		t.Errorf("Expected the delay to stop at %s, got %s", maxReplayDelay, m.delay)
+	}
+
+	// Changing speed while paused keeps the replay paused
+	sendReplayKey(m, tea.KeyMsg{Type: tea.KeySpace})
+	if cmd := sendReplayKey(m, runes("+")); cmd != nil || !m.paused {
+		t.Error("Expected a speed change while paused to stay paused")
+	}
+	if m.delay != maxReplayDelay/2 {
+		t.Errorf("Expected the delay to change while paused, got %s", m.delay)
+	}
+}
+
+func TestReplay_SpaceRestartsFinished(t *testing.T) {
+	m := newTestReplay()
+	for !m.finished() {
+		m.Update(replayTickMsg{seq: m.seq})
+	}
+	if !strings.Contains(m.renderFooter(), "3/3") {
+		t.Errorf("Expected the footer to show the final turn, got %q", m.renderFooter())
+	}
+
+	if cmd := sendReplayKey(m, tea.KeyMsg{Type: tea.KeySpace}); cmd == nil {
+		t.Error("Expected restarting to schedule a tick")
+	}
+	if m.shown != 0 || m.paused {
+		t.Errorf("Expected space to restart a finished replay, got %d shown, paused %v", m.shown, m.paused)
+	}
+}
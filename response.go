@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// defaultBoilerplatePrefixes are openers models commonly prepend to a turn
+var defaultBoilerplatePrefixes = []string{
+	"Sure, here's my argument:",
+	"Sure, here is my argument:",
+	"Here's my argument:",
+	"Here is my argument:",
+	"Certainly!",
+	"Sure!",
+}
+
+// cleanResponse trims leading and trailing whitespace from a completed
+// response and strips any of the given boilerplate prefixes from its start.
+// Prefixes are matched case-insensitively and stripped repeatedly, so
+// "Sure! Here's my argument:" loses both openers.
+func cleanResponse(s string, prefixes []string) string {
+	s = strings.TrimSpace(s)
+
+	for stripped := true; stripped; {
+		stripped = false
+		for _, prefix := range prefixes {
+			if prefix == "" || len(s) < len(prefix) {
+				continue
+			}
+			if strings.EqualFold(s[:len(prefix)], prefix) {
+				s = strings.TrimSpace(s[len(prefix):])
+				stripped = true
+			}
+		}
+	}
+
+	return s
+}
+
+// parsePrefixList splits a "|"-separated list of boilerplate prefixes,
+// dropping empty entries
+func parsePrefixList(list string) []string {
+	var prefixes []string
+	for _, prefix := range strings.Split(list, "|") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// applyResponsePrefix starts content with prefix exactly once. A model that
+// echoes the prefix it was primed with is not given a second copy.
+func applyResponsePrefix(prefix, content string) string {
+	if prefix == "" {
+		return content
+	}
+
+	rest := strings.TrimLeftFunc(content, unicode.IsSpace)
+	switch {
+	case strings.HasPrefix(rest, prefix):
+		return rest
+	case rest == "":
+		return prefix
+	case strings.TrimRightFunc(prefix, unicode.IsSpace) != prefix:
+		return prefix + rest
+	}
+	return prefix + " " + rest
+}
+
+// finishContent tidies a completed turn: boilerplate is trimmed when
+// enabled, a turn longer than the sentence limit is cut short and marked,
+// and the response prefix is kept at the start exactly once
+func (m *debateModel) finishContent(content string) string {
+	prefix := m.promptOptions.ResponsePrefix
+	content = strings.TrimPrefix(content, prefix)
+	if m.trimBoilerplate {
+		content = cleanResponse(content, m.boilerplatePrefixes)
+	}
+	if m.maxSentences > 0 {
+		if truncated, ok := truncateSentences(content, m.maxSentences); ok {
+			content = truncated + truncationMarker
+		}
+	}
+	return applyResponsePrefix(prefix, content)
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestCleanResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		prefixes []string
+		want     string
+	}{
+		{"whitespace only", "\n\n  My argument.  \n", nil, "My argument."},
+		{"default prefix", "Sure, here's my argument:\nTaxes fund roads.", defaultBoilerplatePrefixes, "Taxes fund roads."},
+		{"case insensitive", "HERE IS MY ARGUMENT: Taxes fund roads.", defaultBoilerplatePrefixes, "Taxes fund roads."},
+		{"stacked prefixes", "Sure! Here's my argument: Taxes fund roads.", defaultBoilerplatePrefixes, "Taxes fund roads."},
+		{"custom prefix", "As an AI, I think taxes fund roads.", []string{"As an AI,"}, "I think taxes fund roads."},
+		{"prefix not at start", "Taxes fund roads. Sure!", defaultBoilerplatePrefixes, "Taxes fund roads. Sure!"},
+		{"only prefix", "Certainly!", defaultBoilerplatePrefixes, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanResponse(tt.input, tt.prefixes); got != tt.want {
+				t.Errorf("cleanResponse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrefixList(t *testing.T) {
+	prefixes := parsePrefixList(" Sure, okay!| |Here's my take: ")
+	if len(prefixes) != 2 || prefixes[0] != "Sure, okay!" || prefixes[1] != "Here's my take:" {
+		t.Errorf("Unexpected prefixes: %q", prefixes)
+	}
+}
+
+func TestApplyResponsePrefix(t *testing.T) {
+	tests := []struct {
+		prefix, content, want string
+	}{
+		{"", " as is ", " as is "},
+		{"CLAIM:", " Cities need trams.", "CLAIM: Cities need trams."},
+		{"CLAIM:", "Cities need trams.", "CLAIM: Cities need trams."},
+		{"CLAIM:", "\nCLAIM: echoed by the model", "CLAIM: echoed by the model"},
+		{"CLAIM:", "", "CLAIM:"},
+		{"Position: ", "trams", "Position: trams"},
+	}
+
+	for _, tt := range tests {
+		if got := applyResponsePrefix(tt.prefix, tt.content); got != tt.want {
+			t.Errorf("applyResponsePrefix(%q, %q) = %q, want %q", tt.prefix, tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import "time"
+
+// DebateResult is everything a headless debate produced, for callers that
+// run debates programmatically rather than reading the printed transcript.
+// A debate that fails part way still returns the turns made before the
+// failure, with Err set.
+type DebateResult struct {
+	// Topic is the debated topic
+	Topic string
+
+	// Meta identifies the debate and how it was set up
+	Meta DebateMeta
+
+	// Turns holds every completed turn in order, moderator interjections
+	// included, even those pruned from the model's history
+	Turns []Turn
+
+	// Stats summarizes Turns
+	Stats DebateStats
+
+	// Verdict is the model tag of the winner when a judge decided the
+	// debate, and empty otherwise
+	Verdict string
+
+	// Err is why the debate stopped early, if it failed
+	Err error
+}
+
+// DebateStats summarizes a debate's turns
+type DebateStats struct {
+	// Arguments is how many turns the debaters made, not counting theses
+	Arguments int
+
+	// Theses is how many thesis statements the debaters made
+	Theses int
+
+	// Interjections is how many turns the moderator made
+	Interjections int
+
+	// Flagged is how many turns the content filter changed
+	Flagged int
+
+	// Characters is how many characters each debater wrote, by model tag
+	Characters map[string]int
+
+	// Duration is how long the debate took to run
+	Duration time.Duration
+}
+
+// newDebateStats summarizes turns from a debate that took duration
+func newDebateStats(turns []Turn, duration time.Duration) DebateStats {
+	stats := DebateStats{
+		Characters: make(map[string]int),
+		Duration:   duration,
+	}
+	for _, turn := range turns {
+		if turn.Moderator {
+			stats.Interjections++
+		} else if turn.Thesis {
+			stats.Theses++
+		} else {
+			stats.Arguments++
+			stats.Characters[turn.ModelName] += len([]rune(turn.Content))
+		}
+		if turn.Flagged {
+			stats.Flagged++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRunHeadless_Result verifies a completed headless run returns every
+// turn along with its stats
+func TestRunHeadless_Result(t *testing.T) {
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:    "mistral:7b",
+		model2Name:    "gemma3:4b",
+		ollamaClient:  NewOllamaClient(server.URL),
+		topic:         "Should homework be banned?",
+		moderator:     "llama3:8b",
+		moderateEvery: 2,
+		historyCap:    2,
+	}
+	result := runHeadless(context.Background(), m, 4)
+	if result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+
+	if result.Topic != m.topic {
+		t.Errorf("Expected topic %q, got %q", m.topic, result.Topic)
+	}
+	if len(result.Turns) != 5 || !result.Turns[2].Moderator {
+		t.Fatalf("Expected 4 arguments with an interjection after the second, got %+v", result.Turns)
+	}
+	stats := result.Stats
+	if stats.Arguments != 4 || stats.Interjections != 1 || stats.Flagged != 0 {
+		t.Errorf("Expected 4 arguments and 1 interjection, got %+v", stats)
+	}
+	if stats.Characters["mistral:7b"] != 4 || stats.Characters["gemma3:4b"] != 4 {
+		t.Errorf("Expected 4 characters from each debater, got %v", stats.Characters)
+	}
+	if stats.Duration <= 0 {
+		t.Errorf("Expected a positive duration, got %v", stats.Duration)
+	}
+	if result.Verdict != "" {
+		t.Errorf("Expected no verdict without a judge, got %q", result.Verdict)
+	}
+}
+
+// TestRunHeadless_PartialResult verifies a failed run still returns the
+// turns made before the failure
+func TestRunHeadless_PartialResult(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "ok", Done: true})
+	}))
+	defer server.Close()
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+	}
+	result := runHeadless(context.Background(), m, 4)
+	if result.Err == nil {
+		t.Fatal("Expected the failed turn to be reported")
+	}
+	if len(result.Turns) != 2 || result.Stats.Arguments != 2 {
+		t.Errorf("Expected the 2 turns made before the failure, got %d turns and %+v", len(result.Turns), result.Stats)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// contentFilter inspects a completed turn, returning the content to keep and
+// whether anything in it was flagged
+type contentFilter func(s string) (filtered string, flagged bool)
+
+// wordlistFilter redacts whole-word, case-insensitive matches of a list of
+// disallowed words
+type wordlistFilter struct {
+	pattern *regexp.Regexp
+}
+
+// LoadWordlist reads a safety wordlist from the file at path
+func LoadWordlist(path string) (*wordlistFilter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safety wordlist: %w", err)
+	}
+	defer f.Close()
+
+	return ParseWordlist(f)
+}
+
+// ParseWordlist parses a wordlist with one word or phrase per line. Blank
+// lines and lines starting with '#' are ignored.
+func ParseWordlist(r io.Reader) (*wordlistFilter, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, regexp.QuoteMeta(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read safety wordlist: %w", err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("safety wordlist is empty")
+	}
+
+	pattern, err := regexp.Compile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid safety wordlist: %w", err)
+	}
+	return &wordlistFilter{pattern: pattern}, nil
+}
+
+// filterContent replaces each listed word in s with asterisks of the same
+// length and reports whether any were found
+func (f *wordlistFilter) filterContent(s string) (filtered string, flagged bool) {
+	filtered = f.pattern.ReplaceAllStringFunc(s, func(match string) string {
+		flagged = true
+		return strings.Repeat("*", utf8.RuneCountInString(match))
+	})
+	return filtered, flagged
+}
+
+// applyContentFilter runs the content filter over the turn that just
+// finished, redacting it in place and marking it when flagged
+func (m *debateModel) applyContentFilter() {
+	if m.contentFilter == nil || len(m.history) == 0 {
+		return
+	}
+
+	last := &m.history[len(m.history)-1]
+	if m.moderating != last.Moderator || (!m.moderating && last.ModelName != m.getNextModel()) {
+		return
+	}
+	filtered, flagged := m.contentFilter(last.Content)
+	last.Content = filtered
+	last.Flagged = last.Flagged || flagged
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWordlistFilter_RedactsAndFlags(t *testing.T) {
+	f, err := ParseWordlist(strings.NewReader("# disallowed\ndarn\n\nheck no\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse wordlist: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		flagged bool
+	}{
+		{"clean", "Remote work is better.", "Remote work is better.", false},
+		{"single word", "That is a darn good point.", "That is a **** good point.", true},
+		{"case insensitive", "DARN. Heck no, it is not.", "****. *******, it is not.", true},
+		{"whole words only", "Darning socks is a skill.", "Darning socks is a skill.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, flagged := f.filterContent(tt.input)
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if flagged != tt.flagged {
+				t.Errorf("Expected flagged=%v, got %v", tt.flagged, flagged)
+			}
+		})
+	}
+}
+
+func TestParseWordlist_Empty(t *testing.T) {
+	if _, err := ParseWordlist(strings.NewReader("# only comments\n\n")); err == nil {
+		t.Errorf("Expected error for an empty wordlist")
+	}
+}
+
+func TestSafetyFilter_MarksFlaggedTurns(t *testing.T) {
+	f, err := ParseWordlist(strings.NewReader("darn\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse wordlist: %v", err)
+	}
+
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", contentFilter: f.filterContent, state: stateDebating, isGenerating: true}
+	m.history = []Turn{{ModelName: "mistral:7b", Content: "A darn fine argument."}}
+	m.turnStarted = true
+	m.applyContentFilter()
+
+	last := m.history[0]
+	if last.Content != "A **** fine argument." || !last.Flagged {
+		t.Errorf("Expected a redacted, flagged turn, got %+v", last)
+	}
+	if badge := m.turnBadge(0); !strings.Contains(badge, "filtered") {
+		t.Errorf("Expected a filtered marker, got %q", badge)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// maxTemperature is the highest sampling temperature a scenario may set
+const maxTemperature = 2.0
+
+// Scenario is a complete, reproducible debate setup loaded from a -scenario
+// file. Its settings fill in any flags not given on the command line.
+type Scenario struct {
+	OllamaURL string        `json:"ollama_url"`
+	Topic     string        `json:"topic"`
+	Turns     int           `json:"turns"` // Turns before the debate ends
+	Language  string        `json:"language"`
+	Phases    string        `json:"phases"`
+	Theme     string        `json:"theme"`
+	Model1    ScenarioModel `json:"model1"`
+	Model2    ScenarioModel `json:"model2"`
+}
+
+// ScenarioModel is one debater in a scenario
+type ScenarioModel struct {
+	Name        string   `json:"name"`
+	Alias       string   `json:"alias"`
+	Stance      string   `json:"stance"`      // PositionPro, PositionCon, or empty to follow speaking order
+	Temperature *float64 `json:"temperature"` // Sampling temperature sent with this model's requests
+}
+
+// LoadScenario reads and validates a scenario file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// ParseScenario decodes and validates a JSON scenario. Unknown fields are
+// rejected so that a misspelled setting is not silently ignored.
+func ParseScenario(data []byte) (*Scenario, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var scenario Scenario
+	if err := decoder.Decode(&scenario); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, fmt.Errorf("line %d: %w", lineAt(data, syntaxErr.Offset), err)
+		case errors.As(err, &typeErr):
+			return nil, fmt.Errorf("line %d: %s must be %s, got %s", lineAt(data, typeErr.Offset), typeErr.Field, typeErr.Type, typeErr.Value)
+		}
+		return nil, err
+	}
+
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// lineAt returns the 1-based line number of the byte offset in data
+func lineAt(data []byte, offset int64) int {
+	offset = min(offset, int64(len(data)))
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// validate reports the first field that cannot be used to run a debate
+func (s *Scenario) validate() error {
+	if err := s.Model1.validate("model1"); err != nil {
+		return err
+	}
+	if err := s.Model2.validate("model2"); err != nil {
+		return err
+	}
+	if s.Model1.Name == s.Model2.Name {
+		return fmt.Errorf("model1.name and model2.name are both '%s'; the models must differ", s.Model1.Name)
+	}
+	if s.Model1.Stance != "" && s.Model1.Stance == s.Model2.Stance {
+		return fmt.Errorf("model1.stance and model2.stance are both '%s'; the models must take opposite sides", s.Model1.Stance)
+	}
+
+	if s.OllamaURL != "" {
+		u, err := url.Parse(s.OllamaURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("ollama_url '%s' must be an http:// or https:// URL", s.OllamaURL)
+		}
+	}
+	if s.Turns < 0 {
+		return fmt.Errorf("turns must not be negative, got %d", s.Turns)
+	}
+	if _, err := ParseLanguage(s.Language); err != nil {
+		return fmt.Errorf("language: %w", err)
+	}
+	if _, err := ParsePhaseSchedule(s.Phases); err != nil {
+		return fmt.Errorf("phases: %w", err)
+	}
+	if s.Theme != "" {
+		if _, err := ParseTheme(s.Theme); err != nil {
+			return fmt.Errorf("theme: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validate reports the first invalid setting of the debater named field
+func (sm ScenarioModel) validate(field string) error {
+	if sm.Name == "" {
+		return fmt.Errorf("%s.name is required", field)
+	}
+	switch sm.Stance {
+	case "", PositionPro, PositionCon:
+	default:
+		return fmt.Errorf("%s.stance must be '%s' or '%s', got '%s'", field, PositionPro, PositionCon, sm.Stance)
+	}
+	if t := sm.Temperature; t != nil && (*t < 0 || *t > maxTemperature) {
+		return fmt.Errorf("%s.temperature must be between 0 and %g, got %g", field, maxTemperature, *t)
+	}
+	return nil
+}
+
+// proModel returns whichever of model1 and model2, the models debating on
+// model1's and model2's sides, the scenario has arguing pro, or empty when
+// positions follow speaking order
+func (s *Scenario) proModel(model1, model2 string) string {
+	switch {
+	case s.Model1.Stance == PositionPro || s.Model2.Stance == PositionCon:
+		return model1
+	case s.Model2.Stance == PositionPro || s.Model1.Stance == PositionCon:
+		return model2
+	}
+	return ""
+}
+
+// apply sets the flags in fs from the scenario, leaving any flag given on
+// the command line and any setting the scenario omits at its current value
+func (s *Scenario) apply(fs *flag.FlagSet) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	values := map[string]string{
+		"model1":     s.Model1.Name,
+		"model2":     s.Model2.Name,
+		"alias1":     s.Model1.Alias,
+		"alias2":     s.Model2.Alias,
+		"ollama-url": s.OllamaURL,
+		"topic":      s.Topic,
+		"language":   s.Language,
+		"phases":     s.Phases,
+		"theme":      s.Theme,
+	}
+	if s.Turns > 0 {
+		values["turns"] = strconv.Itoa(s.Turns)
+		values["max-turns"] = strconv.Itoa(s.Turns)
+	}
+
+	for name, value := range values {
+		if value == "" || explicit[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("scenario: -%s: %w", name, err)
+		}
+	}
+
+	// Stances belong to a side, whichever model the command line put there
+	pro := s.proModel(fs.Lookup("model1").Value.String(), fs.Lookup("model2").Value.String())
+	if pro != "" && !explicit["pro"] {
+		if err := fs.Set("pro", pro); err != nil {
+			return fmt.Errorf("scenario: -pro: %w", err)
+		}
+	}
+	return nil
+}
+
+// modelOptions returns the per-model options set by the scenario for model1
+// and model2, the models debating on model1's and model2's sides
+func (s *Scenario) modelOptions(model1, model2 string) map[string]map[string]interface{} {
+	options := make(map[string]map[string]interface{})
+	for i, sm := range []ScenarioModel{s.Model1, s.Model2} {
+		modelName := model1
+		if i == 1 {
+			modelName = model2
+		}
+		if sm.Temperature != nil {
+			options[modelName] = map[string]interface{}{"temperature": *sm.Temperature}
+		}
+	}
+	return options
+}
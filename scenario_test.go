@@ -0,0 +1,199 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const completeScenario = `{
+  "ollama_url": "http://localhost:11434",
+  "topic": "Is remote work better?",
+  "turns": 6,
+  "language": "pl",
+  "phases": "opening,closing",
+  "theme": "high-contrast",
+  "model1": {"name": "phi3:mini", "alias": "Optimist", "stance": "con", "temperature": 0.9},
+  "model2": {"name": "gemma3:4b", "alias": "Skeptic", "temperature": 0.3}
+}`
+
+// scenarioFlags defines the flags a scenario can set, as main does
+func scenarioFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("model1", "phi3:mini", "")
+	fs.String("model2", "gemma3:4b", "")
+	fs.String("alias1", "", "")
+	fs.String("alias2", "", "")
+	fs.String("pro", "", "")
+	fs.String("ollama-url", defaultOllamaURL, "")
+	fs.String("topic", "", "")
+	fs.Int("turns", defaultHeadlessTurns, "")
+	fs.Int("max-turns", 0, "")
+	fs.String("language", defaultLanguage, "")
+	fs.String("phases", "", "")
+	fs.String("theme", themes[0].Name, "")
+	return fs
+}
+
+// flagValue returns the current value of the named flag in fs
+func flagValue(fs *flag.FlagSet, name string) string {
+	return fs.Lookup(name).Value.String()
+}
+
+// TestLoadScenario_PopulatesInitialModel verifies a complete scenario sets
+// every flag and that a model built from them debates as described
+func TestLoadScenario_PopulatesInitialModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	if err := os.WriteFile(path, []byte(completeScenario), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scenario, err := LoadScenario(path)
+	if err != nil {
+		t.Fatalf("LoadScenario failed: %v", err)
+	}
+	fs := scenarioFlags()
+	if err := scenario.apply(fs); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	want := map[string]string{
+		"model1": "phi3:mini", "model2": "gemma3:4b",
+		"alias1": "Optimist", "alias2": "Skeptic",
+		"pro":   "gemma3:4b",
+		"topic": "Is remote work better?",
+		"turns": "6", "max-turns": "6",
+		"language": "pl", "phases": "opening,closing",
+		"theme": "high-contrast",
+	}
+	for name, value := range want {
+		if got := flagValue(fs, name); got != value {
+			t.Errorf("-%s = %q, want %q", name, got, value)
+		}
+	}
+
+	// Build the initial model from the flags the way main does
+	var requests []GenerateRequest
+	server := newTestServer(t, &requests)
+	client := NewOllamaClient(server.URL)
+	for modelName, options := range scenario.modelOptions(flagValue(fs, "model1"), flagValue(fs, "model2")) {
+		client.SetModelOptions(modelName, options)
+	}
+	m := &debateModel{
+		model1Name:    flagValue(fs, "model1"),
+		model2Name:    flagValue(fs, "model2"),
+		model1Alias:   flagValue(fs, "alias1"),
+		model2Alias:   flagValue(fs, "alias2"),
+		proModel:      flagValue(fs, "pro"),
+		ollamaClient:  client,
+		promptOptions: PromptOptions{Language: flagValue(fs, "language")},
+		maxTurns:      scenario.Turns,
+		initialTopic:  flagValue(fs, "topic"),
+	}
+
+	cmd := m.Init()
+	if m.state != stateDebating || m.topic != "Is remote work better?" {
+		t.Fatalf("Expected the debate to start on the scenario topic, got state %v topic %q", m.state, m.topic)
+	}
+	cmd()
+
+	if len(requests) != 1 || requests[0].Model != "phi3:mini" {
+		t.Fatalf("Expected phi3:mini to open, got %+v", requests)
+	}
+	if got := requests[0].Options["temperature"]; got != 0.9 {
+		t.Errorf("Expected model1's temperature 0.9, got %v", got)
+	}
+	for _, want := range []string{"Jesteś Optimist.", "Argumentujesz przeciwko tematowi"} {
+		if !strings.Contains(requests[0].Prompt, want) {
+			t.Errorf("Expected the opening prompt to contain %q, got:\n%s", want, requests[0].Prompt)
+		}
+	}
+}
+
+// TestScenario_CommandLineWins verifies flags given on the command line are
+// not overridden by the scenario
+func TestScenario_CommandLineWins(t *testing.T) {
+	scenario, err := ParseScenario([]byte(completeScenario))
+	if err != nil {
+		t.Fatalf("ParseScenario failed: %v", err)
+	}
+
+	fs := scenarioFlags()
+	if err := fs.Parse([]string{"-topic", "Tabs or spaces?", "-turns", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scenario.apply(fs); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	if got := flagValue(fs, "topic"); got != "Tabs or spaces?" {
+		t.Errorf("Expected the command-line topic to win, got %q", got)
+	}
+	if got := flagValue(fs, "turns"); got != "2" {
+		t.Errorf("Expected the command-line turns to win, got %q", got)
+	}
+	if got := flagValue(fs, "alias1"); got != "Optimist" {
+		t.Errorf("Expected unset flags to come from the scenario, got %q", got)
+	}
+}
+
+// TestScenario_SettingsFollowSide verifies a model swapped in on the
+// command line takes over its side's stance and temperature
+func TestScenario_SettingsFollowSide(t *testing.T) {
+	scenario, err := ParseScenario([]byte(completeScenario))
+	if err != nil {
+		t.Fatalf("ParseScenario failed: %v", err)
+	}
+
+	fs := scenarioFlags()
+	if err := fs.Parse([]string{"-model2", "llama3:8b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scenario.apply(fs); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	if got := flagValue(fs, "pro"); got != "llama3:8b" {
+		t.Errorf("Expected model2's side, now llama3:8b, to argue pro, got %q", got)
+	}
+	options := scenario.modelOptions(flagValue(fs, "model1"), flagValue(fs, "model2"))
+	if got := options["llama3:8b"]["temperature"]; got != 0.3 {
+		t.Errorf("Expected llama3:8b to get model2's temperature 0.3, got %v", got)
+	}
+	if _, ok := options["gemma3:4b"]; ok {
+		t.Errorf("Expected no options for the replaced gemma3:4b, got %v", options)
+	}
+}
+
+func TestParseScenario_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario string
+		want     string
+	}{
+		{"missing model", `{"model1": {"name": "a"}}`, "model2.name is required"},
+		{"bad stance", `{"model1": {"name": "a", "stance": "neutral"}, "model2": {"name": "b"}}`, "model1.stance must be 'pro' or 'con', got 'neutral'"},
+		{"same model", `{"model1": {"name": "a"}, "model2": {"name": "a"}}`, "model1.name and model2.name are both 'a'"},
+		{"same stance", `{"model1": {"name": "a", "stance": "pro"}, "model2": {"name": "b", "stance": "pro"}}`, "both 'pro'"},
+		{"temperature", `{"model1": {"name": "a"}, "model2": {"name": "b", "temperature": 3}}`, "model2.temperature must be between 0 and 2, got 3"},
+		{"url", `{"ollama_url": "localhost:11434", "model1": {"name": "a"}, "model2": {"name": "b"}}`, "ollama_url 'localhost:11434' must be an http:// or https:// URL"},
+		{"turns", `{"turns": -1, "model1": {"name": "a"}, "model2": {"name": "b"}}`, "turns must not be negative"},
+		{"language", `{"language": "xx", "model1": {"name": "a"}, "model2": {"name": "b"}}`, "language: unsupported language 'xx'"},
+		{"phases", `{"phases": "recess", "model1": {"name": "a"}, "model2": {"name": "b"}}`, "phases: unknown phase 'recess'"},
+		{"theme", `{"theme": "dark", "model1": {"name": "a"}, "model2": {"name": "b"}}`, `theme: unknown theme "dark"`},
+		{"unknown field", `{"colour": "dark", "model1": {"name": "a"}, "model2": {"name": "b"}}`, `unknown field "colour"`},
+		{"wrong type", "{\n  \"turns\": \"six\"\n}", "line 2: turns must be int, got string"},
+		{"syntax", "{\n  \"topic\": \"x\",\n}", "line 3:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScenario([]byte(tt.scenario))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
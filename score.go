@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scoreTimeout bounds how long the judge may take to score a turn
+const scoreTimeout = 2 * time.Minute
+
+// scoreMeterWidth is how many cells the footer's score meter spans
+const scoreMeterWidth = 20
+
+// scoreMsg carries the judge's score after a turn. seq ties it to the
+// request it answers, so that late scores for earlier turns are ignored.
+type scoreMsg struct {
+	seq   int
+	score int
+	ok    bool // Whether the judge gave a score that could be read
+}
+
+// BuildScorePrompt asks a judge model for a quick 0-100 reading of which of
+// two debaters is ahead so far, 100 meaning the first
+func BuildScorePrompt(topic string, history []Turn, speakers []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("You are scoring a debate in progress on the topic: \"%s\"\n\n", topic))
+	prompt.WriteString(fmt.Sprintf("The debaters are %s.\n\n", strings.Join(speakers, " and ")))
+	prompt.WriteString("Transcript so far:\n")
+	prompt.WriteString(FormatHistory(history))
+	prompt.WriteString("\n\n")
+	prompt.WriteString(fmt.Sprintf("Who is arguing more convincingly so far? Give a score from 0 to 100, where 100 means %s is far ahead, 0 means %s is far ahead and 50 means they are even. ",
+		speakers[0], speakers[1]))
+	prompt.WriteString("Do not explain; reply with a single line in exactly this form:\n")
+	prompt.WriteString("SCORE: <number>\n")
+
+	return prompt.String()
+}
+
+// parseScore extracts the score from a judge's response, clamped to 0-100.
+// It reads the last "SCORE:" line, tolerating decoration such as "**",
+// "%" or "/100", and reports false when there is no number to read.
+func parseScore(response string) (int, bool) {
+	lines := strings.Split(response, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.Trim(lines[i], " *_")
+		if len(line) < len("SCORE:") || !strings.EqualFold(line[:len("SCORE:")], "SCORE:") {
+			continue
+		}
+
+		value := strings.TrimLeft(line[len("SCORE:"):], " *_")
+		end := 0
+		for end < len(value) && (value[end] == '-' && end == 0 || value[end] == '.' || value[end] >= '0' && value[end] <= '9') {
+			end++
+		}
+		score, err := strconv.ParseFloat(value[:end], 64)
+		if err != nil {
+			return 0, false
+		}
+		return int(min(max(score, 0), 100) + 0.5), true
+	}
+
+	return 0, false
+}
+
+// scoreTurn asks the judge to score the debate so far, replacing any
+// scoring still in flight. It returns nil when the meter is off.
+func (m *debateModel) scoreTurn() tea.Cmd {
+	if m.scoreModel == "" {
+		return nil
+	}
+	if m.scoreCancel != nil {
+		m.scoreCancel()
+	}
+	ctx, cancel := context.WithTimeout(m.requestContext(), scoreTimeout)
+	m.scoreCancel = cancel
+	m.scoreSeq++
+
+	seq := m.scoreSeq
+	backend, judge := m.backend(), m.scoreModel
+	prompt := BuildScorePrompt(m.topic, m.promptHistory(), []string{m.sideName(0), m.sideName(1)})
+	return func() tea.Msg {
+		defer cancel()
+		response, err := collectResponse(ctx, backend, judge, prompt, nil)
+		if err != nil {
+			return scoreMsg{seq: seq}
+		}
+		score, ok := parseScore(response)
+		return scoreMsg{seq: seq, score: score, ok: ok}
+	}
+}
+
+// applyScore shows the latest score on the meter. A score that could not be
+// read leaves the last one in place, marked as out of date.
+func (m *debateModel) applyScore(msg scoreMsg) {
+	if msg.seq != m.scoreSeq {
+		return
+	}
+	m.scoreCancel = nil
+	m.scoreStale = !msg.ok
+	if msg.ok {
+		m.score = msg.score
+		m.scored = true
+	}
+}
+
+// stopScoring abandons any scoring in flight and clears the meter
+func (m *debateModel) stopScoring() {
+	if m.scoreCancel != nil {
+		m.scoreCancel()
+		m.scoreCancel = nil
+	}
+	m.scoreSeq++
+	m.score = 0
+	m.scored = false
+	m.scoreStale = false
+}
+
+// withScore runs score alongside cmd. Either may be nil.
+func withScore(score, cmd tea.Cmd) tea.Cmd {
+	if score == nil {
+		return cmd
+	}
+	if cmd == nil {
+		return score
+	}
+	return tea.Batch(score, cmd)
+}
+
+// renderScoreMeter draws the judge's score as a bar split between the
+// debaters' colors, model1's share on the left
+func (m *debateModel) renderScoreMeter() string {
+	if !m.scored {
+		return subtleStyle.Render("⚖️  Scoring after the first turn...")
+	}
+
+	filled := m.score * scoreMeterWidth / 100
+	mark1, mark2 := "█", "█"
+	if renderMode == renderPlain {
+		mark1, mark2 = "#", "="
+	}
+	note := ""
+	if m.scoreStale {
+		note = " (last score; the judge's latest could not be read)"
+	}
+	return fmt.Sprintf("⚖️  %s %s%s %s %s",
+		model1LabelStyle.Render(m.sideName(0)),
+		model1LabelStyle.Render(strings.Repeat(mark1, filled)),
+		model2LabelStyle.Render(strings.Repeat(mark2, scoreMeterWidth-filled)),
+		model2LabelStyle.Render(m.sideName(1)),
+		subtleStyle.Render(fmt.Sprintf("%d/100%s", m.score, note)))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseScore(t *testing.T) {
+	tests := []struct {
+		response string
+		want     int
+		ok       bool
+	}{
+		{"SCORE: 62", 62, true},
+		{"The first debater leads.\nscore: 70", 70, true},
+		{"**SCORE:** 55", 55, true},
+		{"SCORE: 80/100", 80, true},
+		{"SCORE: 45%", 45, true},
+		{"SCORE: 66.6", 67, true},
+		{"SCORE: 150", 100, true},
+		{"SCORE: -20", 0, true},
+		{"SCORE: 10\nOn reflection:\nSCORE: 30", 30, true},
+		{"SCORE: about even", 0, false},
+		{"I think it is 60", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseScore(tt.response)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseScore(%q) = %d, %v; want %d, %v", tt.response, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBuildScorePrompt(t *testing.T) {
+	history := []Turn{{ModelName: "mistral:7b", Content: "Cats are better."}}
+	prompt := BuildScorePrompt("Cats or dogs?", history, []string{"Optimist", "Skeptic"})
+	for _, want := range []string{"Cats or dogs?", "[mistral:7b]: Cats are better.", "100 means Optimist is far ahead", "0 means Skeptic", "SCORE: <number>"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected the prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+}
+
+// scoringModel returns a debate whose judge replies with the given
+// responses in turn
+func scoringModel(responses ...string) *debateModel {
+	fixture := &Fixture{}
+	for _, response := range responses {
+		fixture.Responses = append(fixture.Responses, FixtureResponse{Model: "judge:1b", Chunks: []FixtureChunk{{Text: response}}})
+	}
+	return &debateModel{
+		model1Name: "phi3:mini",
+		model2Name: "gemma3:4b",
+		topic:      "Is remote work better?",
+		history:    []Turn{{ModelName: "phi3:mini", Content: "Remote work wins."}},
+		generator:  newFixtureGenerator(fixture),
+		scoreModel: "judge:1b",
+	}
+}
+
+func TestScoreTurn_UpdatesMeter(t *testing.T) {
+	m := scoringModel("SCORE: 75", "no idea", "SCORE: 40")
+
+	m.Update(m.scoreTurn()())
+	if !m.scored || m.score != 75 || m.scoreStale {
+		t.Fatalf("Expected a score of 75, got %d (scored %v, stale %v)", m.score, m.scored, m.scoreStale)
+	}
+	m.width = 200
+	if meter := m.renderScoreMeter(); !strings.Contains(meter, "75/100") || !strings.Contains(meter, strings.Repeat("█", 15)) {
+		t.Errorf("Expected the meter three quarters filled, got %q", meter)
+	}
+
+	// An unreadable score keeps the last one, marked out of date
+	m.Update(m.scoreTurn()())
+	if m.score != 75 || !m.scoreStale {
+		t.Errorf("Expected the last score kept and marked stale, got %d (stale %v)", m.score, m.scoreStale)
+	}
+	if !strings.Contains(m.renderScoreMeter(), "could not be read") {
+		t.Errorf("Expected the meter to say the score is out of date, got %q", m.renderScoreMeter())
+	}
+
+	m.Update(m.scoreTurn()())
+	if m.score != 40 || m.scoreStale {
+		t.Errorf("Expected a fresh score of 40, got %d (stale %v)", m.score, m.scoreStale)
+	}
+}
+
+func TestScoreTurn_IgnoresLateScores(t *testing.T) {
+	// The fixture answers in the order the requests run, latest first
+	m := scoringModel("SCORE: 20", "SCORE: 90")
+	first := m.scoreTurn()
+	second := m.scoreTurn()
+
+	m.Update(second())
+	m.Update(first())
+	if m.score != 20 {
+		t.Errorf("Expected the score for the latest turn, got %d", m.score)
+	}
+}
+
+func TestScoreTurn_Off(t *testing.T) {
+	m := scoringModel()
+	m.scoreModel = ""
+	if m.scoreTurn() != nil {
+		t.Error("Expected no scoring without a judge")
+	}
+	if footer := m.renderFooter(); strings.Contains(footer, "⚖️") {
+		t.Errorf("Expected no meter in the footer, got %q", footer)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// truncationMarker is appended to a turn cut short by -max-sentences
+const truncationMarker = " […]"
+
+// sentenceAbbreviations are words that end in a period without ending the
+// sentence, lowercased and without their final period. Abbreviations that
+// often end a sentence, like "etc.", are left out so those sentences still
+// end.
+var sentenceAbbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true, "st": true,
+	"vs": true, "e.g": true, "i.e": true, "cf": true, "approx": true, "fig": true, "inc": true,
+	"ltd": true, "u.s": true, "u.k": true,
+}
+
+// sentenceClosers may follow a sentence's final punctuation and still
+// belong to the sentence
+const sentenceClosers = `.!?…"')]”’`
+
+// sentenceEnds returns the byte offset just past the end of each sentence
+// in text. A sentence ends at '.', '!', '?' or '…', with any closing quotes
+// or brackets, when followed by whitespace or the end of the text. A period
+// after an abbreviation, or after an initial followed by a capitalized name,
+// does not end a sentence.
+// A blank line also ends one, so headings and list items without final
+// punctuation count as sentences of their own.
+func sentenceEnds(text string) []int {
+	var ends []int
+	last := 0
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		j := i + size
+
+		switch {
+		case r == '.' || r == '!' || r == '?' || r == '…':
+			for j < len(text) {
+				next, nextSize := utf8.DecodeRuneInString(text[j:])
+				if !strings.ContainsRune(sentenceClosers, next) {
+					break
+				}
+				j += nextSize
+			}
+			next, _ := utf8.DecodeRuneInString(text[j:])
+			if (j == len(text) || unicode.IsSpace(next)) && !(r == '.' && endsWithAbbreviation(text[:i], text[j:])) {
+				ends = append(ends, j)
+				last = j
+			}
+
+		case r == '\n' && strings.HasPrefix(strings.TrimLeft(text[j:], " \t\r"), "\n"):
+			if strings.TrimSpace(text[last:i]) != "" {
+				ends = append(ends, i)
+				last = i
+			}
+		}
+
+		i = j
+	}
+
+	return ends
+}
+
+// endsWithAbbreviation reports whether text ends with a word that a
+// following period abbreviates rather than ends. A single capital letter is
+// taken for an initial only when the rest of the text goes on with a
+// capitalized name; "I" never is one.
+func endsWithAbbreviation(text, rest string) bool {
+	word := text[strings.LastIndexFunc(text, unicode.IsSpace)+1:]
+	word = strings.TrimLeft(word, `"'([“‘`)
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		next, _ := utf8.DecodeRuneInString(strings.TrimLeftFunc(rest, unicode.IsSpace))
+		return unicode.IsUpper(r) && r != 'I' && unicode.IsUpper(next)
+	}
+	return sentenceAbbreviations[strings.ToLower(word)]
+}
+
+// truncateSentences cuts text after its first n sentences. It reports
+// whether anything was cut; text with n or fewer sentences is returned as it
+// is.
+func truncateSentences(text string, n int) (string, bool) {
+	ends := sentenceEnds(text)
+	if n <= 0 || len(ends) < n {
+		return text, false
+	}
+
+	cut := ends[n-1]
+	if strings.TrimSpace(text[cut:]) == "" {
+		return text, false
+	}
+	return strings.TrimRightFunc(text[:cut], unicode.IsSpace), true
+}
@@ -0,0 +1,87 @@
+package main
+
+import "testing"
+
+func TestSentenceEnds(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"simple", "Homework helps. It builds habits! Does it?", []string{"Homework helps.", " It builds habits!", " Does it?"}},
+		{"abbreviations", "Dr. Smith disagrees, e.g. in his 2019 study. Mr. Jones agrees.", []string{"Dr. Smith disagrees, e.g. in his 2019 study.", " Mr. Jones agrees."}},
+		{"initials", "J. K. Rowling wrote it. Fine.", []string{"J. K. Rowling wrote it.", " Fine."}},
+		{"pronoun I", "Nobody agrees more than I. Still, it helps.", []string{"Nobody agrees more than I.", " Still, it helps."}},
+		{"letter before a number", "Pick plan B. 3 of 4 agree.", []string{"Pick plan B.", " 3 of 4 agree."}},
+		{"letter at the end", "We prefer option A.", []string{"We prefer option A."}},
+		{"decimals", "It rose 3.5 percent. Then fell.", []string{"It rose 3.5 percent.", " Then fell."}},
+		{"closing quotes", `He said "no." She left.`, []string{`He said "no."`, " She left."}},
+		{"ellipsis and repeats", "Wait... really?! Yes…", []string{"Wait...", " really?!", " Yes…"}},
+		{"etc ends a sentence", "Cars, buses, etc. They pollute.", []string{"Cars, buses, etc.", " They pollute."}},
+		{"blank line", "Point one\n\nIt matters.", []string{"Point one", "\n\nIt matters."}},
+		{"unterminated", "No final stop", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ends := sentenceEnds(tt.text)
+			var got []string
+			start := 0
+			for _, end := range ends {
+				got = append(got, tt.text[start:end])
+				start = end
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected sentences %q, got %q", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Sentence %d: expected %q, got %q", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTruncateSentences(t *testing.T) {
+	text := "Homework helps. Dr. Lee agrees! It builds habits. Does it?"
+
+	tests := []struct {
+		n       int
+		want    string
+		wantCut bool
+	}{
+		{1, "Homework helps.", true},
+		{2, "Homework helps. Dr. Lee agrees!", true},
+		{4, text, false},
+		{10, text, false},
+		{0, text, false},
+	}
+	for _, tt := range tests {
+		got, cut := truncateSentences(text, tt.n)
+		if got != tt.want || cut != tt.wantCut {
+			t.Errorf("truncateSentences(%d) = %q, %v; want %q, %v", tt.n, got, cut, tt.want, tt.wantCut)
+		}
+	}
+
+	if got, cut := truncateSentences("One. Two.   \n", 2); cut || got != "One. Two.   \n" {
+		t.Errorf("Expected trailing whitespace not to count as a cut, got %q, %v", got, cut)
+	}
+}
+
+// TestFinishContent_MaxSentences verifies finished turns are cut to the
+// sentence limit and marked, keeping the response prefix
+func TestFinishContent_MaxSentences(t *testing.T) {
+	m := &debateModel{maxSentences: 2, promptOptions: PromptOptions{ResponsePrefix: "CLAIM:"}}
+
+	got := m.finishContent("CLAIM: Homework helps. It builds habits. It teaches focus.")
+	if want := "CLAIM: Homework helps. It builds habits." + truncationMarker; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if again := m.finishContent(got); again != got {
+		t.Errorf("Expected finishing twice to change nothing, got %q", again)
+	}
+	if got := m.finishContent("CLAIM: Short. Sweet."); got != "CLAIM: Short. Sweet." {
+		t.Errorf("Expected a turn within the limit to be kept, got %q", got)
+	}
+}
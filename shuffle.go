@@ -0,0 +1,76 @@
+package main
+
+import "math/rand"
+
+// roundShuffle picks at random which model opens each round, a round being
+// one stint of each model, instead of model1 always opening (see
+// -shuffle-rounds). The zero value keeps strict alternation.
+type roundShuffle struct {
+	rng         *rand.Rand // Draws each round's opener; nil when not shuffling
+	seed        int64      // Seed rng was created with
+	secondStint bool       // Whether the model speaking now closes its round
+	firstOpener int        // Model, 0 or 1, that opened the debate
+
+	// wasSecondStint is secondStint before the last switch, so that a
+	// continuation can step back to the turn it extends
+	wasSecondStint bool
+}
+
+// newRoundShuffle returns a shuffle whose openers are drawn from seed, so
+// the same seed gives the same order
+func newRoundShuffle(seed int64) roundShuffle {
+	return roundShuffle{rng: rand.New(rand.NewSource(seed)), seed: seed}
+}
+
+// enabled reports whether rounds are shuffled
+func (s *roundShuffle) enabled() bool {
+	return s.rng != nil
+}
+
+// opener draws the model, 0 or 1, that opens the next round
+func (s *roundShuffle) opener() int {
+	return s.rng.Intn(2)
+}
+
+// openDebate sets up the turn order for a new debate. With shuffling on, a
+// random model opens, unless -first-message has model1 open.
+func (m *debateModel) openDebate() {
+	m.currentTurn = 0
+	m.turnStreak = 0
+	if !m.shuffle.enabled() {
+		return
+	}
+	m.shuffle.secondStint = false
+	if m.firstMessage == "" {
+		m.currentTurn = m.shuffle.opener()
+	}
+	m.shuffle.firstOpener = m.currentTurn
+}
+
+// openingModel returns the model that opened the debate, which is model1
+// unless rounds are shuffled
+func (m *debateModel) openingModel() string {
+	if m.shuffle.firstOpener == 1 {
+		return m.model2Name
+	}
+	return m.model1Name
+}
+
+// unswitchStint steps the round back to the stint before the last switch
+func (m *debateModel) unswitchStint() {
+	m.shuffle.secondStint = m.shuffle.wasSecondStint
+}
+
+// switchStint passes the turn on once the current model's stint is over:
+// to its opponent within a round, and to a newly drawn opener after it
+func (m *debateModel) switchStint() {
+	m.shuffle.wasSecondStint = m.shuffle.secondStint
+	m.turnStreak = 0
+	if !m.shuffle.secondStint {
+		m.shuffle.secondStint = true
+		m.currentTurn = 1 - m.currentTurn
+		return
+	}
+	m.shuffle.secondStint = false
+	m.currentTurn = m.shuffle.opener()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// speakingOrder returns who speaks in the first n turns of a debate between
+// "a" and "b", one letter per turn
+func speakingOrder(m *debateModel, n int) string {
+	m.model1Name, m.model2Name = "a", "b"
+	m.openDebate()
+	var order strings.Builder
+	for i := 0; i < n; i++ {
+		order.WriteString(m.getNextModel())
+		m.switchTurn()
+	}
+	return order.String()
+}
+
+func TestShuffleRounds_Reproducible(t *testing.T) {
+	first := speakingOrder(&debateModel{shuffle: newRoundShuffle(42)}, 40)
+	second := speakingOrder(&debateModel{shuffle: newRoundShuffle(42)}, 40)
+	if first != second {
+		t.Errorf("Expected the same seed to give the same order, got %s and %s", first, second)
+	}
+}
+
+func TestShuffleRounds_EachRoundHasBothModels(t *testing.T) {
+	order := speakingOrder(&debateModel{shuffle: newRoundShuffle(7)}, 40)
+	openers := make(map[string]bool)
+	for i := 0; i < len(order); i += 2 {
+		round := order[i : i+2]
+		if round != "ab" && round != "ba" {
+			t.Fatalf("Expected each round to have one turn of each model, got %q in %s", round, order)
+		}
+		openers[round[:1]] = true
+	}
+	if !openers["a"] || !openers["b"] {
+		t.Errorf("Expected both models to open a round, got %s", order)
+	}
+}
+
+func TestShuffleRounds_TurnRatio(t *testing.T) {
+	m := &debateModel{shuffle: newRoundShuffle(3), turnRatio: turnRatio{model1: 2, model2: 1}}
+	order := speakingOrder(m, 30)
+	for i := 0; i < len(order); i += 3 {
+		round := order[i : i+3]
+		if round != "aab" && round != "baa" {
+			t.Fatalf("Expected each round to hold model1's two turns together with model2's one, got %q in %s", round, order)
+		}
+	}
+}
+
+func TestShuffleRounds_DefaultAlternates(t *testing.T) {
+	if order := speakingOrder(&debateModel{}, 6); order != "ababab" {
+		t.Errorf("Expected strict alternation without shuffling, got %s", order)
+	}
+}
+
+// TestShuffleRounds_OpenerIsPro verifies the model drawn to open the
+// debate takes the pro side when no -pro is set
+func TestShuffleRounds_OpenerIsPro(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		m := &debateModel{model1Name: "a", model2Name: "b", shuffle: newRoundShuffle(seed)}
+		m.openDebate()
+		opener := m.getNextModel()
+		if m.stance(opener) != PositionPro {
+			t.Errorf("seed %d: expected opener %s to be pro", seed, opener)
+		}
+	}
+}
+
+// TestShuffleRounds_UnswitchStint verifies stepping back over a switch
+// restores the round's progress, as continuing the last turn does
+func TestShuffleRounds_UnswitchStint(t *testing.T) {
+	m := &debateModel{model1Name: "a", model2Name: "b", shuffle: newRoundShuffle(5)}
+	m.openDebate()
+	m.switchTurn()
+	if !m.shuffle.secondStint {
+		t.Fatal("Expected the second stint of the round after one switch")
+	}
+	m.unswitchStint()
+	if m.shuffle.secondStint {
+		t.Error("Expected the first stint after stepping back")
+	}
+}
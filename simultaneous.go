@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// roundState tracks a simultaneous round, in which both models answer the
+// same history at once. Index 0 is model1 and index 1 is model2.
+type roundState struct {
+	models  [2]string
+	prompts [2]string
+	streams [2]<-chan string
+	errs    [2]<-chan error
+	answers [2]string
+	done    [2]bool
+}
+
+// side returns which model a stream belongs to, or -1 for an unknown stream
+func (r *roundState) side(stream <-chan string) int {
+	for i, s := range r.streams {
+		if s != nil && s == stream {
+			return i
+		}
+	}
+	return -1
+}
+
+// startRound sends both models their prompts at once, replacing any
+// in-flight generation. Neither sees the other's answer until the round
+// is over.
+func (m *debateModel) startRound() tea.Cmd {
+	m.cancelGeneration()
+	ctx, cancel := context.WithCancel(m.requestContext())
+	m.cancel = cancel
+	m.stream = nil
+	m.streamErrs = nil
+	m.turnStarted = false
+
+	round := &roundState{models: [2]string{m.model1Name, m.model2Name}}
+	turnIndex := m.argumentCount()
+	cmds := make([]tea.Cmd, len(round.models))
+	for i, modelName := range round.models {
+		round.prompts[i] = m.promptFor(modelName, turnIndex+i)
+		round.streams[i], round.errs[i] = m.backend().GenerateResponseWithOptions(ctx, modelName, round.prompts[i], nil)
+		cmds[i] = waitForNextChunk(round.streams[i], round.errs[i])
+	}
+	m.round = round
+	m.lastPrompt = round.prompts[0]
+
+	return tea.Batch(cmds...)
+}
+
+// updateRound handles a message from one of the round's streams. It reports
+// false for any other message, which Update then handles as usual.
+func (m *debateModel) updateRound(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case responseChunkMsg:
+		i := m.round.side(msg.responseChan)
+		if i < 0 {
+			return nil, false
+		}
+		m.round.answers[i] += msg.chunk
+		return waitForNextChunk(msg.responseChan, msg.errorChan), true
+
+	case responseCompleteMsg:
+		i := m.round.side(msg.responseChan)
+		if i < 0 {
+			return nil, false
+		}
+		m.round.done[i] = true
+		if !m.round.done[0] || !m.round.done[1] {
+			return nil, true
+		}
+		return m.finishRound(), true
+
+	case responseErrorMsg:
+		i := m.round.side(msg.responseChan)
+		if i < 0 {
+			return nil, false
+		}
+
+		// Hand the failure to the usual error handling as the failed
+		// model's turn. Retrying or moving on starts a fresh round.
+		m.currentTurn = i
+		m.turnStreak = 0
+		m.stream = msg.responseChan
+		m.lastPrompt = m.round.prompts[i]
+		m.round = nil
+		return nil, false
+	}
+	return nil, false
+}
+
+// finishRound adds both answers to the history together, model1's first,
+// and starts the next round
+func (m *debateModel) finishRound() tea.Cmd {
+	round := m.round
+	m.round = nil
+	m.cancelGeneration()
+	m.isGenerating = false
+	m.contextRetried = false
+	m.autoRetries = 0
+	m.errorMsg = ""
+
+	for i, modelName := range round.models {
+		m.history = append(m.history, Turn{
+			ModelName:   modelName,
+			DisplayName: m.sideName(i),
+			Content:     m.finishContent(round.answers[i]),
+			Timestamp:   time.Now(),
+			Prompt:      m.storedPrompt(round.prompts[i]),
+			Stance:      m.stance(modelName),
+		})
+		m.currentTurn = i
+		m.turnStarted = true
+		m.applyContentFilter()
+		m.applySoftLength()
+		m.reportTurn(false)
+	}
+	m.turnStarted = false
+	m.currentTurn = 0
+	m.turnStreak = 0
+
+	// Finish the debate if either model has come to agree
+	if m.endOnConsensus && (detectConsensus(m.history[len(m.history)-2]) || detectConsensus(m.history[len(m.history)-1])) {
+		m.state = stateStopped
+		m.endReason = "🤝 Consensus reached"
+		return nil
+	}
+
+	// Finish the debate once the turn limit is reached
+	if m.maxTurns > 0 && m.argumentCount() >= m.maxTurns {
+		m.state = stateStopped
+		m.endReason = fmt.Sprintf("🏁 Finished after %d turns", m.maxTurns)
+		return nil
+	}
+
+	m.pruneHistory()
+
+	// Let the moderator interject when due, then start the next round
+	m.isGenerating = true
+	cmd := m.startRound
+	if m.moderationDue() {
+		cmd = m.generateModeration
+	}
+
+	// Both turns arrive at once, so pace their reveal from here
+	if m.typewriter.enabled() && !m.typewriter.ticking {
+		m.typewriter.ticking = true
+		return tea.Batch(cmd(), typewriterTick())
+	}
+	return cmd()
+}
+
+// headlessRound generates both models' answers to the current history at
+// once for a headless debate, returning model1's turn first
+func (m *debateModel) headlessRound(ctx context.Context) ([]Turn, error) {
+	models := [2]string{m.model1Name, m.model2Name}
+	turnIndex := m.argumentCount()
+
+	var prompts, answers [2]string
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, modelName := range models {
+		prompts[i] = m.promptFor(modelName, turnIndex+i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			answers[i], errs[i] = collectResponse(ctx, m.backend(), modelName, prompts[i], nil)
+		}()
+	}
+	wg.Wait()
+
+	turns := make([]Turn, 0, len(models))
+	for i, modelName := range models {
+		if errs[i] != nil {
+			if m.sink != nil {
+				m.sink.Send(m.errorEvent(modelName, errs[i]))
+			}
+			return turns, errs[i]
+		}
+
+		content := m.finishContent(answers[i])
+		var flagged bool
+		if m.contentFilter != nil {
+			content, flagged = m.contentFilter(content)
+		}
+		turns = append(turns, Turn{
+			ModelName:   modelName,
+			DisplayName: m.sideName(i),
+			Content:     content,
+			Timestamp:   time.Now(),
+			Flagged:     flagged,
+			OverLength:  overLength(content, m.softLength),
+			Prompt:      m.storedPrompt(prompts[i]),
+			Stance:      m.stance(modelName),
+		})
+	}
+	return turns, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newRoundServer answers every generate request with a reply naming the
+// model, recording the requests
+func newRoundServer(t *testing.T, requests *[]GenerateRequest) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		*requests = append(*requests, req)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Answer from " + req.Model, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestSimultaneous_RoundRevealsBothTurns verifies neither turn of a round is
+// shown until both models have finished, and the next round only starts then
+func TestSimultaneous_RoundRevealsBothTurns(t *testing.T) {
+	var requests []GenerateRequest
+	server := newRoundServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		state:        stateDebating,
+		isGenerating: true,
+		simultaneous: true,
+	}
+	defer m.shutdown()
+
+	m.generateResponse()
+	round := m.round
+	if round == nil {
+		t.Fatal("Expected a round to start")
+	}
+
+	// model2 finishes first, but nothing is shown until model1 is done too
+	m.Update(responseChunkMsg{chunk: "Keep it.", responseChan: round.streams[1]})
+	m.Update(responseCompleteMsg{responseChan: round.streams[1]})
+	m.Update(responseChunkMsg{chunk: "Ban it.", responseChan: round.streams[0]})
+	if len(m.history) != 0 || m.round != round {
+		t.Fatalf("Expected the round to wait for both models, got %+v", m.history)
+	}
+
+	m.Update(responseCompleteMsg{responseChan: round.streams[0]})
+	if len(m.history) != 2 {
+		t.Fatalf("Expected both turns once the round finished, got %+v", m.history)
+	}
+	if m.history[0].ModelName != "mistral:7b" || m.history[0].Content != "Ban it." ||
+		m.history[1].ModelName != "gemma3:4b" || m.history[1].Content != "Keep it." {
+		t.Errorf("Expected model1's turn then model2's, got %+v", m.history)
+	}
+	if m.round == nil || m.round == round {
+		t.Error("Expected the next round to start after both turns were revealed")
+	}
+}
+
+// TestSimultaneous_Headless verifies headless rounds make both turns from
+// the same history before the next round begins
+func TestSimultaneous_Headless(t *testing.T) {
+	var requests []GenerateRequest
+	server := newRoundServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		simultaneous: true,
+	}
+	result := runHeadless(context.Background(), m, 4)
+	if result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+
+	want := []string{"mistral:7b", "gemma3:4b", "mistral:7b", "gemma3:4b"}
+	if len(m.history) != len(want) {
+		t.Fatalf("Expected %d turns, got %d", len(want), len(m.history))
+	}
+	for i, model := range want {
+		if m.history[i].ModelName != model || m.history[i].Content != "Answer from "+model {
+			t.Errorf("Turn %d: expected %s's answer, got %+v", i, model, m.history[i])
+		}
+	}
+
+	// Neither model saw the other's answer within a round, and both saw
+	// the whole first round in the second
+	for _, req := range requests[:2] {
+		if strings.Contains(req.Prompt, "Answer from") {
+			t.Errorf("Expected a first-round prompt without answers, got:\n%s", req.Prompt)
+		}
+	}
+	for _, req := range requests[2:] {
+		if !strings.Contains(req.Prompt, "Answer from mistral:7b") || !strings.Contains(req.Prompt, "Answer from gemma3:4b") {
+			t.Errorf("Expected a second-round prompt with both answers, got:\n%s", req.Prompt)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// Event types sent to an OutputSink
+const (
+	eventChunk = "chunk" // Part of a turn as it streams in
+	eventTurn  = "turn"  // A completed turn
+	eventError = "error" // A turn that failed
+	eventDone  = "done"  // The end of the debate
+
+	eventPruned = "pruned" // A turn dropped from memory by -history-cap
+	eventMeta   = "meta"   // The debate's metadata, sent as it starts
+)
+
+// DebateEvent is one update from a running debate, serialized as JSON for
+// sinks that leave the process
+type DebateEvent struct {
+	Type       string      `json:"type"`
+	Topic      string      `json:"topic,omitempty"`
+	Model      string      `json:"model,omitempty"`
+	Speaker    string      `json:"speaker,omitempty"`
+	Content    string      `json:"content,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Flagged    bool        `json:"flagged,omitempty"`
+	OverLength bool        `json:"over_length,omitempty"`
+	Prompt     string      `json:"prompt,omitempty"`
+	Moderator  bool        `json:"moderator,omitempty"`
+	Thesis     bool        `json:"thesis,omitempty"`
+	Stance     string      `json:"stance,omitempty"`
+	Meta       *DebateMeta `json:"meta,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+// OutputSink receives events from a headless debate as they happen
+type OutputSink interface {
+	Send(event DebateEvent)
+}
+
+// chunkEvent reports a streamed chunk of the turn modelName is generating
+func (m *debateModel) chunkEvent(modelName, chunk string) DebateEvent {
+	return DebateEvent{
+		Type:      eventChunk,
+		Topic:     m.topic,
+		Model:     modelName,
+		Speaker:   m.displayName(modelName),
+		Content:   chunk,
+		Timestamp: time.Now(),
+	}
+}
+
+// turnEvent reports a completed turn
+func (m *debateModel) turnEvent(turn Turn) DebateEvent {
+	return newTurnEvent(m.topic, turn)
+}
+
+// newTurnEvent reports a completed turn of the debate on topic
+func newTurnEvent(topic string, turn Turn) DebateEvent {
+	return DebateEvent{
+		Type:       eventTurn,
+		Topic:      topic,
+		Model:      turn.ModelName,
+		Speaker:    turn.Speaker(),
+		Content:    turn.Content,
+		Error:      turn.Error,
+		Flagged:    turn.Flagged,
+		OverLength: turn.OverLength,
+		Prompt:     turn.Prompt,
+		Moderator:  turn.Moderator,
+		Thesis:     turn.Thesis,
+		Stance:     turn.Stance,
+		Timestamp:  turn.Timestamp,
+	}
+}
+
+// errorEvent reports that modelName's turn failed
+func (m *debateModel) errorEvent(modelName string, err error) DebateEvent {
+	return DebateEvent{
+		Type:      eventError,
+		Topic:     m.topic,
+		Model:     modelName,
+		Speaker:   m.displayName(modelName),
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	}
+}
+
+// prunedEvent reports a completed turn that is being dropped from memory
+func (m *debateModel) prunedEvent(turn Turn) DebateEvent {
+	event := m.turnEvent(turn)
+	event.Type = eventPruned
+	return event
+}
+
+// reportTurn sends the turn that just finished to the sink. A continuation
+// reports only the text it added, since the turn itself was already sent.
+func (m *debateModel) reportTurn(continued bool) {
+	if m.sink == nil || len(m.history) == 0 || (!continued && !m.turnStarted) {
+		return
+	}
+
+	last := m.history[len(m.history)-1]
+	if continued && m.continueFrom <= len(last.Content) {
+		last.Content = strings.TrimSpace(last.Content[m.continueFrom:])
+	}
+	m.sink.Send(m.turnEvent(last))
+}
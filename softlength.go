@@ -0,0 +1,42 @@
+package main
+
+import "strings"
+
+// overLengthNote marks a turn that ran past the soft length limit
+const overLengthNote = "[over length]"
+
+// overLength reports whether content runs past limit words. A limit of 0
+// or less never does.
+func overLength(content string, limit int) bool {
+	return limit > 0 && len(strings.Fields(content)) > limit
+}
+
+// applySoftLength marks the argument that just finished when it runs past
+// the soft length limit. Moderator interjections and theses are not held to
+// it.
+func (m *debateModel) applySoftLength() {
+	if m.softLength <= 0 || len(m.history) == 0 {
+		return
+	}
+
+	last := &m.history[len(m.history)-1]
+	if last.Moderator || last.Thesis {
+		return
+	}
+	last.OverLength = overLength(last.Content, m.softLength)
+}
+
+// lengthReminderDue reports whether modelName should be reminded of the
+// soft length limit, which is when its last argument ran over it
+func (m *debateModel) lengthReminderDue(modelName string) bool {
+	if !m.lengthReminder {
+		return false
+	}
+	for i := len(m.history) - 1; i >= 0; i-- {
+		turn := m.history[i]
+		if turn.ModelName == modelName && !turn.Moderator && !turn.Thesis && turn.Error == "" {
+			return turn.OverLength
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestOverLength(t *testing.T) {
+	tests := []struct {
+		content string
+		limit   int
+		want    bool
+	}{
+		{"Remote work wins.", 3, false},
+		{"Remote work wins, clearly.", 3, true},
+		{"  Remote\n\nwork   wins.  ", 3, false},
+		{"Any length at all is fine here.", 0, false},
+		{"", 1, false},
+	}
+	for _, tt := range tests {
+		if got := overLength(tt.content, tt.limit); got != tt.want {
+			t.Errorf("overLength(%q, %d) = %v, want %v", tt.content, tt.limit, got, tt.want)
+		}
+	}
+}
+
+// TestSoftLength_NotesAndReminds runs a debate where only phi3:mini runs
+// over the limit, so only its next prompt carries the reminder
+func TestSoftLength_NotesAndReminds(t *testing.T) {
+	m := &debateModel{
+		model1Name:     "phi3:mini",
+		model2Name:     "gemma3:4b",
+		ollamaClient:   NewOllamaClient("http://127.0.0.1:1"),
+		generator:      newFixtureGenerator(testFixture()),
+		maxTurns:       3,
+		initialTopic:   "Is remote work better?",
+		softLength:     2,
+		lengthReminder: true,
+	}
+	reminder := "ran over the 2-word limit"
+	var prompts []string
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+		if m.lastPrompt != "" && (len(prompts) == 0 || prompts[len(prompts)-1] != m.lastPrompt) {
+			prompts = append(prompts, m.lastPrompt)
+		}
+	}
+
+	if len(m.history) != 3 {
+		t.Fatalf("Expected 3 turns, got %+v", m.history)
+	}
+	if !m.history[0].OverLength || m.history[1].OverLength {
+		t.Errorf("Expected only the 3-word turn over length, got %+v", m.history[:2])
+	}
+	if len(prompts) != 3 {
+		t.Fatalf("Expected 3 prompts, got %q", prompts)
+	}
+	if strings.Contains(prompts[0], reminder) || strings.Contains(prompts[1], reminder) {
+		t.Errorf("Expected no reminder before phi3:mini ran over, got %q", prompts[:2])
+	}
+	if !strings.Contains(prompts[2], reminder) {
+		t.Errorf("Expected phi3:mini reminded to be concise, got:\n%s", prompts[2])
+	}
+
+	var b strings.Builder
+	if err := ExportText(m.topic, nil, m.history, timestampFormat{}, &b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Remote work wins. "+overLengthNote) {
+		t.Errorf("Expected the over-length note in the export, got:\n%s", b.String())
+	}
+	if strings.Contains(b.String(), "Offices win. "+overLengthNote) {
+		t.Errorf("Expected no note on a turn within the limit, got:\n%s", b.String())
+	}
+}
+
+// TestSoftLength_NoReminderByDefault verifies turns are only noted unless
+// the reminder is asked for
+func TestSoftLength_NoReminderByDefault(t *testing.T) {
+	m := &debateModel{
+		model1Name: "phi3:mini",
+		model2Name: "gemma3:4b",
+		topic:      "Is remote work better?",
+		softLength: 2,
+		history: []Turn{
+			{ModelName: "phi3:mini", Content: "Remote work wins.", OverLength: true},
+			{ModelName: "gemma3:4b", Content: "Offices win."},
+		},
+	}
+	if prompt := m.promptFor("phi3:mini", 3); strings.Contains(prompt, "word limit") {
+		t.Errorf("Expected no reminder without -soft-length-reminder, got:\n%s", prompt)
+	}
+
+	m.lengthReminder = true
+	if prompt := m.promptFor("phi3:mini", 3); !strings.Contains(prompt, "Keep this one to 2 words or fewer") {
+		t.Errorf("Expected a reminder, got:\n%s", prompt)
+	}
+	if prompt := m.promptFor("gemma3:4b", 3); strings.Contains(prompt, "word limit") {
+		t.Errorf("Expected no reminder for the model within the limit, got:\n%s", prompt)
+	}
+}
+
+func TestSoftLength_Headless(t *testing.T) {
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(testFixture()),
+		topic:        "Is remote work better?",
+		softLength:   2,
+	}
+	result := runHeadless(context.Background(), m, 2)
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	if len(m.history) != 2 || !m.history[0].OverLength || m.history[1].OverLength {
+		t.Errorf("Expected only the 3-word turn over length, got %+v", m.history)
+	}
+}
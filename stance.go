@@ -0,0 +1,32 @@
+package main
+
+// How turns are colored (see -color-by)
+const (
+	colorByModel  = "model"  // Each model keeps its own color
+	colorByStance = "stance" // The pro side takes model1's color and the con side model2's
+)
+
+// stance returns the side modelName defends, following -pro when set and
+// otherwise speaking order, in which the debate's opener is pro
+func (m *debateModel) stance(modelName string) string {
+	if m.proModel != "" {
+		if modelName == m.proModel {
+			return PositionPro
+		}
+		return PositionCon
+	}
+	if modelName == m.openingModel() {
+		return PositionPro
+	}
+	return PositionCon
+}
+
+// usesModel1Colors reports whether turn is drawn in model1's colors.
+// isModel1 says whether model1 made the turn, which decides unless turns are
+// colored by stance and the turn records one.
+func (m *debateModel) usesModel1Colors(turn Turn, isModel1 bool) bool {
+	if m.colorBy != colorByStance || turn.Stance == "" {
+		return isModel1
+	}
+	return turn.Stance == PositionPro
+}
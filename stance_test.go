@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestUsesModel1Colors(t *testing.T) {
+	pro := Turn{ModelName: "gemma3:4b", Stance: PositionPro}
+	con := Turn{ModelName: "phi3:mini", Stance: PositionCon}
+	moderator := Turn{ModelName: "llama3:8b", Moderator: true}
+
+	tests := []struct {
+		name     string
+		colorBy  string
+		turn     Turn
+		isModel1 bool
+		want     bool
+	}{
+		{"by model keeps model2's color for the pro side", colorByModel, pro, false, false},
+		{"by model keeps model1's color for the con side", colorByModel, con, true, true},
+		{"unset colors by model", "", pro, false, false},
+		{"by stance gives the pro side model1's color", colorByStance, pro, false, true},
+		{"by stance gives the con side model2's color", colorByStance, con, true, false},
+		{"by stance falls back to the model without a stance", colorByStance, moderator, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &debateModel{colorBy: tt.colorBy}
+			if got := m.usesModel1Colors(tt.turn, tt.isModel1); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestStance_RecordedOnTurns verifies turns remember the side their model
+// defended, following -pro over speaking order
+func TestStance_RecordedOnTurns(t *testing.T) {
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		proModel:     "gemma3:4b",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(testFixture()),
+		maxTurns:     2,
+		initialTopic: "Is remote work better?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	if len(m.history) != 2 {
+		t.Fatalf("Expected 2 turns, got %+v", m.history)
+	}
+	if m.history[0].Stance != PositionCon || m.history[1].Stance != PositionPro {
+		t.Errorf("Expected stances con then pro, got %q then %q", m.history[0].Stance, m.history[1].Stance)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// styleNeutral is the default debate style, which adds no instructions
+const styleNeutral = "neutral"
+
+// debateStyles maps each preset debate style to the instructions it adds to
+// the prompt, setting the tone of every turn
+var debateStyles = map[string]string{
+	"formal":   "Debate formally: structure your argument into clear points, support each one with evidence or reasoning, and keep a measured, professional tone.",
+	"casual":   "Debate casually: speak in a relaxed, conversational tone, as if talking with a friend, and keep it short and plain-spoken.",
+	"socratic": "Debate in the Socratic manner: advance your position mainly by asking your opponent probing questions that expose the assumptions and contradictions in their view.",
+}
+
+// ParseStyle validates a debate style name. An empty name selects the
+// neutral style.
+func ParseStyle(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == styleNeutral {
+		return styleNeutral, nil
+	}
+	if _, ok := debateStyles[name]; !ok {
+		return "", fmt.Errorf("unknown style '%s' (use neutral, formal, casual, or socratic)", name)
+	}
+	return name, nil
+}
+
+// styleInstruction returns the instructions for a debate style in the
+// catalog's language, falling back to English, or "" for the neutral style
+func (msgs promptMessages) styleInstruction(style string) string {
+	if instruction, ok := msgs.Styles[style]; ok {
+		return instruction
+	}
+	return debateStyles[style]
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// modelSwap is the replacement for one side's model being typed while the
+// debate is paused
+type modelSwap struct {
+	side      int // 0 for model1's side, 1 for model2's
+	input     textinput.Model
+	checking  bool   // Whether the typed model is being validated
+	err       string // Why the last model typed was rejected
+	fromError bool   // Whether the debate was paused by a failed turn
+}
+
+// modelSwapMsg reports whether name may take over side
+type modelSwapMsg struct {
+	side int
+	name string
+	err  error
+}
+
+// canSwap reports whether the debate can be paused to swap a model now.
+// Interjections, theses, continuations and simultaneous rounds finish first.
+func (m *debateModel) canSwap() bool {
+	if m.swap != nil || m.moderating || m.stating || m.continuing || m.round != nil || m.simultaneous {
+		return false
+	}
+	return m.state == stateDebating || m.state == stateError
+}
+
+// pauseForSwap stops the turn in progress and asks for the model to take
+// over the side whose turn it is; tab picks the other side
+func (m *debateModel) pauseForSwap() tea.Cmd {
+	fromError := m.state == stateError
+	m.cancelGeneration()
+	m.isGenerating = false
+	m.state = stateDebating
+
+	// Whatever the cancelled stream still sends is stale
+	m.stream = nil
+	m.streamErrs = nil
+
+	// Drop the unfinished turn; it is generated again on resuming
+	if m.turnStarted {
+		m.history = m.history[:len(m.history)-1]
+		m.turnStarted = false
+	}
+
+	input := textinput.New()
+	input.Placeholder = "installed model, such as llama3:8b"
+	input.Prompt = "New model: "
+	input.Focus()
+	m.swap = &modelSwap{side: m.currentTurn, input: input, fromError: fromError}
+	return textinput.Blink
+}
+
+// updateSwap handles a key typed while the swap prompt is open
+func (m *debateModel) updateSwap(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		// Leave both models as they were
+		return m.resumeAfterSwap(false)
+
+	case "tab":
+		m.swap.side = 1 - m.swap.side
+		m.swap.err = ""
+		return nil
+
+	case "enter":
+		name := strings.TrimSpace(m.swap.input.Value())
+		if name == "" {
+			m.swap.err = "Model name cannot be empty"
+			return nil
+		}
+		if m.swap.checking {
+			return nil
+		}
+		other := m.model2Name
+		if m.swap.side == 1 {
+			other = m.model1Name
+		}
+		if name == other {
+			m.swap.err = fmt.Sprintf("%s already argues the other side", name)
+			return nil
+		}
+		m.swap.checking = true
+		m.swap.err = ""
+		return m.validateSwap(m.swap.side, name)
+	}
+
+	var cmd tea.Cmd
+	m.swap.input, cmd = m.swap.input.Update(msg)
+	return cmd
+}
+
+// modelValidator checks that a model can generate responses
+type modelValidator interface {
+	ValidateModel(modelName string) error
+}
+
+// validateSwap checks in the background that the backend can run name: a
+// fixture must have responses left for it, and Ollama must have it
+// installed
+func (m *debateModel) validateSwap(side int, name string) tea.Cmd {
+	var validator modelValidator = m.ollamaClient
+	if v, ok := m.generator.(modelValidator); ok {
+		validator = v
+	}
+	return func() tea.Msg {
+		return modelSwapMsg{side: side, name: name, err: validator.ValidateModel(name)}
+	}
+}
+
+// applySwap puts the validated model in place, or shows why it was
+// rejected, and resumes the debate once a model is swapped in
+func (m *debateModel) applySwap(msg modelSwapMsg) tea.Cmd {
+	if m.swap == nil || !m.swap.checking {
+		return nil
+	}
+	m.swap.checking = false
+	if msg.err != nil {
+		m.swap.err = msg.err.Error()
+		return nil
+	}
+	m.swapModel(msg.side, msg.name)
+	return m.resumeAfterSwap(true)
+}
+
+// swapModel hands side over to name for the rest of the debate. Turns
+// already in the history stay attributed to the model that made them, and
+// the side's alias, which named that model, is dropped.
+func (m *debateModel) swapModel(side int, name string) {
+	old := &m.model1Name
+	alias := &m.model1Alias
+	if side == 1 {
+		old, alias = &m.model2Name, &m.model2Alias
+	}
+	if *old == name {
+		return
+	}
+
+	if m.swappedOut == nil {
+		m.swappedOut = make(map[string]int)
+	}
+	m.swappedOut[*old] = side
+	delete(m.swappedOut, name)
+	if m.proModel == *old {
+		m.proModel = name
+	}
+	*old = name
+	*alias = ""
+}
+
+// resumeAfterSwap closes the swap prompt and generates the paused turn
+// again. Left without a swap, a debate paused by a failed turn returns to
+// the error instead.
+func (m *debateModel) resumeAfterSwap(swapped bool) tea.Cmd {
+	fromError := m.swap.fromError
+	m.swap = nil
+	if fromError && !swapped {
+		m.state = stateError
+		return nil
+	}
+	return m.retryTurn()
+}
+
+// onModel1Side reports whether modelName argues, or argued before it was
+// swapped out, on model1's side
+func (m *debateModel) onModel1Side(modelName string) bool {
+	switch modelName {
+	case m.model1Name:
+		return true
+	case m.model2Name:
+		return false
+	}
+	side, ok := m.swappedOut[modelName]
+	return ok && side == 0
+}
+
+// renderSwapPrompt renders the prompt for the model to swap in
+func (m *debateModel) renderSwapPrompt() string {
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("⏸ Paused • Swap %s for:", m.sideName(m.swap.side))))
+	b.WriteString("\n")
+	b.WriteString(m.swap.input.View())
+	b.WriteString("\n")
+	switch {
+	case m.swap.checking:
+		b.WriteString(subtleStyle.Render("Checking the model is available..."))
+		b.WriteString("\n")
+	case m.swap.err != "":
+		b.WriteString(errorStyle.Render("⚠️  " + m.swap.err))
+		b.WriteString("\n")
+	}
+	b.WriteString(subtleStyle.Render("Enter to swap • Tab for the other side • Esc to resume unchanged"))
+	return b.String()
+}
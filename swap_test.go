@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagsServer serves an Ollama model list holding models
+func tagsServer(t *testing.T, models ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list := make([]map[string]string, 0, len(models))
+		for _, name := range models {
+			list = append(list, map[string]string{"name": name})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"models": list})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestSwapModel_TakesOverSide verifies a swap while a fixture plays is
+// checked against the fixture, with no server to ask
+func TestSwapModel_TakesOverSide(t *testing.T) {
+	fixture := &Fixture{Responses: []FixtureResponse{
+		{Model: "phi3:mini", Chunks: []FixtureChunk{{Text: "Remote work wins."}}},
+		{Model: "gemma3:4b", Chunks: []FixtureChunk{{Text: "Offices ", DelayMS: 10}, {Text: "win."}}},
+		{Model: "llama3:8b", Chunks: []FixtureChunk{{Text: "Hybrid wins."}}},
+	}}
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		model2Alias:  "Gemma",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(fixture),
+		maxTurns:     2,
+		initialTopic: "Is remote work better?",
+	}
+
+	// Run the opening, then pause while gemma3:4b is answering it
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50 && !(len(m.history) == 1 && m.currentTurn == 1); i++ {
+		_, cmd = m.Update(cmd())
+	}
+	pending := cmd
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if m.swap == nil || m.swap.side != 1 || m.isGenerating {
+		t.Fatalf("Expected the debate paused to swap model2's side, got swap %+v generating %v", m.swap, m.isGenerating)
+	}
+
+	// What the cancelled generation still sends is ignored
+	if pending != nil {
+		m.Update(pending())
+	}
+	if len(m.history) != 1 || m.swap == nil {
+		t.Fatalf("Expected the cancelled turn to leave no trace, got %+v", m.history)
+	}
+
+	// A model the fixture has no responses for is rejected and the prompt
+	// stays open
+	m.swap.input.SetValue("nope:1b")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(cmd())
+	if m.swap == nil || !strings.Contains(m.swap.err, "no more recorded responses") || m.model2Name != "gemma3:4b" {
+		t.Fatalf("Expected nope:1b rejected, got swap %+v and model2 %s", m.swap, m.model2Name)
+	}
+
+	// Nor may one side take the other's model
+	m.swap.input.SetValue("phi3:mini")
+	if _, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.swap == nil || !strings.Contains(m.swap.err, "other side") {
+		t.Fatalf("Expected phi3:mini rejected for model2's side, got swap %+v", m.swap)
+	}
+
+	m.swap.input.SetValue("llama3:8b")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = m.Update(cmd())
+	if m.swap != nil || m.model1Name != "phi3:mini" || m.model2Name != "llama3:8b" {
+		t.Fatalf("Expected llama3:8b to take over model2's side, got %s vs %s", m.model1Name, m.model2Name)
+	}
+	if m.model2Alias != "" || !m.isGenerating {
+		t.Errorf("Expected the alias dropped and the debate resumed, got alias %q generating %v", m.model2Alias, m.isGenerating)
+	}
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	if len(m.history) != 2 || m.history[0].ModelName != "phi3:mini" || m.history[1].ModelName != "llama3:8b" {
+		t.Fatalf("Expected the next turn from llama3:8b, got %+v", m.history)
+	}
+	if !strings.Contains(m.lastPrompt, "llama3:8b") {
+		t.Errorf("Expected llama3:8b addressed in its prompt, got:\n%s", m.lastPrompt)
+	}
+}
+
+// TestSwapModel_ValidatesWithOllama verifies a swap is checked against
+// the models Ollama has installed when no fixture plays
+func TestSwapModel_ValidatesWithOllama(t *testing.T) {
+	server := tagsServer(t, "phi3:mini", "gemma3:4b", "llama3:8b")
+	m := &debateModel{model1Name: "phi3:mini", model2Name: "gemma3:4b", ollamaClient: NewOllamaClient(server.URL)}
+
+	if msg := m.validateSwap(1, "nope:1b")().(modelSwapMsg); msg.err == nil || !strings.Contains(msg.err.Error(), "not found") {
+		t.Errorf("Expected nope:1b rejected as not installed, got %v", msg.err)
+	}
+	if msg := m.validateSwap(1, "llama3:8b")().(modelSwapMsg); msg.err != nil {
+		t.Errorf("Expected llama3:8b accepted, got %v", msg.err)
+	}
+}
+
+func TestSwapModel_KeepsAttribution(t *testing.T) {
+	m := &debateModel{
+		model1Name: "phi3:mini",
+		model2Name: "gemma3:4b",
+		proModel:   "phi3:mini",
+		history: []Turn{
+			{ModelName: "phi3:mini", Content: "Remote work wins."},
+			{ModelName: "gemma3:4b", Content: "Offices win."},
+		},
+	}
+
+	m.swapModel(0, "llama3:8b")
+
+	if m.model1Name != "llama3:8b" || m.model2Name != "gemma3:4b" {
+		t.Fatalf("Expected only model1's side swapped, got %s vs %s", m.model1Name, m.model2Name)
+	}
+	if m.history[0].ModelName != "phi3:mini" {
+		t.Errorf("Expected the earlier turn to keep its model, got %s", m.history[0].ModelName)
+	}
+	if !m.onModel1Side("phi3:mini") || !m.onModel1Side("llama3:8b") || m.onModel1Side("gemma3:4b") {
+		t.Errorf("Expected phi3:mini's turns to stay on model1's side")
+	}
+	if m.stance("llama3:8b") != PositionPro {
+		t.Errorf("Expected llama3:8b to argue in favor like phi3:mini, got %s", m.stance("llama3:8b"))
+	}
+	if prompt := m.promptFor("llama3:8b", 2); !strings.Contains(prompt, promptCatalog["en"].AssignedPro) {
+		t.Errorf("Expected the newcomer told its side, got:\n%s", prompt)
+	}
+}
+
+// TestSwapModel_ClearedOnRestart verifies a new debate forgets the models
+// swapped out of the last one
+func TestSwapModel_ClearedOnRestart(t *testing.T) {
+	m := &debateModel{model1Name: "phi3:mini", model2Name: "gemma3:4b", textInput: textinput.New()}
+	m.swapModel(0, "llama3:8b")
+
+	m.restart()
+
+	if len(m.swappedOut) != 0 || m.onModel1Side("phi3:mini") {
+		t.Errorf("Expected phi3:mini forgotten after restarting, got %v", m.swappedOut)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is a named color scheme for the view and HTML exports
+type theme struct {
+	Name   string
+	Model1 lipgloss.Color
+	Model2 lipgloss.Color
+	Header lipgloss.Color
+	Error  lipgloss.Color
+	Subtle lipgloss.Color
+}
+
+// themes lists the available color schemes, the default first, in the order
+// the theme key cycles through them
+var themes = []theme{
+	{
+		Name:   "default",
+		Model1: lipgloss.Color("#00BFFF"), // Deep Sky Blue
+		Model2: lipgloss.Color("#32CD32"), // Lime Green
+		Header: lipgloss.Color("#FFD700"), // Gold
+		Error:  lipgloss.Color("#FF6347"), // Tomato Red
+		Subtle: lipgloss.Color("#808080"), // Gray
+	},
+	{
+		Name:   "light",
+		Model1: lipgloss.Color("#0050A0"), // Dark Blue
+		Model2: lipgloss.Color("#1E7B1E"), // Forest Green
+		Header: lipgloss.Color("#8B5A00"), // Dark Amber
+		Error:  lipgloss.Color("#B22222"), // Firebrick
+		Subtle: lipgloss.Color("#5A5A5A"), // Dark Gray
+	},
+	{
+		Name:   "high-contrast",
+		Model1: lipgloss.Color("#00FFFF"), // Cyan
+		Model2: lipgloss.Color("#FFFF00"), // Yellow
+		Header: lipgloss.Color("#FFFFFF"), // White
+		Error:  lipgloss.Color("#FF0000"), // Red
+		Subtle: lipgloss.Color("#C0C0C0"), // Silver
+	},
+}
+
+func init() {
+	applyTheme(themes[0])
+}
+
+// ParseTheme returns the index of the theme with the given name
+func ParseTheme(name string) (int, error) {
+	for i, t := range themes {
+		if strings.EqualFold(name, t.Name) {
+			return i, nil
+		}
+	}
+
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.Name
+	}
+	return 0, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// applyTheme rebuilds the colors and styles used for rendering from t
+func applyTheme(t theme) {
+	model1Color = t.Model1
+	model2Color = t.Model2
+	headerColor = t.Header
+	errorColor = t.Error
+	subtleColor = t.Subtle
+
+	model1Style = lipgloss.NewStyle().
+		Foreground(model1Color).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(model1Color).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	model1LabelStyle = lipgloss.NewStyle().
+		Foreground(model1Color).
+		Bold(true)
+
+	model2Style = lipgloss.NewStyle().
+		Foreground(model2Color).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(model2Color).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	model2LabelStyle = lipgloss.NewStyle().
+		Foreground(model2Color).
+		Bold(true)
+
+	moderatorStyle = lipgloss.NewStyle().
+		Foreground(headerColor).
+		Italic(true).
+		BorderStyle(lipgloss.DoubleBorder()).
+		BorderForeground(headerColor).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	moderatorLabelStyle = lipgloss.NewStyle().
+		Foreground(headerColor).
+		Bold(true)
+
+	headerStyle = lipgloss.NewStyle().
+		Foreground(headerColor).
+		Bold(true).
+		Padding(1, 0)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(errorColor).
+		Bold(true)
+
+	subtleStyle = lipgloss.NewStyle().
+		Foreground(subtleColor).
+		Italic(true)
+
+	timestampStyle = lipgloss.NewStyle().
+		Foreground(subtleColor).
+		Italic(true)
+
+	badgeStyle = lipgloss.NewStyle().
+		Foreground(errorColor).
+		Bold(true)
+
+	promptPaneStyle = lipgloss.NewStyle().
+		Foreground(subtleColor).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(subtleColor).
+		Padding(0, 1)
+
+	if renderMode == renderPlain {
+		plainStyles()
+	}
+}
+
+// cycleTheme switches to the next theme, wrapping around after the last,
+// and drops cached renderings so the view redraws in the new colors
+func (m *debateModel) cycleTheme() {
+	m.themeIndex = (m.themeIndex + 1) % len(themes)
+	applyTheme(themes[m.themeIndex])
+	m.turnCache = nil
+}
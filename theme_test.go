@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+// TestCycleTheme verifies the theme key advances through the themes,
+// restyling the view, and wraps around to the first
+func TestCycleTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme(themes[0]) })
+
+	m := &debateModel{turnCache: []renderedTurn{{output: "stale"}}}
+	for i := 1; i <= len(themes); i++ {
+		m.cycleTheme()
+
+		want := themes[i%len(themes)]
+		if m.themeIndex != i%len(themes) {
+			t.Fatalf("Cycle %d: expected theme index %d, got %d", i, i%len(themes), m.themeIndex)
+		}
+		if model1Color != want.Model1 || model1Style.GetForeground() != want.Model1 || subtleStyle.GetForeground() != want.Subtle {
+			t.Errorf("Cycle %d: expected the %s theme's colors to be applied", i, want.Name)
+		}
+		if m.turnCache != nil {
+			t.Errorf("Cycle %d: expected the render cache to be dropped", i)
+		}
+	}
+	if m.themeIndex != 0 {
+		t.Errorf("Expected cycling to wrap around to the first theme, got %d", m.themeIndex)
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	if i, err := ParseTheme("High-Contrast"); err != nil || themes[i].Name != "high-contrast" {
+		t.Errorf("ParseTheme(High-Contrast) = %d, %v", i, err)
+	}
+	if _, err := ParseTheme("neon"); err == nil {
+		t.Error("Expected an error for an unknown theme")
+	}
+}
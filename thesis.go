@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// thesisMaxTokens caps the length of a thesis generation, which only needs
+// room for one sentence
+const thesisMaxTokens = 60
+
+// thesisOptions are sent with thesis generations in place of the usual
+// token budget
+var thesisOptions = map[string]interface{}{"num_predict": thesisMaxTokens}
+
+// thesisDue reports whether modelName should state its thesis before its
+// next turn: when theses are on and it has neither argued nor attempted a
+// thesis yet
+func (m *debateModel) thesisDue(modelName string) bool {
+	return m.theses && !m.simultaneous && !m.thesisTried[modelName] && !m.hasSpoken(modelName)
+}
+
+// thesisPrompt builds the prompt asking modelName for its thesis
+func (m *debateModel) thesisPrompt(modelName string) string {
+	return BuildThesisPrompt(m.topic, m.promptHistory(), m.displayName(modelName), m.stance(modelName))
+}
+
+// markThesisTried notes that modelName has had its chance at a thesis, so
+// a failed thesis is not attempted again
+func (m *debateModel) markThesisTried(modelName string) {
+	if m.thesisTried == nil {
+		m.thesisTried = make(map[string]bool)
+	}
+	m.thesisTried[modelName] = true
+}
+
+// thesisTurn returns a new thesis turn of modelName holding content
+func (m *debateModel) thesisTurn(modelName, content string) Turn {
+	return Turn{
+		ModelName:   modelName,
+		DisplayName: m.displayName(modelName),
+		Content:     content,
+		Timestamp:   time.Now(),
+		Prompt:      m.storedPrompt(m.lastPrompt),
+		Thesis:      true,
+		Stance:      m.stance(modelName),
+	}
+}
+
+// finishThesis cuts a thesis down to its first sentence
+func finishThesis(content string) string {
+	content, _ = truncateSentences(strings.TrimSpace(content), 1)
+	return content
+}
+
+// generateThesis asks the model whose turn it is for its thesis. Its
+// argument follows once the thesis completes.
+func (m *debateModel) generateThesis() tea.Cmd {
+	modelName := m.getNextModel()
+	m.markThesisTried(modelName)
+	m.stating = true
+	return m.startGeneration(modelName, m.thesisPrompt(modelName))
+}
+
+// completeThesis keeps the finished thesis and starts the same model's
+// argument
+func (m *debateModel) completeThesis() tea.Cmd {
+	if m.turnStarted {
+		last := &m.history[len(m.history)-1]
+		last.Content = finishThesis(last.Content)
+	}
+	m.applyContentFilter()
+	m.reportTurn(false)
+	m.stating = false
+	m.pruneHistory()
+
+	m.isGenerating = true
+	return m.generateResponse()
+}
+
+// skipThesis drops a failed thesis, noting why, and lets the model go
+// straight to its argument
+func (m *debateModel) skipThesis(err error) tea.Cmd {
+	m.cancelGeneration()
+	m.stating = false
+	if m.turnStarted {
+		m.history = m.history[:len(m.history)-1]
+		m.turnStarted = false
+	}
+	m.errorMsg = fmt.Sprintf("Thesis skipped: %v", err)
+
+	m.isGenerating = true
+	return m.generateResponse()
+}
+
+// stateThesis adds modelName's thesis to the history of a headless debate
+// and returns it. A failed thesis is reported and skipped.
+func (m *debateModel) stateThesis(ctx context.Context, modelName string) (Turn, bool) {
+	m.markThesisTried(modelName)
+	prompt := m.thesisPrompt(modelName)
+	m.lastPrompt = prompt
+	content, err := collectResponseWithOptions(ctx, m.backend(), modelName, prompt, thesisOptions, nil)
+	if err != nil {
+		if m.sink != nil {
+			m.sink.Send(m.errorEvent(modelName, err))
+		}
+		return Turn{}, false
+	}
+
+	turn := m.thesisTurn(modelName, finishThesis(content))
+	if m.contentFilter != nil {
+		turn.Content, turn.Flagged = m.contentFilter(turn.Content)
+	}
+	m.history = append(m.history, turn)
+	if m.sink != nil {
+		m.sink.Send(m.turnEvent(turn))
+	}
+	m.pruneHistory()
+	return turn, true
+}
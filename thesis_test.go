@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBuildThesisPrompt(t *testing.T) {
+	prompt := BuildThesisPrompt("Should homework be banned?", nil, "Optimist", PositionPro)
+	for _, want := range []string{"Should homework be banned?", "You are Optimist.", "in favor of the topic", "exactly one sentence"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected the prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "Previous discussion") {
+		t.Errorf("Expected no history section before anyone has spoken, got:\n%s", prompt)
+	}
+
+	history := []Turn{{ModelName: "mistral:7b", Content: "Homework builds discipline."}}
+	prompt = BuildThesisPrompt("Should homework be banned?", history, "Skeptic", PositionCon)
+	for _, want := range []string{"against the topic", "[mistral:7b]: Homework builds discipline."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected the prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+}
+
+// newThesisServer answers thesis prompts with two sentences and arguments
+// with "ok", recording every request
+func newThesisServer(t *testing.T, requests *[]GenerateRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+		response := "ok"
+		if strings.Contains(req.Prompt, "state your thesis") {
+			response = " Homework should go. It wastes evenings."
+		}
+		json.NewEncoder(w).Encode(GenerateResponse{Response: response, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// checkTheses verifies each model's thesis comes right before its first
+// argument, is cut to one sentence, and is generated with a short budget
+func checkTheses(t *testing.T, history []Turn, requests []GenerateRequest) {
+	t.Helper()
+	wantModels := []string{"mistral:7b", "mistral:7b", "gemma3:4b", "gemma3:4b", "mistral:7b"}
+	wantThesis := []bool{true, false, true, false, false}
+	if len(history) < len(wantModels) {
+		t.Fatalf("Expected at least %d turns, got %+v", len(wantModels), history)
+	}
+	for i := range wantModels {
+		turn := history[i]
+		if turn.ModelName != wantModels[i] || turn.Thesis != wantThesis[i] {
+			t.Errorf("Turn %d: expected %s (thesis %v), got %s (thesis %v)", i, wantModels[i], wantThesis[i], turn.ModelName, turn.Thesis)
+		}
+		if turn.Thesis && turn.Content != "Homework should go." {
+			t.Errorf("Turn %d: expected the thesis cut to one sentence, got %q", i, turn.Content)
+		}
+	}
+
+	for i, req := range requests {
+		_, limited := req.Options["num_predict"]
+		if thesis := strings.Contains(req.Prompt, "state your thesis"); thesis != limited {
+			t.Errorf("Request %d: expected num_predict only on thesis requests, got %v", i, req.Options)
+		}
+	}
+
+	// The argument after a thesis is still the model's first and sees it
+	if !strings.Contains(requests[1].Prompt, "[mistral:7b]: Homework should go.") {
+		t.Errorf("Expected the thesis in the argument's context, got:\n%s", requests[1].Prompt)
+	}
+	if !strings.Contains(requests[1].Prompt, "Provide your opening argument") {
+		t.Errorf("Expected the argument after a thesis to be an opening, got:\n%s", requests[1].Prompt)
+	}
+}
+
+// TestTheses_Interactive verifies theses are recorded as distinct turns in
+// the interactive debate
+func TestTheses_Interactive(t *testing.T) {
+	var requests []GenerateRequest
+	server := newThesisServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		theses:       true,
+		maxTurns:     3, // Theses do not count
+		initialTopic: "Should homework be banned?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	if len(m.history) != 5 {
+		t.Fatalf("Expected 5 turns, got %+v", m.history)
+	}
+	checkTheses(t, m.history, requests)
+}
+
+// TestTheses_Headless verifies headless debates state theses the same way
+// and count them apart from arguments
+func TestTheses_Headless(t *testing.T) {
+	var requests []GenerateRequest
+	server := newThesisServer(t, &requests)
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Should homework be banned?",
+		theses:       true,
+	}
+	result := runHeadless(context.Background(), m, 3)
+	if result.Err != nil {
+		t.Fatalf("runHeadless failed: %v", result.Err)
+	}
+
+	checkTheses(t, result.Turns, requests)
+	if result.Stats.Theses != 2 || result.Stats.Arguments != 3 {
+		t.Errorf("Expected 2 theses and 3 arguments, got %+v", result.Stats)
+	}
+}
+
+// TestTheses_FailedThesisSkipped verifies a failed thesis is dropped and
+// the model argues without one
+func TestTheses_FailedThesisSkipped(t *testing.T) {
+	var requests []GenerateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		if strings.Contains(req.Prompt, "state your thesis") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "ok", Done: true})
+	}))
+	defer server.Close()
+
+	m := &debateModel{
+		model1Name:   "mistral:7b",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		theses:       true,
+		maxTurns:     2,
+		initialTopic: "Should homework be banned?",
+	}
+	cmd := m.Init()
+	for i := 0; cmd != nil && i < 50; i++ {
+		_, cmd = m.Update(cmd())
+	}
+
+	if len(m.history) != 2 || m.history[0].Thesis || m.history[1].Thesis {
+		t.Fatalf("Expected two arguments without theses, got %+v", m.history)
+	}
+	if len(requests) != 4 {
+		t.Errorf("Expected each thesis to be attempted once, got %d requests", len(requests))
+	}
+}
+
+func TestTheses_RecordedInTranscript(t *testing.T) {
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: "Homework should go.", Thesis: true},
+		{ModelName: "mistral:7b", Content: "Because evenings matter."},
+	}
+
+	var b strings.Builder
+	if err := ExportJSONLines("Topic", nil, history, &b); err != nil {
+		t.Fatal(err)
+	}
+	_, loaded, err := ParseTranscript(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded[0].Thesis || loaded[1].Thesis {
+		t.Errorf("Expected the thesis flag to survive a round trip, got %+v", loaded)
+	}
+
+	b.Reset()
+	ExportText("Topic", nil, history, timestampFormat{}, &b)
+	if !strings.Contains(b.String(), "mistral:7b (thesis):") {
+		t.Errorf("Expected the thesis marked in text exports, got:\n%s", b.String())
+	}
+
+	if out := formatTurn(history[0], true, 80, defaultMinContentWidth, "", timestampFormat{}); !strings.Contains(out, "📌 thesis") {
+		t.Errorf("Expected the thesis badge in the view, got:\n%s", out)
+	}
+}
+
+// TestFormatTurn_ThesisStyleDoesNotLeak verifies that drawing a thesis
+// leaves the side's style as it was for the turns after it
+func TestFormatTurn_ThesisStyleDoesNotLeak(t *testing.T) {
+	thesis := Turn{ModelName: "phi3:mini", Content: "Homework should go.", Thesis: true}
+	argument := Turn{ModelName: "phi3:mini", Content: "It takes time from sleep."}
+
+	if rendered := formatTurn(thesis, true, 80, defaultMinContentWidth, "", timestampFormat{}); !strings.Contains(rendered, "┏") {
+		t.Fatalf("Expected the thesis in a thick border, got:\n%s", rendered)
+	}
+	rendered := formatTurn(argument, true, 80, defaultMinContentWidth, "", timestampFormat{})
+	if strings.Contains(rendered, "┏") || !strings.Contains(rendered, "╭") {
+		t.Errorf("Expected the next argument in the usual rounded border, got:\n%s", rendered)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// thinkingInterval is how often the thinking indicator advances a frame
+const thinkingInterval = 150 * time.Millisecond
+
+// defaultThinkingFrames is the name of the built-in frame set used by default
+const defaultThinkingFrames = "spinner"
+
+// thinkingFrameSets are the built-in thinking indicator animations
+var thinkingFrameSets = map[string][]string{
+	"thought": {"💭"},
+	"spinner": {"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+	"dots":    {"·  ", "·· ", "···", " ··", "  ·", "   "},
+	"moon":    {"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"},
+}
+
+// thinkingTickMsg is sent when the thinking indicator should advance
+type thinkingTickMsg struct{}
+
+// thinkingIndicator animates the "is thinking" line while a model generates
+type thinkingIndicator struct {
+	frames  []string
+	frame   int
+	ticking bool // Whether a tick is already scheduled
+}
+
+// current returns the frame to show, or the static 💭 when no frames are set
+func (t *thinkingIndicator) current() string {
+	if len(t.frames) == 0 {
+		return "💭"
+	}
+	return t.frames[t.frame]
+}
+
+// advance moves to the next frame, wrapping around at the end of the set
+func (t *thinkingIndicator) advance() {
+	if len(t.frames) > 0 {
+		t.frame = (t.frame + 1) % len(t.frames)
+	}
+}
+
+// animated reports whether there is more than one frame to cycle through
+func (t *thinkingIndicator) animated() bool {
+	return len(t.frames) > 1
+}
+
+// thinkingTick returns a command that advances the indicator
+func thinkingTick() tea.Cmd {
+	return tea.Tick(thinkingInterval, func(time.Time) tea.Msg {
+		return thinkingTickMsg{}
+	})
+}
+
+// parseThinkingFrames resolves a built-in frame set name, or a
+// comma-separated list of custom frames
+func parseThinkingFrames(spec string) ([]string, error) {
+	if frames, ok := thinkingFrameSets[spec]; ok {
+		return frames, nil
+	}
+	if strings.Contains(spec, ",") {
+		var frames []string
+		for _, frame := range strings.Split(spec, ",") {
+			if frame != "" {
+				frames = append(frames, frame)
+			}
+		}
+		if len(frames) > 0 {
+			return frames, nil
+		}
+	}
+
+	names := make([]string, 0, len(thinkingFrameSets))
+	for name := range thinkingFrameSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown frame set '%s' (built-in: %s, or a comma-separated list)", spec, strings.Join(names, ", "))
+}
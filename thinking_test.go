@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestThinkingIndicator_AdvancesAndWraps(t *testing.T) {
+	ti := thinkingIndicator{frames: []string{"a", "b", "c"}}
+
+	want := []string{"a", "b", "c", "a", "b"}
+	for i, frame := range want {
+		if got := ti.current(); got != frame {
+			t.Errorf("tick %d: expected frame %q, got %q", i, frame, got)
+		}
+		ti.advance()
+	}
+}
+
+func TestThinkingIndicator_TickOnlyWhileGenerating(t *testing.T) {
+	m := &debateModel{
+		state:        stateDebating,
+		isGenerating: true,
+		thinking:     thinkingIndicator{frames: thinkingFrameSets["moon"]},
+	}
+
+	m.Update(thinkingTickMsg{})
+	if m.thinking.frame != 1 {
+		t.Errorf("Expected the frame to advance while generating, got %d", m.thinking.frame)
+	}
+
+	m.isGenerating = false
+	_, cmd := m.Update(thinkingTickMsg{})
+	if cmd != nil || m.thinking.ticking {
+		t.Errorf("Expected ticking to stop once generation ends")
+	}
+}
+
+func TestParseThinkingFrames(t *testing.T) {
+	if frames, err := parseThinkingFrames("dots"); err != nil || len(frames) != len(thinkingFrameSets["dots"]) {
+		t.Errorf("Expected built-in dots frame set, got %v, %v", frames, err)
+	}
+	if frames, err := parseThinkingFrames("🤔,🧠"); err != nil || len(frames) != 2 {
+		t.Errorf("Expected 2 custom frames, got %v, %v", frames, err)
+	}
+	if _, err := parseThinkingFrames("nope"); err == nil {
+		t.Errorf("Expected error for unknown frame set")
+	}
+}
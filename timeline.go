@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// timelineSegmentWidths splits width cells between turns in proportion to
+// their lengths, so the widths always add up to width. Cells left over after
+// rounding down go to the turns with the largest remainders, earlier turns
+// first on ties. When width allows, a turn too short for a cell of its own
+// takes one from the widest segment so that every turn stays visible.
+func timelineSegmentWidths(lengths []int, width int) []int {
+	n := len(lengths)
+	widths := make([]int, n)
+	if n == 0 || width <= 0 {
+		return widths
+	}
+
+	total := 0
+	for _, length := range lengths {
+		total += max(length, 0)
+	}
+
+	remainders := make([]float64, n)
+	assigned := 0
+	for i, length := range lengths {
+		share := float64(width) / float64(n)
+		if total > 0 {
+			share = float64(width) * float64(max(length, 0)) / float64(total)
+		}
+		widths[i] = int(share)
+		remainders[i] = share - float64(widths[i])
+		assigned += widths[i]
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := 0; i < width-assigned; i++ {
+		widths[order[i%n]]++
+	}
+
+	// Keep every turn visible when there is room for all of them
+	if width >= n {
+		for i := range widths {
+			if widths[i] > 0 {
+				continue
+			}
+			widest := 0
+			for j := range widths {
+				if widths[j] > widths[widest] {
+					widest = j
+				}
+			}
+			widths[widest]--
+			widths[i]++
+		}
+	}
+
+	return widths
+}
+
+// renderTimeline draws the debate as a bar of colored segments, one per
+// turn in the model's color and sized by the turn's length, with a legend
+func (m *debateModel) renderTimeline(history []Turn, width int) string {
+	if len(history) == 0 {
+		return ""
+	}
+
+	lengths := make([]int, len(history))
+	chars := make(map[string]int)
+	for i, turn := range history {
+		lengths[i] = utf8.RuneCountInString(turn.Content)
+		if !turn.Moderator {
+			chars[turn.ModelName] += lengths[i]
+		}
+	}
+
+	var bar strings.Builder
+	for i, w := range timelineSegmentWidths(lengths, width) {
+		if w == 0 {
+			continue
+		}
+		if renderMode == renderPlain {
+			bar.WriteString(strings.Repeat(m.timelineFill(history[i]), w))
+			continue
+		}
+		bar.WriteString(lipgloss.NewStyle().Background(m.timelineColor(history[i])).Render(strings.Repeat(" ", w)))
+	}
+
+	mark1, mark2 := "■", "■"
+	if renderMode == renderPlain {
+		mark1 = m.timelineFill(Turn{ModelName: m.model1Name})
+		mark2 = m.timelineFill(Turn{ModelName: m.model2Name})
+	}
+	legend := fmt.Sprintf("%s %s (%d chars)   %s %s (%d chars)",
+		model1LabelStyle.Render(mark1), m.sideName(0), chars[m.model1Name],
+		model2LabelStyle.Render(mark2), m.sideName(1), chars[m.model2Name])
+
+	return bar.String() + "\n" + subtleStyle.Render("Timeline: ") + legend
+}
+
+// timelineColor returns the segment color for a turn
+func (m *debateModel) timelineColor(turn Turn) lipgloss.Color {
+	switch {
+	case turn.Error != "":
+		return errorColor
+	case turn.Moderator:
+		return headerColor
+	case m.onModel1Side(turn.ModelName):
+		return model1Color
+	default:
+		return model2Color
+	}
+}
+
+// timelineFill returns the character a turn's segment is drawn with when
+// rendering without colors
+func (m *debateModel) timelineFill(turn Turn) string {
+	switch {
+	case turn.Error != "":
+		return "!"
+	case turn.Moderator:
+		return "~"
+	case m.onModel1Side(turn.ModelName):
+		return "#"
+	default:
+		return "="
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTimelineSegmentWidths(t *testing.T) {
+	tests := []struct {
+		name    string
+		lengths []int
+		width   int
+		want    []int
+	}{
+		{"proportional", []int{100, 300}, 40, []int{10, 30}},
+		{"equal", []int{50, 50, 50, 50}, 20, []int{5, 5, 5, 5}},
+		{"remainder to largest fraction", []int{1, 1, 1}, 10, []int{4, 3, 3}},
+		{"minimum one cell", []int{1, 1000}, 10, []int{1, 9}},
+		{"empty turns share evenly", []int{0, 0}, 7, []int{4, 3}},
+		{"narrower than turns", []int{10, 10, 10, 10}, 2, []int{1, 1, 0, 0}},
+		{"no width", []int{5}, 0, []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := timelineSegmentWidths(tt.lengths, tt.width)
+			sum := 0
+			for i := range got {
+				sum += got[i]
+				if got[i] != tt.want[i] {
+					t.Errorf("timelineSegmentWidths(%v, %d) = %v, want %v", tt.lengths, tt.width, got, tt.want)
+				}
+			}
+			if tt.width > 0 && sum != tt.width {
+				t.Errorf("Expected widths to add up to %d, got %d", tt.width, sum)
+			}
+		})
+	}
+}
+
+// TestRenderTimeline_FillsWidth verifies the bar spans the requested width
+// and the legend totals each model's characters
+func TestRenderTimeline_FillsWidth(t *testing.T) {
+	m := &debateModel{model1Name: "mistral:7b", model2Name: "gemma3:4b", model2Alias: "Skeptic"}
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: strings.Repeat("a", 30)},
+		{ModelName: "gemma3:4b", Content: strings.Repeat("b", 10)},
+		{ModelName: "mistral:7b", Content: strings.Repeat("c", 20)},
+	}
+
+	bar, legend, _ := strings.Cut(m.renderTimeline(history, 60), "\n")
+	if got := lipgloss.Width(bar); got != 60 {
+		t.Errorf("Expected a bar 60 cells wide, got %d", got)
+	}
+	if !strings.Contains(legend, "mistral:7b (50 chars)") || !strings.Contains(legend, "Skeptic (10 chars)") {
+		t.Errorf("Expected per-model totals in the legend, got %q", legend)
+	}
+}
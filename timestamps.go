@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultTimeFormat is the layout used for turn timestamps: the local short time
+const defaultTimeFormat = "15:04:05"
+
+// timestampFormat controls how turn timestamps are shown in the view and in
+// exports. The zero value formats with defaultTimeFormat in local time.
+type timestampFormat struct {
+	layout   string
+	location *time.Location
+}
+
+// parseTimestampFormat validates a Go time layout and an IANA timezone name.
+// An empty timezone means local time.
+func parseTimestampFormat(layout, timezone string) (timestampFormat, error) {
+	// A layout without any reference-time elements would print the same
+	// literal text for every turn
+	reference := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	if layout == "" || reference.Add(time.Hour).Format(layout) == reference.Format(layout) {
+		return timestampFormat{}, fmt.Errorf("time format '%s' contains no time fields (use a Go layout such as 15:04:05)", layout)
+	}
+
+	f := timestampFormat{layout: layout}
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return timestampFormat{}, fmt.Errorf("unknown timezone '%s': %w", timezone, err)
+		}
+		f.location = location
+	}
+
+	return f, nil
+}
+
+// format renders t with the configured layout and timezone
+func (f timestampFormat) format(t time.Time) string {
+	layout := f.layout
+	if layout == "" {
+		layout = defaultTimeFormat
+	}
+	if f.location != nil {
+		t = t.In(f.location)
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimestampFormat_TimezoneShiftsTimestamp(t *testing.T) {
+	turn := Turn{ModelName: "mistral:7b", Content: "Hi.", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	stamps, err := parseTimestampFormat(defaultTimeFormat, "Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to parse timestamp format: %v", err)
+	}
+	if got := stamps.format(turn.Timestamp); got != "19:00:00" {
+		t.Errorf("Expected 19:00:00 in Tokyo, got %s", got)
+	}
+
+	rendered := formatTurn(turn, true, 80, defaultMinContentWidth, "", stamps)
+	if !strings.Contains(rendered, "[19:00:00]") {
+		t.Errorf("Expected the shifted timestamp in the rendered turn, got:\n%s", rendered)
+	}
+
+	var b strings.Builder
+	if err := ExportText("Topic", nil, []Turn{turn}, stamps, &b); err != nil {
+		t.Fatalf("ExportText failed: %v", err)
+	}
+	if !strings.Contains(b.String(), "[19:00:00]") {
+		t.Errorf("Expected the shifted timestamp in the export, got:\n%s", b.String())
+	}
+}
+
+func TestTimestampFormat_CustomLayout(t *testing.T) {
+	stamps, err := parseTimestampFormat("2006-01-02 15:04 MST", "UTC")
+	if err != nil {
+		t.Fatalf("Failed to parse timestamp format: %v", err)
+	}
+	got := stamps.format(time.Date(2024, 3, 9, 8, 30, 0, 0, time.UTC))
+	if got != "2024-03-09 08:30 UTC" {
+		t.Errorf("Expected 2024-03-09 08:30 UTC, got %s", got)
+	}
+}
+
+func TestParseTimestampFormat_Invalid(t *testing.T) {
+	if _, err := parseTimestampFormat("no fields here", ""); err == nil {
+		t.Errorf("Expected error for a layout without time fields")
+	}
+	if _, err := parseTimestampFormat(defaultTimeFormat, "Mars/Olympus_Mons"); err == nil {
+		t.Errorf("Expected error for an unknown timezone")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultTopicLimit is the longest topic accepted, in characters, unless
+// -topic-limit says otherwise
+const defaultTopicLimit = 200
+
+// topicHeaderLines is how many lines the topic may take in the header of
+// the debate view before it is cut short
+const topicHeaderLines = 2
+
+// checkTopicLength reports an error when topic is longer than limit
+// characters. A limit of 0 accepts any length.
+func checkTopicLength(topic string, limit int) error {
+	if n := utf8.RuneCountInString(topic); limit > 0 && n > limit {
+		return fmt.Errorf("the topic is %d characters long, more than the limit of %d (raise it with -topic-limit, or 0 for no limit)", n, limit)
+	}
+	return nil
+}
+
+// headerTopic fits topic into at most lines lines of width columns, the
+// first of which starts after a prefix indent columns wide. Line breaks and
+// runs of spaces in the topic are collapsed, and a topic that does not fit
+// ends in "…". Only the display is shortened: prompts and exports always
+// get the whole topic.
+func headerTopic(topic string, indent, width, lines int) string {
+	topic = strings.Join(strings.Fields(topic), " ")
+	if width <= 0 || lipgloss.Width(topic)+indent <= width {
+		return topic
+	}
+
+	// Wrap as if the prefix were part of the text, then drop it again
+	pad := strings.Repeat(" ", indent)
+	wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(pad+topic), "\n")
+	for i := range wrapped {
+		wrapped[i] = strings.TrimRight(wrapped[i], " ")
+	}
+	wrapped[0] = strings.TrimPrefix(wrapped[0], pad)
+	if len(wrapped) <= lines {
+		return strings.Join(wrapped, "\n")
+	}
+
+	// Make room for the ellipsis on the last line shown
+	wrapped = wrapped[:lines]
+	last := []rune(wrapped[lines-1])
+	limit := width - 1
+	if lines == 1 {
+		limit -= indent
+	}
+	for len(last) > 0 && lipgloss.Width(string(last)) > limit {
+		last = last[:len(last)-1]
+	}
+	wrapped[lines-1] = strings.TrimRight(string(last), " ") + "…"
+	return strings.Join(wrapped, "\n")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestCheckTopicLength(t *testing.T) {
+	if err := checkTopicLength(strings.Repeat("ż", 200), 200); err != nil {
+		t.Errorf("Expected a topic at the limit to be accepted, got %v", err)
+	}
+	if err := checkTopicLength(strings.Repeat("a", 201), 200); err == nil {
+		t.Error("Expected a topic over the limit to be rejected")
+	}
+	if err := checkTopicLength(strings.Repeat("a", 5000), 0); err != nil {
+		t.Errorf("Expected no limit with 0, got %v", err)
+	}
+}
+
+func TestHeaderTopic(t *testing.T) {
+	if got := headerTopic("Is remote\nwork   better?", 10, 80, 2); got != "Is remote work better?" {
+		t.Errorf("Expected a short topic on one line, got %q", got)
+	}
+
+	long := strings.Repeat("Should cities ban cars from their centres? ", 10)
+	got := headerTopic(long, 10, 40, 2)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected the topic cut to 2 lines, got %q", got)
+	}
+	if lipgloss.Width(lines[0])+10 > 40 || lipgloss.Width(lines[1]) > 40 {
+		t.Errorf("Expected the lines to fit 40 columns after the prefix, got %q", got)
+	}
+	if !strings.HasPrefix(lines[0], "Should cities") || !strings.HasSuffix(lines[1], "…") {
+		t.Errorf("Expected the start of the topic ending in an ellipsis, got %q", got)
+	}
+
+	if got := headerTopic(long, 10, 40, 1); strings.Contains(got, "\n") || lipgloss.Width(got)+10 > 40 {
+		t.Errorf("Expected a single line fitting beside the prefix, got %q", got)
+	}
+}
+
+// TestLongTopic_HeaderCutPromptWhole verifies a long topic is shortened in
+// the header but reaches the models in full
+func TestLongTopic_HeaderCutPromptWhole(t *testing.T) {
+	topic := strings.Repeat("Is remote work better for everyone involved? ", 8) + "The end."
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(testFixture()),
+		maxTurns:     1,
+		initialTopic: topic,
+	}
+	m.Init()
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 40})
+
+	view := m.View()
+	if strings.Contains(view, "The end.") || !strings.Contains(view, "…") {
+		t.Errorf("Expected the topic cut short in the header, got:\n%s", view)
+	}
+	if _, prompt := m.nextPrompt(); !strings.Contains(prompt, topic) {
+		t.Errorf("Expected the whole topic in the prompt, got:\n%s", prompt)
+	}
+}
+
+func TestTopicLimit_StopsTyping(t *testing.T) {
+	m := &debateModel{topicLimit: 5}
+	m.Init()
+	for _, r := range "abcdefgh" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := m.textInput.Value(); got != "abcde" {
+		t.Errorf("Expected typing to stop at the limit, got %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// runTournament runs the same debate n times from a fresh copy of template,
+// has judgeModel pick each winner, and returns the winning model tags in
+// order. Undecided debates are recorded as an empty string. Up to workers
+// debates run at once, or all of them when workers is zero. When a debate
+// fails, the others are cancelled and the error is returned with the winners
+// of the debates that finished before the first unfinished one.
+func runTournament(ctx context.Context, template debateModel, judgeModel string, n, turns, workers int, progress io.Writer) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	winners := make([]string, n)
+	done := make([]bool, n)
+	var mu sync.Mutex // Guards progress and failed
+	var failed error
+
+	// Zero or less runs every debate at once
+	if workers <= 0 || workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := runTournamentDebate(ctx, tournamentDebate(template, i), judgeModel, turns)
+				winner := result.Verdict
+				mu.Lock()
+				if err != nil {
+					// Keep the first failure, not the cancellations it causes
+					if failed == nil {
+						failed = fmt.Errorf("debate %d: %w", i+1, err)
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				winners[i] = winner
+				done[i] = true
+
+				if winner == "" {
+					fmt.Fprintf(progress, "Debate %d/%d: undecided\n", i+1, n)
+				} else {
+					fmt.Fprintf(progress, "Debate %d/%d: %s wins\n", i+1, n, template.displayName(winner))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failed == nil {
+		failed = ctx.Err()
+	}
+	if failed != nil {
+		completed := 0
+		for completed < n && done[completed] {
+			completed++
+		}
+		return winners[:completed], failed
+	}
+	return winners, nil
+}
+
+// tournamentDebate returns a fresh copy of template for debate i of a
+// tournament. With shuffled rounds each debate draws from its own source,
+// seeded from the tournament's seed and i, so debates running at once do
+// not share one and a seed reproduces every debate whatever the scheduling.
+func tournamentDebate(template debateModel, i int) debateModel {
+	m := template
+	m.resetDebate()
+	m.topic = template.topic
+	if template.shuffle.enabled() {
+		m.shuffle = newRoundShuffle(template.shuffle.seed + int64(i))
+	}
+	return m
+}
+
+// runTournamentDebate runs debate m of a tournament and returns its result,
+// with the winner chosen by judgeModel as the verdict
+func runTournamentDebate(ctx context.Context, m debateModel, judgeModel string, turns int) (DebateResult, error) {
+	result := runHeadless(ctx, &m, turns)
+	if result.Err != nil {
+		return result, result.Err
+	}
+	verdict, err := JudgeDebate(ctx, m.ollamaClient, judgeModel, &m)
+	if err != nil {
+		return result, err
+	}
+	result.Verdict = verdict
+	return result, nil
+}
+
+// tallyWins counts how many debates each model won. Undecided debates are
+// counted under the empty string.
+func tallyWins(winners []string) map[string]int {
+	tally := make(map[string]int)
+	for _, winner := range winners {
+		tally[winner]++
+	}
+	return tally
+}
+
+// formatTournamentSummary renders the tally of a tournament
+func formatTournamentSummary(m *debateModel, winners []string) string {
+	tally := tallyWins(winners)
+
+	summary := fmt.Sprintf("Tournament results (%d debates):\n", len(winners))
+	summary += fmt.Sprintf("  %s: %d wins\n", m.sideName(0), tally[m.model1Name])
+	summary += fmt.Sprintf("  %s: %d wins\n", m.sideName(1), tally[m.model2Name])
+	if tally[""] > 0 {
+		summary += fmt.Sprintf("  undecided: %d\n", tally[""])
+	}
+
+	return summary
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTallyWins(t *testing.T) {
+	winners := []string{"phi3:mini", "gemma3:4b", "phi3:mini", "", "phi3:mini"}
+	tally := tallyWins(winners)
+
+	if tally["phi3:mini"] != 3 || tally["gemma3:4b"] != 1 || tally[""] != 1 {
+		t.Errorf("Unexpected tally: %v", tally)
+	}
+}
+
+func TestFormatTournamentSummary(t *testing.T) {
+	m := &debateModel{model1Name: "phi3:mini", model2Name: "gemma3:4b", model2Alias: "Skeptic"}
+	summary := formatTournamentSummary(m, []string{"gemma3:4b", "gemma3:4b", ""})
+
+	for _, want := range []string{"(3 debates)", "phi3:mini: 0 wins", "Skeptic: 2 wins", "undecided: 1"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	speakers := map[string]string{"Optimist": "phi3:mini", "gemma3:4b": "gemma3:4b"}
+
+	tests := []struct {
+		response string
+		want     string
+	}{
+		{"Both argued well.\nWINNER: Optimist", "phi3:mini"},
+		{"Reasoning...\n**Winner: gemma3:4b**", "gemma3:4b"},
+		{"Reasoning...\nwinner: GEMMA3:4B.", "gemma3:4b"},
+		{"WINNER: nobody", ""},
+		{"I cannot decide.", ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseVerdict(tt.response, speakers); got != tt.want {
+			t.Errorf("parseVerdict(%q) = %q, want %q", tt.response, got, tt.want)
+		}
+	}
+}
+
+func TestRunTournament_AlternatesAndTallies(t *testing.T) {
+	var requests []GenerateRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "My point.\nWINNER: gemma3:4b", Done: true})
+	}))
+	defer server.Close()
+
+	template := debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Cats or dogs?",
+	}
+
+	var progress strings.Builder
+	winners, err := runTournament(context.Background(), template, "llama3:8b", 2, 2, 1, &progress)
+	if err != nil {
+		t.Fatalf("runTournament failed: %v", err)
+	}
+
+	if len(winners) != 2 || tallyWins(winners)["gemma3:4b"] != 2 {
+		t.Errorf("Expected gemma3:4b to win both debates, got %v", winners)
+	}
+
+	// Each debate is two turns plus a judge call
+	wantModels := []string{"phi3:mini", "gemma3:4b", "llama3:8b", "phi3:mini", "gemma3:4b", "llama3:8b"}
+	if len(requests) != len(wantModels) {
+		t.Fatalf("Expected %d requests, got %d", len(wantModels), len(requests))
+	}
+	for i, want := range wantModels {
+		if requests[i].Model != want {
+			t.Errorf("Request %d: expected model %s, got %s", i, want, requests[i].Model)
+		}
+	}
+}
+
+// TestRunTournament_Workers verifies debates run side by side up to the
+// number of workers, keeping winners in debate order
+func TestRunTournament_Workers(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "My point.\nWINNER: gemma3:4b", Done: true})
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	template := debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Cats or dogs?",
+	}
+
+	var progress strings.Builder
+	winners, err := runTournament(context.Background(), template, "llama3:8b", 6, 2, 3, &progress)
+	if err != nil {
+		t.Fatalf("runTournament failed: %v", err)
+	}
+	if len(winners) != 6 || tallyWins(winners)["gemma3:4b"] != 6 {
+		t.Errorf("Expected gemma3:4b to win all 6 debates, got %v", winners)
+	}
+	if peak > 3 {
+		t.Errorf("Expected at most 3 debates at once, got %d requests in flight", peak)
+	}
+	if strings.Count(progress.String(), "wins") != 6 {
+		t.Errorf("Expected a progress line per debate, got:\n%s", progress.String())
+	}
+}
+
+// TestRunTournament_ShuffleSeedPerDebate verifies each debate of a shuffled
+// tournament draws its openers from its own source, seeded by its index, so
+// parallel debates do not share one. Run with -race to catch sharing.
+func TestRunTournament_ShuffleSeedPerDebate(t *testing.T) {
+	template := debateModel{shuffle: newRoundShuffle(42)}
+	first, second := tournamentDebate(template, 0), tournamentDebate(template, 1)
+	if first.shuffle.rng == template.shuffle.rng || first.shuffle.rng == second.shuffle.rng {
+		t.Fatal("Expected each debate to get its own source")
+	}
+	if first.shuffle.seed != 42 || second.shuffle.seed != 43 {
+		t.Errorf("Expected seeds 42 and 43, got %d and %d", first.shuffle.seed, second.shuffle.seed)
+	}
+	if got, want := speakingOrder(&second, 20), speakingOrder(&debateModel{shuffle: newRoundShuffle(43)}, 20); got != want {
+		t.Errorf("Expected debate 1 to speak like seed 43, got %s, want %s", got, want)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "My point.\nWINNER: gemma3:4b", Done: true})
+	}))
+	defer server.Close()
+	template.model1Name, template.model2Name = "phi3:mini", "gemma3:4b"
+	template.ollamaClient = NewOllamaClient(server.URL)
+	template.topic = "Cats or dogs?"
+
+	var progress strings.Builder
+	if _, err := runTournament(context.Background(), template, "llama3:8b", 8, 4, 4, &progress); err != nil {
+		t.Fatalf("runTournament failed: %v", err)
+	}
+}
+
+func TestRunTournamentDebate_RecordsVerdict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "My point.\nWINNER: gemma3:4b", Done: true})
+	}))
+	defer server.Close()
+
+	template := debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient(server.URL),
+		topic:        "Cats or dogs?",
+	}
+	result, err := runTournamentDebate(context.Background(), tournamentDebate(template, 0), "llama3:8b", 2)
+	if err != nil {
+		t.Fatalf("runTournamentDebate failed: %v", err)
+	}
+	if result.Verdict != "gemma3:4b" || len(result.Turns) != 2 {
+		t.Errorf("Expected 2 turns and gemma3:4b's win in the result, got verdict %q and %d turns", result.Verdict, len(result.Turns))
+	}
+}
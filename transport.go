@@ -0,0 +1,59 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// NewTransport returns an HTTP transport for reaching Ollama through
+// proxyURL and trusting the PEM certificates in caFile on top of the
+// system's. An empty proxyURL keeps the proxy from the HTTP_PROXY and
+// HTTPS_PROXY environment variables; an empty caFile trusts only the
+// system's certificates.
+func NewTransport(proxyURL, caFile string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", proxyURL)
+		}
+		if proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no PEM certificates found", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return transport, nil
+}
+
+// SetTransport replaces how the client makes HTTP requests, for example
+// with a transport from NewTransport. It must be called before the client
+// makes its first request.
+func (c *OllamaClient) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewTransport_ProxyHonored verifies requests go through the configured
+// proxy rather than straight to the Ollama host
+func TestNewTransport_ProxyHonored(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		json.NewEncoder(w).Encode(map[string]interface{}{"models": []map[string]string{{"name": "mistral:7b"}}})
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(proxy.URL, "")
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	client := NewOllamaClient("http://ollama.invalid:11434")
+	client.SetTransport(transport)
+
+	models, err := client.ListModels()
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if proxied != "http://ollama.invalid:11434/api/tags" {
+		t.Errorf("Expected the proxy to receive the request for Ollama, got %q", proxied)
+	}
+	if len(models) != 1 || models[0] != "mistral:7b" {
+		t.Errorf("Expected the models the proxy returned, got %v", models)
+	}
+}
+
+// TestNewTransport_CAFile verifies an HTTPS server signed by a CA from the
+// file is trusted, and not without it
+func TestNewTransport_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[]}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o644); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+
+	untrusted := NewOllamaClient(server.URL)
+	if _, err := untrusted.ListModels(); err == nil {
+		t.Error("Expected the server to be untrusted without the CA file")
+	}
+
+	transport, err := NewTransport("", caFile)
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	trusted := NewOllamaClient(server.URL)
+	trusted.SetTransport(transport)
+	if _, err := trusted.ListModels(); err != nil {
+		t.Errorf("Expected the server to be trusted with the CA file, got %v", err)
+	}
+}
+
+func TestNewTransport_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0o644)
+
+	tests := []struct {
+		name     string
+		proxyURL string
+		caFile   string
+		want     string
+	}{
+		{"unsupported proxy scheme", "ftp://proxy:21", "", "scheme"},
+		{"proxy without host", "http://", "", "missing host"},
+		{"missing CA file", "", filepath.Join(dir, "missing.pem"), "failed to read CA file"},
+		{"CA file without certificates", "", notPEM, "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTransport(tt.proxyURL, tt.caFile)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typewriterInterval is how often the typewriter reveals more characters
+const typewriterInterval = 50 * time.Millisecond
+
+// defaultTypewriterCPS is the default reveal rate in characters per second
+const defaultTypewriterCPS = 200
+
+// typewriterTickMsg is sent when the typewriter should reveal more text
+type typewriterTickMsg struct{}
+
+// typewriter paces how much of the transcript is shown, independent of how
+// fast chunks arrive from the network. The full text is always kept in the
+// history; only the view is held back.
+type typewriter struct {
+	cps      int     // Characters revealed per second; 0 disables pacing
+	revealed int     // Characters of the transcript revealed so far
+	budget   float64 // Fractional characters carried over between ticks
+	ticking  bool    // Whether a tick is already scheduled
+}
+
+// enabled reports whether display pacing is active
+func (t *typewriter) enabled() bool {
+	return t.cps > 0
+}
+
+// advance reveals the characters earned over elapsed, never revealing more
+// than total. It returns true while text remains hidden.
+func (t *typewriter) advance(elapsed time.Duration, total int) bool {
+	t.budget += float64(t.cps) * elapsed.Seconds()
+	release := int(t.budget)
+	t.budget -= float64(release)
+
+	t.revealed += release
+	if t.revealed >= total {
+		t.revealed = total
+		t.budget = 0
+	}
+	return t.revealed < total
+}
+
+// typewriterTick returns a command that triggers the next reveal
+func typewriterTick() tea.Cmd {
+	return tea.Tick(typewriterInterval, func(time.Time) tea.Msg {
+		return typewriterTickMsg{}
+	})
+}
+
+// transcriptLength returns the number of characters across all turns
+func transcriptLength(history []Turn) int {
+	total := 0
+	for _, turn := range history {
+		total += utf8.RuneCountInString(turn.Content)
+	}
+	return total
+}
+
+// revealHistory returns the leading turns of history holding at most
+// revealed characters, truncating the last visible turn as needed
+func revealHistory(history []Turn, revealed int) []Turn {
+	var visible []Turn
+	for _, turn := range history {
+		if revealed <= 0 {
+			break
+		}
+		runes := []rune(turn.Content)
+		if len(runes) > revealed {
+			turn.Content = string(runes[:revealed])
+		}
+		revealed -= len(runes)
+		visible = append(visible, turn)
+	}
+	return visible
+}
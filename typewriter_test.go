@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTypewriter_AdvancePacing(t *testing.T) {
+	tw := typewriter{cps: 100}
+
+	// 50ms at 100 cps releases 5 characters
+	if hidden := tw.advance(50*time.Millisecond, 1000); !hidden || tw.revealed != 5 {
+		t.Errorf("Expected 5 characters revealed, got %d", tw.revealed)
+	}
+
+	// A full second releases another 100
+	tw.advance(time.Second, 1000)
+	if tw.revealed != 105 {
+		t.Errorf("Expected 105 characters revealed, got %d", tw.revealed)
+	}
+}
+
+func TestTypewriter_CarriesFractions(t *testing.T) {
+	tw := typewriter{cps: 10}
+
+	// 10 cps over 50ms earns half a character per tick
+	tw.advance(50*time.Millisecond, 100)
+	if tw.revealed != 0 {
+		t.Errorf("Expected no characters after half a character's time, got %d", tw.revealed)
+	}
+	tw.advance(50*time.Millisecond, 100)
+	if tw.revealed != 1 {
+		t.Errorf("Expected fractions to carry over into 1 character, got %d", tw.revealed)
+	}
+}
+
+func TestTypewriter_StopsAtTotal(t *testing.T) {
+	tw := typewriter{cps: 1000}
+
+	if hidden := tw.advance(time.Second, 20); hidden {
+		t.Errorf("Expected all text to be revealed")
+	}
+	if tw.revealed != 20 {
+		t.Errorf("Expected reveal to stop at 20 characters, got %d", tw.revealed)
+	}
+}
+
+func TestRevealHistory(t *testing.T) {
+	history := []Turn{
+		{ModelName: "mistral:7b", Content: "Hello"},
+		{ModelName: "gemma3:4b", Content: "Wörld!"},
+	}
+
+	visible := revealHistory(history, 8)
+	if len(visible) != 2 {
+		t.Fatalf("Expected 2 visible turns, got %d", len(visible))
+	}
+	if visible[1].Content != "Wör" {
+		t.Errorf("Expected second turn truncated to \"Wör\", got %q", visible[1].Content)
+	}
+	if history[1].Content != "Wörld!" {
+		t.Errorf("Revealing should not modify the stored history")
+	}
+
+	if visible := revealHistory(history, 5); len(visible) != 1 {
+		t.Errorf("Expected only the first turn to be visible, got %d", len(visible))
+	}
+	if transcriptLength(history) != 11 {
+		t.Errorf("Expected transcript length 11, got %d", transcriptLength(history))
+	}
+}
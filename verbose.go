@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// GenerationStats describes a finished generation, as reported on the last
+// chunk of Ollama's stream
+type GenerationStats struct {
+	DoneReason string        // Why generation stopped, such as "stop" or "length"
+	Tokens     int           // Tokens generated
+	Duration   time.Duration // Time Ollama spent on the request, loading the model included
+}
+
+// stats returns the statistics carried by a final chunk
+func (r GenerateResponse) stats() GenerationStats {
+	return GenerationStats{
+		DoneReason: r.DoneReason,
+		Tokens:     r.EvalCount,
+		Duration:   time.Duration(r.TotalDuration),
+	}
+}
+
+// SetDoneHandler sets a function called with the statistics of every
+// generation that finishes, from the goroutine streaming it. A nil handler
+// disables it.
+func (c *OllamaClient) SetDoneHandler(handler func(modelName string, stats GenerationStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onDone = handler
+}
+
+// formatVerboseLine renders a generation's statistics as a logfmt line for
+// -verbose
+func formatVerboseLine(at time.Time, modelName string, stats GenerationStats) string {
+	doneReason := stats.DoneReason
+	if doneReason == "" {
+		doneReason = "unknown"
+	}
+	return fmt.Sprintf("time=%s model=%s duration=%s tokens=%d done_reason=%s",
+		at.Format(time.RFC3339), strconv.Quote(modelName), stats.Duration.Round(time.Millisecond), stats.Tokens, doneReason)
+}
+
+// verboseLog writes -verbose lines to w. While held, because w is the
+// terminal the TUI is drawing on, lines are kept back and written on
+// release so they do not tear the display.
+type verboseLog struct {
+	mu      sync.Mutex
+	w       io.Writer
+	holding bool
+	held    []string
+}
+
+// newVerboseLog returns a log writing to w
+func newVerboseLog(w io.Writer) *verboseLog {
+	return &verboseLog{w: w}
+}
+
+// log writes line, or keeps it back while the log is held. It is safe to
+// call from any goroutine.
+func (l *verboseLog) log(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holding {
+		l.held = append(l.held, line)
+		return
+	}
+	fmt.Fprintln(l.w, line)
+}
+
+// hold keeps lines back until release
+func (l *verboseLog) hold() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holding = true
+}
+
+// release writes the lines kept back and stops holding new ones
+func (l *verboseLog) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range l.held {
+		fmt.Fprintln(l.w, line)
+	}
+	l.held = nil
+	l.holding = false
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatVerboseLine(t *testing.T) {
+	at := time.Date(2026, 10, 17, 18, 59, 17, 0, time.UTC)
+	line := formatVerboseLine(at, "phi3:mini", GenerationStats{DoneReason: "stop", Tokens: 187, Duration: 4213456789})
+	want := `time=2026-10-17T18:59:17Z model="phi3:mini" duration=4.213s tokens=187 done_reason=stop`
+	if line != want {
+		t.Errorf("Expected %q, got %q", want, line)
+	}
+
+	if line := formatVerboseLine(at, "phi3:mini", GenerationStats{}); !strings.HasSuffix(line, "done_reason=unknown") {
+		t.Errorf("Expected a missing done reason to be logged as unknown, got %q", line)
+	}
+}
+
+func TestDoneHandler_ReportsFinalChunkStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(GenerateResponse{Response: "Hello"})
+		json.NewEncoder(w).Encode(GenerateResponse{Done: true, DoneReason: "length", EvalCount: 42, TotalDuration: int64(2 * time.Second)})
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL)
+	var got GenerationStats
+	var gotModel string
+	client.SetDoneHandler(func(modelName string, stats GenerationStats) {
+		gotModel, got = modelName, stats
+	})
+	if _, err := collectResponse(context.Background(), client, "mistral:7b", "Hi", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := GenerationStats{DoneReason: "length", Tokens: 42, Duration: 2 * time.Second}
+	if gotModel != "mistral:7b" || got != want {
+		t.Errorf("Expected %+v for mistral:7b, got %+v for %q", want, got, gotModel)
+	}
+}
+
+func TestVerboseLog_HoldsLinesUntilRelease(t *testing.T) {
+	var b strings.Builder
+	log := newVerboseLog(&b)
+
+	log.log("first")
+	log.hold()
+	log.log("second")
+	if b.String() != "first\n" {
+		t.Errorf("Expected lines held back while the TUI runs, got %q", b.String())
+	}
+
+	log.release()
+	log.log("third")
+	if b.String() != "first\nsecond\nthird\n" {
+		t.Errorf("Expected held lines written on release, got %q", b.String())
+	}
+}
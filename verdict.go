@@ -0,0 +1,59 @@
+package main
+
+// Outcome is the verdict on a judged debate or tournament
+type Outcome int
+
+const (
+	OutcomeUndecided Outcome = iota // A tie, or the judge named no debater
+	OutcomeModel1                   // model1 won
+	OutcomeModel2                   // model2 won
+)
+
+// Exit codes for each outcome under -exit-on-verdict, and for a tournament
+// that failed before reaching one
+const (
+	exitModel1Wins = 0
+	exitModel2Wins = 1
+	exitUndecided  = 2
+	exitNoVerdict  = 3
+)
+
+// outcomeOf turns the winning model tag chosen by a judge into an outcome
+func outcomeOf(winner, model1, model2 string) Outcome {
+	switch {
+	case winner == "":
+		return OutcomeUndecided
+	case winner == model1:
+		return OutcomeModel1
+	case winner == model2:
+		return OutcomeModel2
+	}
+	return OutcomeUndecided
+}
+
+// tournamentOutcome decides a tournament by which model won more debates.
+// Equal wins are a tie; undecided debates count for neither.
+func tournamentOutcome(winners []string, model1, model2 string) Outcome {
+	wins := make(map[Outcome]int)
+	for _, winner := range winners {
+		wins[outcomeOf(winner, model1, model2)]++
+	}
+	switch {
+	case wins[OutcomeModel1] > wins[OutcomeModel2]:
+		return OutcomeModel1
+	case wins[OutcomeModel2] > wins[OutcomeModel1]:
+		return OutcomeModel2
+	}
+	return OutcomeUndecided
+}
+
+// exitCode returns the process exit code reporting o
+func (o Outcome) exitCode() int {
+	switch o {
+	case OutcomeModel1:
+		return exitModel1Wins
+	case OutcomeModel2:
+		return exitModel2Wins
+	}
+	return exitUndecided
+}
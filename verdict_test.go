@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestOutcomeOf(t *testing.T) {
+	tests := []struct {
+		winner string
+		want   Outcome
+	}{
+		{"phi3:mini", OutcomeModel1},
+		{"gemma3:4b", OutcomeModel2},
+		{"", OutcomeUndecided},
+		{"llama3:8b", OutcomeUndecided},
+	}
+	for _, tt := range tests {
+		if got := outcomeOf(tt.winner, "phi3:mini", "gemma3:4b"); got != tt.want {
+			t.Errorf("outcomeOf(%q) = %v, want %v", tt.winner, got, tt.want)
+		}
+	}
+}
+
+func TestTournamentOutcome_ExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		winners []string
+		want    int
+	}{
+		{"model1 wins more", []string{"phi3:mini", "gemma3:4b", "phi3:mini"}, 0},
+		{"model2 wins more", []string{"gemma3:4b", "", "gemma3:4b", "phi3:mini"}, 1},
+		{"tie", []string{"phi3:mini", "gemma3:4b"}, 2},
+		{"undecided count for neither", []string{"", "", "phi3:mini", "gemma3:4b"}, 2},
+		{"all undecided", []string{"", ""}, 2},
+		{"no debates", nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tournamentOutcome(tt.winners, "phi3:mini", "gemma3:4b").exitCode(); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOutcome_ExitCodes(t *testing.T) {
+	codes := map[Outcome]int{OutcomeModel1: 0, OutcomeModel2: 1, OutcomeUndecided: 2}
+	for outcome, want := range codes {
+		if got := outcome.exitCode(); got != want {
+			t.Errorf("Expected %v to exit with %d, got %d", outcome, want, got)
+		}
+		if got := outcome.exitCode(); got == exitNoVerdict {
+			t.Errorf("Expected %v not to share the failure exit code", outcome)
+		}
+	}
+}
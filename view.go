@@ -8,109 +8,127 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	// defaultMinContentWidth is the narrowest a turn box is allowed to wrap to
+	defaultMinContentWidth = 20
+
+	// scrollbarMargin is the room left to the right of each turn box
+	scrollbarMargin = 2
+)
+
+// The color scheme and the styles built from it, set by applyTheme from the
+// active theme
 var (
-	// Color scheme
-	model1Color = lipgloss.Color("#00BFFF") // Deep Sky Blue
-	model2Color = lipgloss.Color("#32CD32") // Lime Green
-	headerColor = lipgloss.Color("#FFD700") // Gold
-	errorColor  = lipgloss.Color("#FF6347") // Tomato Red
-	subtleColor = lipgloss.Color("#808080") // Gray
+	model1Color lipgloss.Color
+	model2Color lipgloss.Color
+	headerColor lipgloss.Color
+	errorColor  lipgloss.Color
+	subtleColor lipgloss.Color
 
 	// Styles for model1
-	model1Style = lipgloss.NewStyle().
-			Foreground(model1Color).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(model1Color).
-			Padding(0, 1).
-			MarginBottom(1)
-
-	model1LabelStyle = lipgloss.NewStyle().
-				Foreground(model1Color).
-				Bold(true)
+	model1Style      lipgloss.Style
+	model1LabelStyle lipgloss.Style
 
 	// Styles for model2
-	model2Style = lipgloss.NewStyle().
-			Foreground(model2Color).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(model2Color).
-			Padding(0, 1).
-			MarginBottom(1)
+	model2Style      lipgloss.Style
+	model2LabelStyle lipgloss.Style
 
-	model2LabelStyle = lipgloss.NewStyle().
-				Foreground(model2Color).
-				Bold(true)
+	// Styles for moderator interjections
+	moderatorStyle      lipgloss.Style
+	moderatorLabelStyle lipgloss.Style
 
 	// General styles
-	headerStyle = lipgloss.NewStyle().
-			Foreground(headerColor).
-			Bold(true).
-			Padding(1, 0)
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Bold(true)
-
-	subtleStyle = lipgloss.NewStyle().
-			Foreground(subtleColor).
-			Italic(true)
-
-	timestampStyle = lipgloss.NewStyle().
-			Foreground(subtleColor).
-			Italic(true)
+	headerStyle     lipgloss.Style
+	errorStyle      lipgloss.Style
+	subtleStyle     lipgloss.Style
+	timestampStyle  lipgloss.Style
+	badgeStyle      lipgloss.Style
+	promptPaneStyle lipgloss.Style
 )
 
-// renderInputView renders the topic input view
+// renderInputView renders the topic input view, wrapping its text to the
+// terminal width so nothing overflows on small terminals
 func (m *debateModel) renderInputView() string {
 	var b strings.Builder
+	wrap := lipgloss.NewStyle()
+	if m.width > 0 {
+		wrap = wrap.Width(m.width)
+	}
 
 	// Welcome message
-	b.WriteString(headerStyle.Render("🎭 AI Debate CLI"))
+	b.WriteString(wrap.Render(headerStyle.Render("🎭 AI Debate CLI")))
 	b.WriteString("\n\n")
 
 	// Show model names
-	b.WriteString(fmt.Sprintf("Models: %s vs %s\n\n",
-		model1LabelStyle.Render(m.model1Name),
-		model2LabelStyle.Render(m.model2Name)))
+	b.WriteString(wrap.Render(fmt.Sprintf("Models: %s vs %s",
+		model1LabelStyle.Render(m.sideName(0)),
+		model2LabelStyle.Render(m.sideName(1)))))
+	b.WriteString("\n\n")
 
 	// Render text input for topic
-	b.WriteString("Enter a debate topic:\n")
+	b.WriteString(wrap.Render("Enter a debate topic:"))
+	b.WriteString("\n")
 	b.WriteString(m.textInput.View())
 	b.WriteString("\n\n")
 
 	// Show error if any
 	if m.errorMsg != "" {
-		b.WriteString(errorStyle.Render(m.errorMsg))
+		b.WriteString(wrap.Render(errorStyle.Render(m.errorMsg)))
 		b.WriteString("\n\n")
 	}
 
 	// Instructions
-	b.WriteString(subtleStyle.Render("Press Enter to start • Ctrl+C to quit"))
+	b.WriteString(wrap.Render(subtleStyle.Render("Press Enter to start • Ctrl+C to quit")))
 
 	return b.String()
 }
 
+// textInputWidth returns the width to give the topic input so that it,
+// its prompt and the cursor fit within width
+func textInputWidth(width int, prompt string) int {
+	return max(width-lipgloss.Width(prompt)-1, 1)
+}
+
 // renderDebateView renders the active debate view
 func (m *debateModel) renderDebateView() string {
 	var b strings.Builder
 
 	// Render debate topic header
-	b.WriteString(headerStyle.Render(fmt.Sprintf("📢 Debate Topic: %s", m.topic)))
-	b.WriteString("\n\n")
-
 	// Use viewport width for content formatting
 	viewportWidth := m.viewport.Width
 	if viewportWidth == 0 {
 		viewportWidth = m.width
 	}
 
-	// Display all turns with formatting
-	for i, turn := range m.history {
-		isModel1 := turn.ModelName == m.model1Name
-		b.WriteString(formatTurn(turn, isModel1, viewportWidth))
+	// Render debate topic header, cut short when the topic is too long to
+	// leave room for the debate
+	prefix := "📢 Debate Topic: "
+	b.WriteString(headerStyle.Render(prefix + headerTopic(m.topic, lipgloss.Width(prefix), viewportWidth, topicHeaderLines)))
+	b.WriteString("\n\n")
+
+	// Display the revealed turns within the view limit, or what is coming
+	// before the first one
+	visible := m.visibleHistory()
+	if len(visible) == 0 && m.isGenerating {
+		b.WriteString(m.renderOpeningPlaceholder(viewportWidth))
+		b.WriteString("\n")
+	}
+	start, end := m.viewStart(), len(visible)
+	if m.pageTurns > 0 {
+		// Render only the current page of a long debate
+		start, end = pageBounds(m.currentPage(len(visible)), len(visible), m.pageTurns)
+		b.WriteString(m.renderPageNote(len(visible)))
+	} else {
+		b.WriteString(m.renderHiddenNote(start))
+	}
+	for i := start; i < end; i++ {
+		turn := visible[i]
+		isModel1 := m.onModel1Side(turn.ModelName)
+		b.WriteString(m.renderTurnCached(i, turn, isModel1, viewportWidth))
 		b.WriteString("\n")
 
 		// Add spacing between turns
-		if i < len(m.history)-1 {
+		if i < end-1 {
 			b.WriteString("\n")
 		}
 	}
@@ -128,7 +146,13 @@ func (m *debateModel) renderDebateView() string {
 			indicatorStyle = model2LabelStyle
 		}
 
-		b.WriteString(indicatorStyle.Render(fmt.Sprintf("💭 %s is thinking...", activeModel)))
+		if m.round != nil {
+			// Both models are answering the round at once
+			b.WriteString(fmt.Sprintf("%s %s and %s are thinking...", m.thinking.current(),
+				model1LabelStyle.Render(m.sideName(0)), model2LabelStyle.Render(m.sideName(1))))
+		} else {
+			b.WriteString(indicatorStyle.Render(fmt.Sprintf("%s %s is thinking...", m.thinking.current(), m.displayName(activeModel))))
+		}
 		b.WriteString("\n")
 	}
 
@@ -139,34 +163,156 @@ func (m *debateModel) renderDebateView() string {
 		b.WriteString("\n")
 	}
 
-	// Render viewport with scroll
+	// Show the prompt for the current turn when debugging
+	if m.showPrompt {
+		b.WriteString("\n")
+		b.WriteString(m.renderPromptPane(viewportWidth))
+		b.WriteString("\n")
+	}
+
+	// Show the raw bytes of the last chunk when diagnosing encoding issues
+	if m.debug {
+		b.WriteString("\n")
+		b.WriteString(m.renderDebugPane(viewportWidth))
+		b.WriteString("\n")
+	}
+
+	// Render viewport with scroll, leaving room for the footer however
+	// many lines it wraps to
 	m.viewport.SetContent(b.String())
+	footer := m.renderFooter()
+	if m.height > 0 {
+		m.viewport.Height = viewportHeight(m.height, m.width, footer)
+	}
+
+	return fmt.Sprintf("%s\n%s", m.viewport.View(), footer)
+}
 
-	// Footer with instructions
+// renderFooter renders the key help shown below the debate
+func (m *debateModel) renderFooter() string {
 	autoscrollStatus := "off"
 	if m.autoscroll {
 		autoscrollStatus = "on"
 	}
-	footer := subtleStyle.Render(fmt.Sprintf("Press 'a' to toggle autoscroll [%s] • 'q' or Ctrl+C to stop", autoscrollStatus))
+	pages := ""
+	if m.pageTurns > 0 {
+		pages = " • PgUp/PgDn to change page"
+	}
+	footer := subtleStyle.Render(fmt.Sprintf("%s • Press 'a' to toggle autoscroll [%s] • 'c' to continue the last turn • 's' to swap a model • 'p' to show the prompt • 't' to change theme%s • 'q' or Ctrl+C to stop",
+		formatScrollPercent(m.viewport.ScrollPercent()), autoscrollStatus, pages))
 
-	return fmt.Sprintf("%s\n%s", m.viewport.View(), footer)
+	// Ask for the model to swap in while paused
+	if m.swap != nil {
+		footer = m.renderSwapPrompt() + "\n" + footer
+	}
+
+	// Show who the judge thinks is ahead
+	if m.scoreModel != "" {
+		footer = m.renderScoreMeter() + "\n" + footer
+	}
+
+	// Warn that the transcript is incomplete, for as long as the debate runs
+	if m.outputWarning != "" {
+		footer = errorStyle.Render("⚠️  "+m.outputWarning) + "\n" + footer
+	}
+
+	// Warn that the autosaved copy is behind, until a save succeeds
+	if m.autosaveWarning != "" {
+		footer = errorStyle.Render("⚠️  "+m.autosaveWarning) + "\n" + footer
+	}
+	return footer
+}
+
+// viewportHeight returns how many lines a viewport may take in a terminal
+// height lines tall and width columns wide once chrome, such as headers and
+// footers, has been drawn around it. The chrome is measured as rendered, so
+// a long topic that wraps takes all of its lines. At least one line is left.
+func viewportHeight(height, width int, chrome ...string) int {
+	for _, c := range chrome {
+		height -= renderedHeight(c, width)
+	}
+	return max(height, 1)
+}
+
+// renderedHeight returns how many terminal lines s takes when drawn width
+// columns wide, counting each line the terminal has to wrap
+func renderedHeight(s string, width int) int {
+	if width <= 0 {
+		return lipgloss.Height(s)
+	}
+
+	lines := 0
+	for _, line := range strings.Split(s, "\n") {
+		lines += max((lipgloss.Width(line)+width-1)/width, 1)
+	}
+	return lines
+}
+
+// renderOpeningPlaceholder renders a block standing in for the opening
+// argument until its first words arrive
+func (m *debateModel) renderOpeningPlaceholder(width int) string {
+	text := fmt.Sprintf("Waiting for %s to present the opening argument...", m.sideName(m.currentTurn))
+	if m.round != nil {
+		text = fmt.Sprintf("Waiting for %s and %s to present their opening arguments...",
+			m.sideName(0), m.sideName(1))
+	}
+	return promptPaneStyle.Width(contentWidth(promptPaneStyle, width, m.minContentWidth)).Render(subtleStyle.Render(text))
+}
+
+// renderPromptPane renders the exact prompt for the current turn in a
+// bordered pane
+func (m *debateModel) renderPromptPane(width int) string {
+	title := fmt.Sprintf("🔍 Prompt for %s", m.sideName(m.currentTurn))
+	content := fmt.Sprintf("%s\n\n%s", title, strings.TrimRight(m.currentPrompt(), "\n"))
+	return promptPaneStyle.Width(contentWidth(promptPaneStyle, width, m.minContentWidth)).Render(content)
+}
+
+// formatScrollPercent formats a viewport scroll position (0.0 to 1.0) as a
+// whole percentage for the footer
+func formatScrollPercent(percent float64) string {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 1 {
+		percent = 1
+	}
+	return fmt.Sprintf("%3.0f%%", percent*100)
 }
 
 // renderStoppedView renders the stopped debate view
 func (m *debateModel) renderStoppedView() string {
 	var b strings.Builder
 
-	// Show stop confirmation message
-	b.WriteString(headerStyle.Render("🛑 Debate Stopped"))
+	// Show stop confirmation message, or why the debate ended on its own
+	if m.endReason != "" {
+		b.WriteString(headerStyle.Render(m.endReason))
+	} else {
+		b.WriteString(headerStyle.Render("🛑 Debate Stopped"))
+	}
 	b.WriteString("\n\n")
 
+	// Show the error that ended the debate, if any
+	if m.errorMsg != "" {
+		b.WriteString(errorStyle.Render(m.errorMsg))
+		b.WriteString("\n\n")
+	}
+
 	// Display final debate history
 	b.WriteString(subtleStyle.Render(fmt.Sprintf("Topic: %s", m.topic)))
 	b.WriteString("\n\n")
 
-	for i, turn := range m.history {
-		isModel1 := turn.ModelName == m.model1Name
-		b.WriteString(formatTurn(turn, isModel1, m.width))
+	// Show the rhythm of the debate at a glance
+	if m.showTimeline && len(m.history) > 0 {
+		b.WriteString(m.renderTimeline(m.exportHistory(), m.width))
+		b.WriteString("\n\n")
+	}
+
+	start := m.viewStart()
+	b.WriteString(m.renderHiddenNote(start))
+	for i := start; i < len(m.history); i++ {
+		turn := m.history[i]
+		isModel1 := m.onModel1Side(turn.ModelName)
+		b.WriteString(m.formatTurn(turn, isModel1, m.width, m.turnBadge(i)))
 		b.WriteString("\n")
 
 		// Add spacing between turns
@@ -178,36 +324,27 @@ func (m *debateModel) renderStoppedView() string {
 	// Yank debate to clipboard
 	m.yankDebateToClipboard()
 
-	// Provide exit instructions
-	b.WriteString("\n\n")
-	b.WriteString(subtleStyle.Render("✓ Debate copied to clipboard"))
-	b.WriteString("\n")
-	b.WriteString(subtleStyle.Render("Press any key to exit"))
+	// Show the transcript in the viewport so it can still be scrolled,
+	// leaving room for the exit instructions
+	autoscrollStatus := "off"
+	if m.autoscroll {
+		autoscrollStatus = "on"
+	}
+	footer := subtleStyle.Render("✓ Debate copied to clipboard") + "\n" +
+		subtleStyle.Render(fmt.Sprintf("%s • ↑/↓ or PgUp/PgDn to scroll • 'a' to toggle autoscroll [%s] • 'r' for a new topic • any other key to exit",
+			formatScrollPercent(m.viewport.ScrollPercent()), autoscrollStatus))
+	if m.height > 0 {
+		m.viewport.Height = viewportHeight(m.height, m.width, footer)
+	}
+	m.viewport.SetContent(b.String())
 
-	return b.String()
+	return fmt.Sprintf("%s\n%s", m.viewport.View(), footer)
 }
 
 // yankDebateToClipboard copies all messages with model names to the clipboard
 func (m *debateModel) yankDebateToClipboard() {
 	var b strings.Builder
-
-	// Add topic header
-	b.WriteString(fmt.Sprintf("Debate Topic: %s\n", m.topic))
-	b.WriteString(strings.Repeat("=", 80))
-	b.WriteString("\n\n")
-
-	// Add all turns with model names
-	for i, turn := range m.history {
-		timestamp := turn.Timestamp.Format("15:04:05")
-		b.WriteString(fmt.Sprintf("[%s] %s:\n", timestamp, turn.ModelName))
-		b.WriteString(turn.Content)
-		b.WriteString("\n")
-
-		// Add spacing between turns
-		if i < len(m.history)-1 {
-			b.WriteString("\n")
-		}
-	}
+	_ = ExportText(m.topic, m.exportMeta(), m.exportHistory(), m.timestamps, &b)
 
 	// Copy to clipboard
 	_ = clipboard.WriteAll(b.String())
@@ -228,9 +365,12 @@ func (m *debateModel) renderErrorView() string {
 		b.WriteString(subtleStyle.Render(fmt.Sprintf("Topic: %s", m.topic)))
 		b.WriteString("\n\n")
 
-		for i, turn := range m.history {
-			isModel1 := turn.ModelName == m.model1Name
-			b.WriteString(formatTurn(turn, isModel1, m.width))
+		start := m.viewStart()
+		b.WriteString(m.renderHiddenNote(start))
+		for i := start; i < len(m.history); i++ {
+			turn := m.history[i]
+			isModel1 := m.onModel1Side(turn.ModelName)
+			b.WriteString(m.formatTurn(turn, isModel1, m.width, m.turnBadge(i)))
 			b.WriteString("\n")
 
 			// Add spacing between turns
@@ -242,23 +382,106 @@ func (m *debateModel) renderErrorView() string {
 	}
 
 	// Provide recovery or exit options
-	b.WriteString(subtleStyle.Render("Press 'q' to exit"))
+	b.WriteString(subtleStyle.Render(fmt.Sprintf("Press 'r' to retry %s's turn • 's' to swap a model • 'q' to exit", m.sideName(m.currentTurn))))
 
 	return b.String()
 }
 
-// formatTurn formats a single turn for display
-func formatTurn(turn Turn, isModel1 bool, width int) string {
+// renderHiddenNote returns a line noting how many earlier turns the view
+// limit hides, or nothing when none are
+func (m *debateModel) renderHiddenNote(hidden int) string {
+	if hidden == 0 {
+		return ""
+	}
+	noun := "turns"
+	if hidden == 1 {
+		noun = "turn"
+	}
+	return subtleStyle.Render(fmt.Sprintf("⋯ %d earlier %s not shown", hidden, noun)) + "\n\n"
+}
+
+// turnBadge returns the badge to show next to the turn at index i, if any
+func (m *debateModel) turnBadge(i int) string {
+	var badges []string
+	if m.history[i].Flagged {
+		badges = append(badges, "🚩 filtered")
+	}
+	if m.highlightClashes && i > 0 && detectClash(m.history[i-1], m.history[i]) {
+		badges = append(badges, "⚔️ direct rebuttal")
+	}
+	return strings.Join(badges, " ")
+}
+
+// renderedTurn is a formatted turn along with the inputs it was rendered from
+type renderedTurn struct {
+	turn     Turn
+	isModel1 bool
+	width    int
+	minWidth int
+	badge    string
+	output   string
+}
+
+// renderTurnCached formats the turn at index i, reusing the previous
+// rendering when nothing that affects it has changed. While streaming, only
+// the turn receiving chunks changes, so earlier turns are not re-wrapped on
+// every chunk.
+func (m *debateModel) renderTurnCached(i int, turn Turn, isModel1 bool, width int) string {
+	badge := m.turnBadge(i)
+
+	if i < len(m.turnCache) {
+		cached := m.turnCache[i]
+		if cached.turn == turn && cached.isModel1 == isModel1 && cached.width == width &&
+			cached.minWidth == m.minContentWidth && cached.badge == badge {
+			return cached.output
+		}
+	}
+
+	entry := renderedTurn{
+		turn:     turn,
+		isModel1: isModel1,
+		width:    width,
+		minWidth: m.minContentWidth,
+		badge:    badge,
+		output:   m.formatTurn(turn, isModel1, width, badge),
+	}
+	for len(m.turnCache) <= i {
+		m.turnCache = append(m.turnCache, renderedTurn{})
+	}
+	m.turnCache[i] = entry
+
+	return entry.output
+}
+
+// formatTurn formats a turn with the model's width settings and -color-by
+// coloring. With a maximum width set, wider terminals get a box capped at
+// that width and centered.
+func (m *debateModel) formatTurn(turn Turn, isModel1 bool, width int, badge string) string {
+	isModel1 = m.usesModel1Colors(turn, isModel1)
+	if m.maxContentWidth <= 0 || width-scrollbarMargin <= m.maxContentWidth {
+		return formatTurn(turn, isModel1, width, m.minContentWidth, badge, m.timestamps)
+	}
+
+	rendered := formatTurn(turn, isModel1, m.maxContentWidth+scrollbarMargin, m.minContentWidth, badge, m.timestamps)
+	return lipgloss.PlaceHorizontal(width-scrollbarMargin, lipgloss.Center, rendered)
+}
+
+// formatTurn formats a single turn for display. A non-empty badge is shown
+// next to the timestamp.
+func formatTurn(turn Turn, isModel1 bool, width, minWidth int, badge string, stamps timestampFormat) string {
 	var b strings.Builder
 
 	// Format timestamp
-	timestamp := turn.Timestamp.Format("15:04:05")
+	timestamp := stamps.format(turn.Timestamp)
 
 	// Choose style based on model
 	var labelStyle lipgloss.Style
 	var contentStyle lipgloss.Style
 
-	if isModel1 {
+	if turn.Moderator {
+		labelStyle = moderatorLabelStyle
+		contentStyle = moderatorStyle
+	} else if isModel1 {
 		labelStyle = model1LabelStyle
 		contentStyle = model1Style
 	} else {
@@ -266,22 +489,81 @@ func formatTurn(turn Turn, isModel1 bool, width int) string {
 		contentStyle = model2Style
 	}
 
+	// Set a thesis apart from the arguments that defend it. The shared
+	// style is copied first, since a lipgloss style's setters would
+	// otherwise change it for every later turn too.
+	if turn.Thesis {
+		contentStyle = contentStyle.Copy().Bold(true)
+		if renderMode != renderPlain {
+			contentStyle = contentStyle.BorderStyle(lipgloss.ThickBorder())
+		}
+		badge = strings.TrimSpace("📌 thesis " + badge)
+	}
+
 	// Add model name label with timestamp
-	b.WriteString(labelStyle.Render(turn.ModelName))
+	b.WriteString(labelStyle.Render(turn.Speaker()))
 	b.WriteString(" ")
 	b.WriteString(timestampStyle.Render(fmt.Sprintf("[%s]", timestamp)))
+	if badge != "" {
+		b.WriteString(" ")
+		b.WriteString(badgeStyle.Render(badge))
+	}
 	b.WriteString("\n")
 
-	// Calculate available width for content (accounting for border and padding)
-	// Border takes 2 chars (left + right), padding takes 2 chars (1 on each side)
-	// Also leave some margin for the viewport scrollbar
-	contentWidth := width - 6
-	if contentWidth < 20 {
-		contentWidth = 20 // Minimum width
-	}
+	// Calculate available width for content from the style's actual frame
+	cw := contentWidth(contentStyle, width, minWidth)
 
-	// Format content with proper wrapping and width constraint
-	b.WriteString(contentStyle.Width(contentWidth).Render(turn.Content))
+	// Format content with proper wrapping and width constraint, noting a
+	// turn that ran over length and why a recorded failure failed
+	content := normalizeParagraphs(turn.Content)
+	if turn.OverLength {
+		content += " " + overLengthNote
+	}
+	if note := turn.failureNote(); note != "" {
+		if content != "" {
+			content += "\n\n"
+		}
+		content += note
+	}
+	b.WriteString(contentStyle.Width(cw).Render(content))
 
 	return b.String()
 }
+
+// contentWidth returns the width to give a turn style so that its rendered box,
+// border and margins included, fits within width minus the scrollbar margin.
+// lipgloss counts padding inside the style width, so only the border and
+// margins are subtracted. The result is never less than minWidth (or 1).
+func contentWidth(style lipgloss.Style, width, minWidth int) int {
+	w := width - horizontalBorderSize(style) - style.GetHorizontalMargins() - scrollbarMargin
+	if w < minWidth {
+		w = minWidth
+	}
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// horizontalBorderSize returns the combined width of a style's left and right
+// borders. lipgloss draws every side when a border is set without naming any
+// sides, but its getters report those implicit sides as absent, so that case
+// is handled here.
+func horizontalBorderSize(style lipgloss.Style) int {
+	border, top, right, bottom, left := style.GetBorder()
+	if border == (lipgloss.Border{}) {
+		return 0
+	}
+	if !top && !right && !bottom && !left {
+		left, right = true, true
+	}
+
+	size := 0
+	if left {
+		size += border.GetLeftSize()
+	}
+	if right {
+		size += border.GetRightSize()
+	}
+	return size
+}
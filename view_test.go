@@ -0,0 +1,353 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestContentWidth_MatchesRenderedBox verifies that the computed content width
+// produces a box whose rendered width fits the terminal for different borders
+func TestContentWidth_MatchesRenderedBox(t *testing.T) {
+	styles := map[string]lipgloss.Style{
+		"rounded":  lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).Padding(0, 1),
+		"thick":    lipgloss.NewStyle().BorderStyle(lipgloss.ThickBorder()).Padding(0, 2),
+		"none":     lipgloss.NewStyle().Padding(0, 1),
+		"left":     lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true),
+		"margined": lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).Margin(0, 3),
+	}
+
+	for name, style := range styles {
+		t.Run(name, func(t *testing.T) {
+			width := 80
+			cw := contentWidth(style, width, defaultMinContentWidth)
+
+			rendered := lipgloss.Width(style.Width(cw).Render("some debate content"))
+			if rendered != width-scrollbarMargin {
+				t.Errorf("Expected rendered box width %d, got %d", width-scrollbarMargin, rendered)
+			}
+		})
+	}
+}
+
+// TestContentWidth_Minimum verifies narrow terminals fall back to the minimum width
+func TestContentWidth_Minimum(t *testing.T) {
+	style := lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder())
+
+	if cw := contentWidth(style, 10, 20); cw != 20 {
+		t.Errorf("Expected minimum width 20, got %d", cw)
+	}
+
+	if cw := contentWidth(style, 0, 0); cw != 1 {
+		t.Errorf("Expected width to be clamped to 1, got %d", cw)
+	}
+}
+
+func TestFormatScrollPercent(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{0, "  0%"},
+		{0.424, " 42%"},
+		{0.5, " 50%"},
+		{1, "100%"},
+		{-0.2, "  0%"},
+		{1.7, "100%"},
+	}
+
+	for _, tt := range tests {
+		if got := formatScrollPercent(tt.percent); got != tt.want {
+			t.Errorf("formatScrollPercent(%v) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+}
+
+// TestScrollPercent_TracksViewport verifies the footer reflects scrolling
+func TestScrollPercent_TracksViewport(t *testing.T) {
+	m := &debateModel{
+		model1Name: "mistral:7b",
+		model2Name: "gemma3:4b",
+		state:      stateDebating,
+		topic:      "Tabs or spaces?",
+		viewport:   viewport.New(80, 5),
+		width:      80,
+	}
+	for i := 0; i < 10; i++ {
+		m.history = append(m.history, Turn{ModelName: "mistral:7b", Content: "An argument."})
+	}
+
+	if view := m.renderDebateView(); !strings.Contains(view, "  0%") {
+		t.Errorf("Expected footer to show 0%% at the top")
+	}
+
+	m.viewport.GotoBottom()
+	if view := m.renderDebateView(); !strings.Contains(view, "100%") {
+		t.Errorf("Expected footer to show 100%% at the bottom")
+	}
+}
+
+// TestRenderTurnCached_ReusesUnchangedTurns verifies that completed turns are
+// not re-rendered while another turn streams in
+func TestRenderTurnCached_ReusesUnchangedTurns(t *testing.T) {
+	m := &debateModel{
+		model1Name: "mistral:7b",
+		model2Name: "gemma3:4b",
+		state:      stateDebating,
+		viewport:   viewport.New(80, 20),
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Finished turn."},
+			{ModelName: "gemma3:4b", Content: "Streaming"},
+		},
+	}
+
+	first := m.renderDebateView()
+	if len(m.turnCache) != 2 {
+		t.Fatalf("Expected 2 cached turns, got %d", len(m.turnCache))
+	}
+
+	// Mark the finished turn's cache entry so reuse is observable
+	m.turnCache[0].output = "CACHED"
+	m.history[1].Content += " more text"
+
+	view := m.renderDebateView()
+	if !strings.Contains(view, "CACHED") {
+		t.Errorf("Expected the unchanged turn to come from the cache")
+	}
+	if !strings.Contains(view, "more text") {
+		t.Errorf("Expected the streaming turn to be re-rendered")
+	}
+
+	// A width change invalidates every entry
+	m.viewport.Width = 60
+	view = m.renderDebateView()
+	if strings.Contains(view, "CACHED") {
+		t.Errorf("Expected a width change to re-render cached turns")
+	}
+	if first == view {
+		t.Errorf("Expected output to change with the new width and content")
+	}
+}
+
+// TestRenderPromptPane_ShowsTopicAndHistory verifies that the debug pane shows
+// the prompt built from the current debate state
+func TestRenderPromptPane_ShowsTopicAndHistory(t *testing.T) {
+	m := &debateModel{
+		model1Name:  "mistral:7b",
+		model2Name:  "gemma3:4b",
+		state:       stateDebating,
+		topic:       "Tabs or spaces?",
+		currentTurn: 1,
+		viewport:    viewport.New(120, 40),
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "Tabs respect user preferences."},
+		},
+	}
+
+	if view := m.renderDebateView(); strings.Contains(view, "Prompt for") {
+		t.Errorf("Expected the prompt pane to be hidden by default")
+	}
+
+	m.showPrompt = true
+	pane := m.renderPromptPane(120)
+	for _, want := range []string{"Prompt for gemma3:4b", "Tabs or spaces?", "Tabs respect user preferences."} {
+		if !strings.Contains(pane, want) {
+			t.Errorf("Expected prompt pane to contain %q, got:\n%s", want, pane)
+		}
+	}
+
+	// While generating, the pane shows the prompt that was actually sent
+	m.isGenerating = true
+	m.lastPrompt = "the prompt in flight"
+	if pane := m.renderPromptPane(120); !strings.Contains(pane, "the prompt in flight") {
+		t.Errorf("Expected the in-flight prompt while generating, got:\n%s", pane)
+	}
+}
+
+// TestViewTurns_ScopesEachConsumer verifies the view limit only affects the
+// view, while prompts and exports keep every turn
+func TestViewTurns_ScopesEachConsumer(t *testing.T) {
+	m := &debateModel{
+		model1Name: "mistral:7b",
+		model2Name: "gemma3:4b",
+		topic:      "Tabs or spaces?",
+		width:      80,
+		viewTurns:  2,
+		history: []Turn{
+			{ModelName: "mistral:7b", Content: "first argument"},
+			{ModelName: "gemma3:4b", Content: "second argument"},
+			{ModelName: "mistral:7b", Content: "third argument"},
+		},
+	}
+	m.viewport = viewport.New(80, 20)
+
+	view := m.renderDebateView()
+	if strings.Contains(view, "first argument") {
+		t.Errorf("Expected the oldest turn to be hidden from the view")
+	}
+	if !strings.Contains(view, "second argument") || !strings.Contains(view, "third argument") {
+		t.Errorf("Expected the last two turns in the view")
+	}
+	if !strings.Contains(view, "1 earlier turn not shown") {
+		t.Errorf("Expected a note about the hidden turn")
+	}
+
+	if got := len(m.promptHistory()); got != 3 {
+		t.Errorf("Expected prompts to use all 3 turns, got %d", got)
+	}
+	if got := len(m.exportHistory()); got != 3 {
+		t.Errorf("Expected exports to use all 3 turns, got %d", got)
+	}
+
+	// Without a limit the view shows everything
+	m.viewTurns = 0
+	if !strings.Contains(m.renderDebateView(), "first argument") {
+		t.Errorf("Expected every turn in the view without a limit")
+	}
+}
+
+// TestRenderDebateView_OpeningPlaceholder verifies the view stands in for
+// the opening argument until the first turn arrives
+func TestRenderDebateView_OpeningPlaceholder(t *testing.T) {
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		model1Alias:  "Optimist",
+		topic:        "Should homework be banned?",
+		state:        stateDebating,
+		isGenerating: true,
+		viewport:     viewport.New(80, 20),
+	}
+
+	if view := m.renderDebateView(); !strings.Contains(view, "Waiting for Optimist to present the opening argument...") {
+		t.Errorf("Expected the opening-argument placeholder with empty history, got:\n%s", view)
+	}
+
+	m.history = []Turn{{ModelName: "phi3:mini", Content: "Homework builds habits."}}
+	if view := m.renderDebateView(); strings.Contains(view, "Waiting for") {
+		t.Errorf("Expected the placeholder to go once the first turn arrives, got:\n%s", view)
+	}
+}
+
+// TestFormatTurn_MaxWidth verifies a maximum width caps turn boxes on any
+// terminal, centering them when there is room to spare
+func TestFormatTurn_MaxWidth(t *testing.T) {
+	m := &debateModel{minContentWidth: defaultMinContentWidth, maxContentWidth: 60}
+	turn := Turn{ModelName: "phi3:mini", Content: strings.Repeat("a long argument ", 40)}
+
+	for _, width := range []int{30, 62, 80, 120, 300} {
+		rendered := m.formatTurn(turn, true, width, "")
+		for _, line := range strings.Split(rendered, "\n") {
+			if w := lipgloss.Width(strings.TrimSpace(line)); w > m.maxContentWidth {
+				t.Errorf("width %d: line is %d wide, more than the maximum %d", width, w, m.maxContentWidth)
+			}
+		}
+		if w := lipgloss.Width(rendered); w > max(width-scrollbarMargin, m.maxContentWidth) {
+			t.Errorf("width %d: rendered turn is %d wide", width, w)
+		}
+	}
+
+	rendered := m.formatTurn(turn, true, 120, "")
+	lines := strings.Split(rendered, "\n")
+	if indent := len(lines[1]) - len(strings.TrimLeft(lines[1], " ")); indent != (120-scrollbarMargin-m.maxContentWidth)/2 {
+		t.Errorf("Expected the box to be centered with an indent of %d, got %d", (120-scrollbarMargin-m.maxContentWidth)/2, indent)
+	}
+
+	m.maxContentWidth = 0
+	if w := lipgloss.Width(m.formatTurn(turn, true, 120, "")); w != 120-scrollbarMargin {
+		t.Errorf("Expected the full width %d without a maximum, got %d", 120-scrollbarMargin, w)
+	}
+}
+
+// TestViewportHeight verifies the viewport gets the lines its header and
+// footer leave, counting lines the terminal wraps
+func TestViewportHeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		chrome []string
+		want   int
+	}{
+		{"no chrome", nil, 24},
+		{"one-line footer", []string{"footer"}, 23},
+		{"multi-line header", []string{"\nHeader\n", "footer"}, 20},
+		{"wrapped header", []string{strings.Repeat("x", 100), "footer"}, 20},
+		{"taller than the terminal", []string{strings.Repeat("line\n", 30)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := viewportHeight(24, 40, tt.chrome...); got != tt.want {
+				t.Errorf("viewportHeight = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompareView_LongTopicFits verifies a topic long enough to wrap the
+// header does not push the view past the bottom of the terminal
+func TestCompareView_LongTopicFits(t *testing.T) {
+	m := &compareModel{topic: strings.Repeat("Should homework be banned? ", 10), width: 60, height: 20}
+	m.viewport = viewport.New(60, 15)
+	m.viewport.SetContent(strings.Repeat("answer\n", 50))
+
+	if lines := renderedHeight(m.View(), m.width); lines > m.height {
+		t.Errorf("Expected the view to fit in %d lines, got %d", m.height, lines)
+	}
+}
+
+// TestInputView_Resize verifies the topic input follows the terminal width
+// and the input view never overflows a small terminal
+func TestInputView_Resize(t *testing.T) {
+	m := &debateModel{model1Name: "phi3:mini", model2Name: "gemma3:4b", errorMsg: "No models could be reached on this machine right now"}
+	m.Init()
+
+	m.Update(tea.WindowSizeMsg{Width: 30, Height: 20})
+	if want := 30 - lipgloss.Width(m.textInput.Prompt) - 1; m.textInput.Width != want {
+		t.Errorf("Expected the text input width %d after resizing, got %d", want, m.textInput.Width)
+	}
+	m.textInput.SetValue(strings.Repeat("topic ", 20))
+	for _, line := range strings.Split(m.renderInputView(), "\n") {
+		if w := lipgloss.Width(line); w > 30 {
+			t.Errorf("Expected lines within 30 columns, got %d: %q", w, line)
+		}
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if want := 120 - lipgloss.Width(m.textInput.Prompt) - 1; m.textInput.Width != want {
+		t.Errorf("Expected the text input to grow to %d, got %d", want, m.textInput.Width)
+	}
+
+	if got := textInputWidth(2, "> "); got != 1 {
+		t.Errorf("Expected a width of at least 1 on a tiny terminal, got %d", got)
+	}
+}
+
+// TestDebateView_UsesSizeFromBeforeStart verifies a window size that
+// arrives while the topic is typed sizes the debate once it starts
+func TestDebateView_UsesSizeFromBeforeStart(t *testing.T) {
+	m := &debateModel{
+		model1Name:   "phi3:mini",
+		model2Name:   "gemma3:4b",
+		ollamaClient: NewOllamaClient("http://127.0.0.1:1"),
+		generator:    newFixtureGenerator(testFixture()),
+	}
+	m.Init()
+
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.textInput.SetValue("Is remote work better?")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	defer m.shutdown()
+
+	if m.state != stateDebating {
+		t.Fatalf("Expected the debate to start, got state %v", m.state)
+	}
+	if m.viewport.Width != 120 {
+		t.Errorf("Expected the viewport 120 columns wide, got %d", m.viewport.Width)
+	}
+	if want := viewportHeight(40, 120, m.renderFooter()); m.viewport.Height != want {
+		t.Errorf("Expected the viewport %d lines high, got %d", want, m.viewport.Height)
+	}
+}
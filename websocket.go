@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to the client's key during the handshake (RFC 6455)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// websocketWriteTimeout bounds how long a slow client can hold up a broadcast
+const websocketWriteTimeout = 5 * time.Second
+
+// Websocket frame opcodes
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// websocketSink broadcasts debate events as JSON text messages to every
+// connected websocket client. It is an http.Handler that upgrades each
+// request to a websocket connection.
+type websocketSink struct {
+	mu        sync.Mutex
+	clients   map[*websocketClient]struct{}
+	connected chan struct{} // Closed when the first client connects
+	once      sync.Once
+}
+
+// websocketClient is one connected websocket peer
+type websocketClient struct {
+	conn net.Conn
+	mu   sync.Mutex // Serializes frame writes
+}
+
+// newWebsocketSink returns a sink with no clients
+func newWebsocketSink() *websocketSink {
+	return &websocketSink{
+		clients:   make(map[*websocketClient]struct{}),
+		connected: make(chan struct{}),
+	}
+}
+
+// ServeHTTP performs the websocket handshake and registers the client
+func (s *websocketSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "expected a websocket upgrade", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket upgrade not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return
+	}
+
+	client := &websocketClient{conn: conn}
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+	s.once.Do(func() { close(s.connected) })
+
+	go s.readLoop(client, rw.Reader)
+}
+
+// Send broadcasts event to every client, dropping clients that cannot be
+// written to
+func (s *websocketSink) Send(event DebateEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	clients := make([]*websocketClient, 0, len(s.clients))
+	for client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.mu.Unlock()
+
+	for _, client := range clients {
+		if err := client.writeFrame(opText, payload); err != nil {
+			s.remove(client)
+		}
+	}
+}
+
+// Close sends a close frame to every client and disconnects them
+func (s *websocketSink) Close() {
+	s.mu.Lock()
+	clients := s.clients
+	s.clients = make(map[*websocketClient]struct{})
+	s.mu.Unlock()
+
+	for client := range clients {
+		_ = client.writeFrame(opClose, nil)
+		client.conn.Close()
+	}
+}
+
+// clientCount returns the number of connected clients
+func (s *websocketSink) clientCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// remove disconnects a client
+func (s *websocketSink) remove(client *websocketClient) {
+	s.mu.Lock()
+	delete(s.clients, client)
+	s.mu.Unlock()
+	client.conn.Close()
+}
+
+// readLoop handles control frames from a client until it disconnects.
+// Messages from clients are ignored; the stream only flows outward.
+func (s *websocketSink) readLoop(client *websocketClient, r *bufio.Reader) {
+	defer s.remove(client)
+
+	for {
+		opcode, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opClose:
+			_ = client.writeFrame(opClose, nil)
+			return
+		case opPing:
+			if err := client.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeFrame writes a single unfragmented, unmasked frame as servers do
+func (c *websocketClient) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+	_, err := c.conn.Write(encodeFrame(opcode, payload, nil))
+	return err
+}
+
+// encodeFrame builds a final frame carrying payload. Frames sent by clients
+// must be masked with a 4-byte mask; server frames pass a nil mask.
+func encodeFrame(opcode byte, payload []byte, mask []byte) []byte {
+	frame := []byte{0x80 | opcode}
+
+	var maskBit byte
+	if mask != nil {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if mask == nil {
+		return append(frame, payload...)
+	}
+
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+// readFrame reads one frame and returns its opcode and unmasked payload.
+// Fragmented messages are not reassembled since clients only send control
+// frames to this server.
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > 1<<20 {
+		return 0, nil, errors.New("websocket frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return opcode, payload, nil
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept value for a client key
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains reports whether a comma-separated header lists token,
+// ignoring case
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// serveDebate runs m headlessly once the first websocket client connects on
+// addr, broadcasting its progress to every client. It returns when the
+// debate ends.
+func serveDebate(ctx context.Context, m *debateModel, addr string, turns int, progress io.Writer) error {
+	sink := newWebsocketSink()
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: sink}
+	go server.Serve(listener)
+	defer server.Close()
+
+	fmt.Fprintf(progress, "Waiting for a websocket client on ws://%s ...\n", listener.Addr())
+	select {
+	case <-sink.connected:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	m.sink = sink
+	err = runHeadless(ctx, m, turns).Err
+	if err == nil {
+		sink.Send(DebateEvent{Type: eventDone, Topic: m.topic, Content: m.endReason, Timestamp: time.Now()})
+	}
+	sink.Close()
+
+	return err
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dialWebsocket connects to a test server and completes the handshake
+func dialWebsocket(t *testing.T, server *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request := "GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("Failed to send handshake: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("Failed to read handshake response: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected 101 Switching Protocols, got %d", resp.StatusCode)
+	}
+	// The value from the RFC 6455 example handshake
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Unexpected Sec-WebSocket-Accept %q", got)
+	}
+
+	return conn, r
+}
+
+// readEvent reads one text frame and decodes it as a DebateEvent
+func readEvent(t *testing.T, conn net.Conn, r *bufio.Reader) DebateEvent {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	opcode, payload, err := readFrame(r)
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	if opcode != opText {
+		t.Fatalf("Expected a text frame, got opcode %d", opcode)
+	}
+
+	var event DebateEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Failed to decode event %s: %v", payload, err)
+	}
+	return event
+}
+
+// waitForClients waits until the sink has registered n clients
+func waitForClients(t *testing.T, sink *websocketSink, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for sink.clientCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d clients, have %d", n, sink.clientCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDebateEvent_JSON(t *testing.T) {
+	m := &debateModel{model1Name: "mistral:7b", model1Alias: "Optimist", topic: "Tabs or spaces?"}
+	turn := Turn{ModelName: "mistral:7b", DisplayName: "Optimist", Content: "Tabs.", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	payload, err := json.Marshal(m.turnEvent(turn))
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+	want := `{"type":"turn","topic":"Tabs or spaces?","model":"mistral:7b","speaker":"Optimist","content":"Tabs.","timestamp":"2024-01-01T10:00:00Z"}`
+	if string(payload) != want {
+		t.Errorf("Expected %s, got %s", want, payload)
+	}
+}
+
+func TestWebsocketSink_BroadcastsToClients(t *testing.T) {
+	sink := newWebsocketSink()
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	conn1, r1 := dialWebsocket(t, server)
+	conn2, r2 := dialWebsocket(t, server)
+	waitForClients(t, sink, 2)
+
+	m := &debateModel{model1Name: "mistral:7b", topic: "Tabs or spaces?"}
+	sink.Send(m.chunkEvent("mistral:7b", strings.Repeat("x", 200)))
+
+	for _, c := range []struct {
+		conn net.Conn
+		r    *bufio.Reader
+	}{{conn1, r1}, {conn2, r2}} {
+		event := readEvent(t, c.conn, c.r)
+		if event.Type != eventChunk || event.Model != "mistral:7b" || len(event.Content) != 200 {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+}
+
+func TestWebsocketSink_DropsDisconnectedClients(t *testing.T) {
+	sink := newWebsocketSink()
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	conn1, _ := dialWebsocket(t, server)
+	conn2, r2 := dialWebsocket(t, server)
+	waitForClients(t, sink, 2)
+
+	// A client that says goodbye is removed
+	conn1.Write(encodeFrame(opClose, nil, []byte{1, 2, 3, 4}))
+	waitForClients(t, sink, 1)
+
+	// The remaining client still receives events
+	m := &debateModel{model2Name: "gemma3:4b", topic: "Tabs or spaces?"}
+	sink.Send(m.errorEvent("gemma3:4b", errors.New("connection reset")))
+	if event := readEvent(t, conn2, r2); event.Type != eventError || event.Error != "connection reset" {
+		t.Errorf("Unexpected event %+v", event)
+	}
+}
+
+func TestWebsocketSink_RejectsPlainHTTP(t *testing.T) {
+	server := httptest.NewServer(newWebsocketSink())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a non-websocket request, got %d", resp.StatusCode)
+	}
+}